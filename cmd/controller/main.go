@@ -24,8 +24,10 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"k8s.io/client-go/rest"
@@ -53,6 +55,26 @@ func main() {
 		}
 	}()
 
+	// The Blueprint validating webhook is only served if its certificate is
+	// mounted.
+	certFile := filepath.Join(handler.WebhookCertDir, "tls.crt")
+	keyFile := filepath.Join(handler.WebhookCertDir, "tls.key")
+	if _, err := os.Stat(certFile); err == nil {
+		ws := handler.NewWebhookServer()
+		defer func() {
+			if err := ws.Shutdown(ctx); err != nil {
+				log.WithError(err).Print("Failed to shutdown webhook server")
+			}
+		}()
+		go func() {
+			if err := ws.ListenAndServeTLS(certFile, keyFile); err != nil && err != http.ErrServerClosed {
+				log.WithError(err).Print("Failed to start webhook server")
+			}
+		}()
+	} else {
+		log.Print("Webhook serving certificate not found, Blueprint validating webhook is disabled")
+	}
+
 	// Initialize the clients.
 	log.Print("Getting kubernetes context")
 	config, err := rest.InClusterConfig()
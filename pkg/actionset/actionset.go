@@ -0,0 +1,117 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package actionset builds ActionSets that are derived from other ActionSets.
+package actionset
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// ChildParams overrides the actions that a child ActionSet inherits from its
+// parent. Empty fields are inherited.
+type ChildParams struct {
+	ActionName string
+	Blueprint  string
+	Objects    []crv1alpha1.ObjectReference
+	Options    map[string]string
+	Profile    *crv1alpha1.ObjectReference
+	Secrets    map[string]crv1alpha1.ObjectReference
+	ConfigMaps map[string]crv1alpha1.ObjectReference
+}
+
+// Child returns an ActionSet that runs the actions of a complete parent
+// ActionSet on the parent's output artifacts, with params applied.
+func Child(parent *crv1alpha1.ActionSet, params ChildParams) (*crv1alpha1.ActionSet, error) {
+	if parent.Status == nil || parent.Status.State != crv1alpha1.StateComplete {
+		return nil, errors.Errorf("Request parent ActionSet %s has not been executed", parent.GetName())
+	}
+
+	actions := make([]crv1alpha1.ActionSpec, 0, len(parent.Status.Actions)*max(1, len(params.Objects)))
+	for aidx, pa := range parent.Status.Actions {
+		as := crv1alpha1.ActionSpec{
+			Name:       parent.Spec.Actions[aidx].Name,
+			Blueprint:  pa.Blueprint,
+			Object:     pa.Object,
+			Artifacts:  pa.Artifacts,
+			Secrets:    parent.Spec.Actions[aidx].Secrets,
+			ConfigMaps: parent.Spec.Actions[aidx].ConfigMaps,
+			Profile:    parent.Spec.Actions[aidx].Profile,
+			Options:    mergeOptions(params.Options, parent.Spec.Actions[aidx].Options),
+		}
+		// Apply overrides
+		if params.ActionName != "" {
+			as.Name = params.ActionName
+		}
+		if params.Blueprint != "" {
+			as.Blueprint = params.Blueprint
+		}
+		if len(params.Secrets) > 0 {
+			as.Secrets = params.Secrets
+		}
+		if len(params.ConfigMaps) > 0 {
+			as.ConfigMaps = params.ConfigMaps
+		}
+		if params.Profile != nil {
+			as.Profile = params.Profile
+		}
+		if len(params.Objects) > 0 {
+			for _, obj := range params.Objects {
+				asCopy := as.DeepCopy()
+				asCopy.Object = obj
+
+				actions = append(actions, *asCopy)
+			}
+		} else {
+			actions = append(actions, as)
+		}
+	}
+	return &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: func() string {
+				if params.ActionName != "" {
+					return fmt.Sprintf("%s-%s-", params.ActionName, parent.GetName())
+				}
+				return fmt.Sprintf("%s-", parent.GetName())
+			}(),
+		},
+		Spec: &crv1alpha1.ActionSetSpec{
+			Actions: actions,
+		},
+	}, nil
+}
+
+func mergeOptions(src map[string]string, dst map[string]string) map[string]string {
+	final := make(map[string]string, len(src)+len(dst))
+	for k, v := range dst {
+		final[k] = v
+	}
+	// Override default options and set additional ones
+	for k, v := range src {
+		final[k] = v
+	}
+	return final
+}
+
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actionset
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type ActionSetSuite struct{}
+
+var _ = Suite(&ActionSetSuite{})
+
+func (s *ActionSetSuite) TestChild(c *C) {
+	parent := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-abc"},
+		Spec: &crv1alpha1.ActionSetSpec{
+			Actions: []crv1alpha1.ActionSpec{
+				{
+					Name:    "backup",
+					Options: map[string]string{"a": "1", "b": "2"},
+				},
+			},
+		},
+		Status: &crv1alpha1.ActionSetStatus{
+			State: crv1alpha1.StateRunning,
+			Actions: []crv1alpha1.ActionStatus{
+				{
+					Blueprint: "bp",
+					Object:    crv1alpha1.ObjectReference{Name: "app"},
+					Artifacts: map[string]crv1alpha1.Artifact{
+						"snapshot": {KeyValue: map[string]string{"id": "123"}},
+					},
+				},
+			},
+		},
+	}
+	_, err := Child(parent, ChildParams{ActionName: "delete"})
+	c.Assert(err, NotNil)
+
+	parent.Status.State = crv1alpha1.StateComplete
+	as, err := Child(parent, ChildParams{ActionName: "delete", Options: map[string]string{"b": "3"}})
+	c.Assert(err, IsNil)
+	c.Assert(as.GenerateName, Equals, "delete-backup-abc-")
+	c.Assert(as.Spec.Actions, HasLen, 1)
+	a := as.Spec.Actions[0]
+	c.Assert(a.Name, Equals, "delete")
+	c.Assert(a.Blueprint, Equals, "bp")
+	c.Assert(a.Object.Name, Equals, "app")
+	c.Assert(a.Artifacts["snapshot"].KeyValue["id"], Equals, "123")
+	c.Assert(a.Options, DeepEquals, map[string]string{"a": "1", "b": "3"})
+
+	as, err = Child(parent, ChildParams{
+		Objects: []crv1alpha1.ObjectReference{{Name: "app1"}, {Name: "app2"}},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(as.GenerateName, Equals, "backup-abc-")
+	c.Assert(as.Spec.Actions, HasLen, 2)
+	c.Assert(as.Spec.Actions[0].Name, Equals, "backup")
+	c.Assert(as.Spec.Actions[1].Object.Name, Equals, "app2")
+}
@@ -5,13 +5,35 @@ package v1alpha1
 func (in *BlueprintPhase) DeepCopyInto(out *BlueprintPhase) {
 	*out = *in
 	// TODO: Handle 'Args'
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopyInto handles the Phase deep copies, copying the receiver, writing into out. in must be non-nil.
 // This is a workaround to handle the map[string]interface{} output type
 func (in *Phase) DeepCopyInto(out *Phase) {
 	*out = *in
-	// TODO: Handle 'Output' map[string]interface{}
+	// TODO: Handle 'Output' and 'RenderedArgs' map[string]interface{}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]PhaseAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopyInto handles JSONMap deep copies, copying the receiver, writing into out. in must be non-nil.
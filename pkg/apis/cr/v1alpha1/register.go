@@ -65,6 +65,16 @@ var ProfileResource = customresource.CustomResource{
 	Kind:    reflect.TypeOf(Profile{}).Name(),
 }
 
+// BackupScheduleResource is a CRD for backupschedules.
+var BackupScheduleResource = customresource.CustomResource{
+	Name:    BackupScheduleResourceName,
+	Plural:  BackupScheduleResourceNamePlural,
+	Group:   ResourceGroup,
+	Version: SchemeVersion,
+	Scope:   apiextensionsv1beta1.NamespaceScoped,
+	Kind:    reflect.TypeOf(BackupSchedule{}).Name(),
+}
+
 // Kind takes an unqualified kind and returns back a Group qualified GroupKind
 func Kind(kind string) schema.GroupKind {
 	return SchemeGroupVersion.WithKind(kind).GroupKind()
@@ -84,6 +94,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BlueprintList{},
 		&Profile{},
 		&ProfileList{},
+		&BackupSchedule{},
+		&BackupScheduleList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -83,6 +83,13 @@ type ObjectReference struct {
 // ActionSetSpec is the specification for the actionset.
 type ActionSetSpec struct {
 	Actions []ActionSpec `json:"actions"`
+	// Cancel requests that the controller stops executing the actions. The
+	// ActionSet is kept and its state is set to cancelled.
+	Cancel bool `json:"cancel,omitempty"`
+	// DryRun requests that the controller only renders the args of every
+	// phase. The rendered args are reported in the phase statuses and the
+	// phases are not executed.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // ActionSpec is the specification for a single Action.
@@ -130,6 +137,8 @@ type ActionStatus struct {
 	Blueprint string `json:"blueprint"`
 	// Phases are sub-actions an are executed sequentially.
 	Phases []Phase `json:"phases"`
+	// DeferPhase is executed after the phases, whether they succeeded or failed.
+	DeferPhase *Phase `json:"deferPhase,omitempty"`
 	// Artifacts created by this phase.
 	Artifacts map[string]Artifact `json:"artifacts"`
 }
@@ -146,6 +155,8 @@ const (
 	StateFailed State = "failed"
 	// StateComplete means this action or phase finished successfully.
 	StateComplete State = "complete"
+	// StateCancelled means this action or phase was stopped before it finished.
+	StateCancelled State = "cancelled"
 )
 
 type Error struct {
@@ -157,6 +168,25 @@ type Phase struct {
 	Name   string                 `json:"name"`
 	State  State                  `json:"state"`
 	Output map[string]interface{} `json:"output"`
+	// DependsOn lists the phases that must complete before this phase is
+	// executed.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Attempts records every execution of this phase, including retries. It
+	// is only set when the phase's retry policy allows more than one attempt.
+	Attempts []PhaseAttempt `json:"attempts,omitempty"`
+	// RenderedArgs are the args of the phase rendered by a dry run. Values
+	// taken from Secrets or from the Profile credentials are redacted.
+	RenderedArgs map[string]interface{} `json:"renderedArgs,omitempty"`
+}
+
+// PhaseAttempt is the result of a single execution of a phase.
+type PhaseAttempt struct {
+	// Attempt is the 1-based index of this execution.
+	Attempt   int         `json:"attempt"`
+	State     State       `json:"state"`
+	StartTime metav1.Time `json:"startTime"`
+	EndTime   metav1.Time `json:"endTime"`
+	Error     Error       `json:"error,omitempty"`
 }
 
 // k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -203,6 +233,12 @@ type BlueprintAction struct {
 	InputArtifactNames []string            `json:"inputArtifactNames"`
 	OutputArtifacts    map[string]Artifact `json:"outputArtifacts"`
 	Phases             []BlueprintPhase    `json:"phases"`
+	// DeferPhase is always executed after the phases, whether they succeeded
+	// or failed. It is meant to undo changes made by earlier phases.
+	DeferPhase *BlueprintPhase `json:"deferPhase,omitempty"`
+	// Parallelism is the maximum number of phases executed at the same time
+	// when phases declare dependencies. Zero means no limit.
+	Parallelism int `json:"parallelism,omitempty"`
 }
 
 // BlueprintPhase is a an individual unit of execution.
@@ -211,8 +247,50 @@ type BlueprintPhase struct {
 	Name       string                     `json:"name"`
 	ObjectRefs map[string]ObjectReference `json:"objects"`
 	Args       map[string]interface{}     `json:"args"`
+	Retry      *RetryPolicy               `json:"retry,omitempty"`
+	// DependsOn lists the phases that must complete before this phase is
+	// executed. If no phase of an action declares dependencies, each phase
+	// depends on the previous one.
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// RetryPolicy describes how a failed phase is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times the phase may be executed,
+	// including the first attempt. Values less than 2 disable retries.
+	MaxAttempts int `json:"maxAttempts"`
+	// Backoff controls the wait between two attempts.
+	Backoff *RetryBackoff `json:"backoff,omitempty"`
+	// RetryOn lists the classes of errors that may be retried. If empty,
+	// every error is retried.
+	RetryOn []RetryErrorClass `json:"retryOn,omitempty"`
+}
+
+// RetryBackoff configures an exponential backoff between retries.
+type RetryBackoff struct {
+	// Min is the wait before the first retry.
+	Min metav1.Duration `json:"min,omitempty"`
+	// Max caps the wait between two retries.
+	Max metav1.Duration `json:"max,omitempty"`
+	// Factor multiplies the wait after every retry.
+	Factor float64 `json:"factor,omitempty"`
+	// Jitter randomizes the wait between retries.
+	Jitter bool `json:"jitter,omitempty"`
 }
 
+// RetryErrorClass is a class of errors that may be retried.
+type RetryErrorClass string
+
+const (
+	// RetryOnAny retries every error.
+	RetryOnAny RetryErrorClass = "any"
+	// RetryOnTransient retries timeouts, network errors and throttling or
+	// server-side errors returned by the Kubernetes API.
+	RetryOnTransient RetryErrorClass = "transient"
+	// RetryOnExec retries commands that exited with a non-zero status in a pod.
+	RetryOnExec RetryErrorClass = "exec"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // BlueprintList is the definition of a list of Blueprints
@@ -234,11 +312,13 @@ const (
 
 // Profile
 type Profile struct {
-	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:"metadata"`
-	Location          Location   `json:"location"`
-	Credential        Credential `json:"credential"`
-	SkipSSLVerify     bool       `json:"skipSSLVerify"`
+	metav1.TypeMeta    `json:",inline"`
+	metav1.ObjectMeta  `json:"metadata"`
+	Location           Location       `json:"location"`
+	Credential         Credential     `json:"credential"`
+	SkipSSLVerify      bool           `json:"skipSSLVerify"`
+	EncryptionKey      *EncryptionKey `json:"encryptionKey,omitempty"`
+	RepositoryPassword *EncryptionKey `json:"repositoryPassword,omitempty"`
 }
 
 // LocationType
@@ -248,6 +328,7 @@ const (
 	LocationTypeGCS         LocationType = "gcs"
 	LocationTypeS3Compliant LocationType = "s3Compliant"
 	LocationTypeAzure       LocationType = "azure"
+	LocationTypeFilesystem  LocationType = "filesystem"
 )
 
 // Location
@@ -281,6 +362,14 @@ type KeyPair struct {
 	Secret      ObjectReference `json:"secret"`
 }
 
+// EncryptionKey references the Secret field that holds the key used to
+// encrypt data written to the Profile's Location, or the password of the
+// restic repositories in it.
+type EncryptionKey struct {
+	KeyField string          `json:"keyField"`
+	Secret   ObjectReference `json:"secret"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ProfileList is the definition of a list of Profiles
@@ -289,3 +378,112 @@ type ProfileList struct {
 	metav1.ListMeta `json:"metadata"`
 	Items           []*Profile `json:"items"`
 }
+
+// These names are used to query BackupSchedule API objects.
+const (
+	BackupScheduleResourceName       = "backupschedule"
+	BackupScheduleResourceNamePlural = "backupschedules"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupSchedule creates ActionSets on a cron schedule.
+type BackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              *BackupScheduleSpec   `json:"spec"`
+	Status            *BackupScheduleStatus `json:"status,omitempty"`
+}
+
+// BackupScheduleSpec is the specification for the backupschedule.
+type BackupScheduleSpec struct {
+	// Schedule is a cron expression in the standard five field format, or a
+	// descriptor such as `@daily` or `@every 1h`.
+	Schedule string `json:"schedule"`
+	// Suspend stops the creation of new ActionSets. ActionSets that were
+	// already created are not affected.
+	Suspend bool `json:"suspend,omitempty"`
+	// ActionSetTemplate is used to create the ActionSets.
+	ActionSetTemplate ActionSetTemplate `json:"actionSetTemplate"`
+	// ConcurrencyPolicy specifies how to treat a scheduled run while an
+	// ActionSet created by an earlier run is still pending or running.
+	// Defaults to Allow.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// SuccessfulHistoryLimit is the number of complete ActionSets to keep.
+	// Defaults to 3.
+	SuccessfulHistoryLimit *int32 `json:"successfulHistoryLimit,omitempty"`
+	// FailedHistoryLimit is the number of failed or cancelled ActionSets to
+	// keep. Defaults to 1.
+	FailedHistoryLimit *int32 `json:"failedHistoryLimit,omitempty"`
+	// Retention expires the artifacts of complete ActionSets. If it is set,
+	// SuccessfulHistoryLimit is ignored.
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+}
+
+// RetentionPolicy describes which backups are kept. A backup is kept if any
+// of the Keep rules selects it or if it is not older than MaxAge. Backups that
+// are not kept are deleted by running DeleteAction on them.
+type RetentionPolicy struct {
+	// KeepLast keeps the newest backups.
+	KeepLast int `json:"keepLast,omitempty"`
+	// KeepDaily keeps the newest backup of each of the last days that have
+	// a backup.
+	KeepDaily int `json:"keepDaily,omitempty"`
+	// KeepWeekly keeps the newest backup of each of the last weeks that have
+	// a backup.
+	KeepWeekly int `json:"keepWeekly,omitempty"`
+	// KeepMonthly keeps the newest backup of each of the last months that
+	// have a backup.
+	KeepMonthly int `json:"keepMonthly,omitempty"`
+	// MaxAge keeps the backups that are not older. Older backups expire
+	// unless a Keep rule selects them.
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// DeleteAction is the Blueprint action that deletes the artifacts of a
+	// backup. Defaults to `delete`.
+	DeleteAction string `json:"deleteAction,omitempty"`
+}
+
+// ActionSetTemplate describes the ActionSets created by a BackupSchedule.
+type ActionSetTemplate struct {
+	// Labels and Annotations are added to the ActionSets.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Spec        *ActionSetSpec    `json:"spec"`
+}
+
+// ConcurrencyPolicy describes how scheduled runs that overlap are handled.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyAllow creates ActionSets that run concurrently.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "Allow"
+	// ConcurrencyPolicyForbid skips a run while an earlier ActionSet has not
+	// finished.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "Forbid"
+	// ConcurrencyPolicyReplace cancels the ActionSets that have not finished
+	// before creating a new one.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace"
+)
+
+// BackupScheduleStatus is the status for the backupschedule. This should only
+// be updated by the controller.
+type BackupScheduleStatus struct {
+	// LastScheduleTime is the last time an ActionSet was created.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastActionSet is the name of the last ActionSet that was created.
+	LastActionSet string `json:"lastActionSet,omitempty"`
+	// Active lists the ActionSets that have not finished.
+	Active []string `json:"active,omitempty"`
+	Error  Error    `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupScheduleList is the definition of a list of BackupSchedules
+type BackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []*BackupSchedule `json:"items"`
+}
@@ -59,6 +59,13 @@ func (s *ebsStorage) Type() blockstorage.Type {
 	return blockstorage.TypeEBS
 }
 
+func init() {
+	_ = blockstorage.RegisterProvider(blockstorage.TypeEBS, NewProvider, blockstorage.ConfigSchema{
+		Required: []string{awsconfig.ConfigRegion},
+		Optional: []string{awsconfig.AccessKeyID, awsconfig.SecretAccessKey, awsconfig.ConfigRole},
+	})
+}
+
 // NewProvider returns a provider for the EBS storage type in the specified region
 func NewProvider(ctx context.Context, config map[string]string) (blockstorage.Provider, error) {
 	awsConfig, region, err := awsconfig.GetConfig(ctx, config)
@@ -57,6 +57,13 @@ const (
 	maxRetries = 10
 )
 
+func init() {
+	_ = blockstorage.RegisterProvider(blockstorage.TypeEFS, NewEFSProvider, blockstorage.ConfigSchema{
+		Required: []string{awsconfig.ConfigRegion},
+		Optional: []string{awsconfig.AccessKeyID, awsconfig.SecretAccessKey, awsconfig.ConfigRole},
+	})
+}
+
 // NewEFSProvider retuns a blockstorage provider for AWS EFS.
 func NewEFSProvider(ctx context.Context, config map[string]string) (blockstorage.Provider, error) {
 	awsConfig, region, err := awsconfig.GetConfig(ctx, config)
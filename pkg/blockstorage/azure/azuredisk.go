@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 
 	azcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2018-04-01/compute"
 	azto "github.com/Azure/go-autorest/autorest/to"
@@ -21,6 +22,9 @@ var _ blockstorage.Provider = (*adStorage)(nil)
 const (
 	volumeNameFmt   = "vol-%s"
 	snapshotNameFmt = "snap-%s"
+	// snapshotAccessDuration is the duration in seconds of the SAS URI used
+	// to copy snapshots across regions.
+	snapshotAccessDuration = 3600
 )
 
 type adStorage struct {
@@ -31,6 +35,13 @@ func (s *adStorage) Type() blockstorage.Type {
 	return blockstorage.TypeAD
 }
 
+func init() {
+	_ = blockstorage.RegisterProvider(blockstorage.TypeAD, NewProvider, blockstorage.ConfigSchema{
+		Required: []string{blockstorage.AzureTenantID, blockstorage.AzureCientID, blockstorage.AzureClentSecret},
+		Optional: []string{blockstorage.AzureSubscriptionID, blockstorage.AzureResurceGroup},
+	})
+}
+
 // NewProvider returns a provider for the Azure blockstorage type
 func NewProvider(ctx context.Context, config map[string]string) (blockstorage.Provider, error) {
 	azCli, err := NewClient(ctx, config)
@@ -100,8 +111,98 @@ func (s *adStorage) VolumeDelete(ctx context.Context, volume *blockstorage.Volum
 	return errors.Wrapf(err, "Error in deleting Volume with ID %s", volume.ID)
 }
 
+// SnapshotCopy copies snapshot 'from' to the region of 'to'. Snapshots in the
+// same region are copied directly. Snapshots in other regions are imported
+// from a read-only SAS URI of 'from', which is revoked once the copy is done.
+// The copy is always created in the resource group the provider was configured
+// with, which may differ from the resource group of 'from'.
 func (s *adStorage) SnapshotCopy(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot) (*blockstorage.Snapshot, error) {
-	return nil, errors.New("Copy Snapshot not implemented")
+	if to.Region == "" {
+		return nil, errors.New("Destination snapshot region must be specified")
+	}
+	if to.ID != "" {
+		return nil, errors.Errorf("Snapshot %v destination ID must be empty", to)
+	}
+	_, rg, name, err := parseSnapshotID(from.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "SnapshotsClient.Copy: Failure in parsing snapshot ID %s", from.ID)
+	}
+	creationData := &azcompute.CreationData{
+		CreateOption:     azcompute.Copy,
+		SourceResourceID: azto.StringPtr(from.ID),
+	}
+	if !strings.EqualFold(from.Region, to.Region) {
+		sas, err := s.grantSnapshotAccess(ctx, rg, name)
+		if err != nil {
+			return nil, err
+		}
+		defer s.revokeSnapshotAccess(ctx, rg, name)
+		creationData = &azcompute.CreationData{
+			CreateOption: azcompute.Import,
+			SourceURI:    azto.StringPtr(sas),
+		}
+	}
+	// Copy tags from source snap to dest.
+	tags := ktags.Union(blockstorage.KeyValueToMap(from.Tags), blockstorage.KeyValueToMap(to.Tags))
+	snapName := fmt.Sprintf(snapshotNameFmt, uuid.NewV1().String())
+	createSnap := azcompute.Snapshot{
+		Name:     azto.StringPtr(snapName),
+		Location: azto.StringPtr(to.Region),
+		Tags:     *azto.StringMapPtr(blockstorage.SanitizeTags(ktags.GetTags(tags))),
+		DiskProperties: &azcompute.DiskProperties{
+			CreationData: creationData,
+		},
+	}
+	result, err := s.azCli.SnapshotsClient.CreateOrUpdate(ctx, s.azCli.ResourceGroup, snapName, createSnap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to copy snapshot %s", from.ID)
+	}
+	if err = result.WaitForCompletionRef(ctx, s.azCli.SnapshotsClient.Client); err != nil {
+		return nil, errors.Wrapf(err, "Failed to copy snapshot %s", from.ID)
+	}
+	rs, err := result.Result(*s.azCli.SnapshotsClient)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error in getting result of Snapshot copy operation, snaphotName %s", snapName)
+	}
+	snap, err := s.SnapshotGet(ctx, azto.String(rs.ID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to Get Snapshot after copy, snaphotName %s", snapName)
+	}
+	if from.Volume != nil {
+		vol := *from.Volume
+		snap.Volume = &vol
+	}
+	return snap, nil
+}
+
+// grantSnapshotAccess returns a read-only SAS URI of the snapshot.
+func (s *adStorage) grantSnapshotAccess(ctx context.Context, rg, name string) (string, error) {
+	gad := azcompute.GrantAccessData{
+		Access:            azcompute.Read,
+		DurationInSeconds: azto.Int32Ptr(snapshotAccessDuration),
+	}
+	result, err := s.azCli.SnapshotsClient.GrantAccess(ctx, rg, name, gad)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to grant access to snapshot %s", name)
+	}
+	if err = result.WaitForCompletionRef(ctx, s.azCli.SnapshotsClient.Client); err != nil {
+		return "", errors.Wrapf(err, "Failed to grant access to snapshot %s", name)
+	}
+	au, err := result.Result(*s.azCli.SnapshotsClient)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error in getting result of Snapshot grant access operation, snaphotName %s", name)
+	}
+	return azto.String(au.AccessSAS), nil
+}
+
+func (s *adStorage) revokeSnapshotAccess(ctx context.Context, rg, name string) {
+	result, err := s.azCli.SnapshotsClient.RevokeAccess(ctx, rg, name)
+	if err == nil {
+		err = result.WaitForCompletionRef(ctx, s.azCli.SnapshotsClient.Client)
+	}
+	if err != nil {
+		log.WithError(err).Print("Failed to revoke access to snapshot", field.M{"SnapshotName": name})
+	}
 }
 
 func (s *adStorage) SnapshotCreate(ctx context.Context, volume blockstorage.Volume, tags map[string]string) (*blockstorage.Snapshot, error) {
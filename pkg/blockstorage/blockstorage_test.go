@@ -26,6 +26,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/blockstorage"
 	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
 	ktags "github.com/kanisterio/kanister/pkg/blockstorage/tags"
+	"github.com/kanisterio/kanister/pkg/blockstorage/vmware"
 	envconfig "github.com/kanisterio/kanister/pkg/config"
 	"github.com/kanisterio/kanister/pkg/field"
 	"github.com/kanisterio/kanister/pkg/log"
@@ -52,6 +53,7 @@ var _ = Suite(&BlockStorageProviderSuite{storageType: blockstorage.TypeEBS, stor
 var _ = Suite(&BlockStorageProviderSuite{storageType: blockstorage.TypeGPD, storageRegion: "", storageAZ: "us-west1-b"})
 var _ = Suite(&BlockStorageProviderSuite{storageType: blockstorage.TypeGPD, storageRegion: "", storageAZ: "us-west1-c__us-west1-a"})
 var _ = Suite(&BlockStorageProviderSuite{storageType: blockstorage.TypeAD, storageRegion: "", storageAZ: "westus"})
+var _ = Suite(&BlockStorageProviderSuite{storageType: blockstorage.TypeFCD, storageRegion: "", storageAZ: ""})
 
 func (s *BlockStorageProviderSuite) SetUpSuite(c *C) {
 	var err error
@@ -172,9 +174,12 @@ func (s *BlockStorageProviderSuite) TestSnapshotCopy(c *C) {
 func (s *BlockStorageProviderSuite) testVolumesList(c *C) {
 	var tags map[string]string
 	var zone string
-	if s.provider.Type() == blockstorage.TypeGPD {
+	switch s.provider.Type() {
+	case blockstorage.TypeGPD:
 		tags = map[string]string{"name": "*"}
-	} else {
+	case blockstorage.TypeFCD:
+		tags = map[string]string{testTagKey: testTagValue}
+	default:
 		tags = map[string]string{"status": "available"}
 	}
 	zone = s.storageAZ
@@ -189,10 +194,13 @@ func (s *BlockStorageProviderSuite) testVolumesList(c *C) {
 func (s *BlockStorageProviderSuite) TestSnapshotsList(c *C) {
 	var tags map[string]string
 	testSnaphot := s.createSnapshot(c)
-	if s.provider.Type() != blockstorage.TypeEBS {
-		tags = map[string]string{"labels." + ktags.SanitizeValueForGCP(testTagKey): testTagValue}
-	} else {
+	switch s.provider.Type() {
+	case blockstorage.TypeEBS:
 		tags = map[string]string{"tag-key": testTagKey, "tag-value": testTagValue}
+	case blockstorage.TypeFCD:
+		tags = map[string]string{testTagKey: testTagValue}
+	default:
+		tags = map[string]string{"labels." + ktags.SanitizeValueForGCP(testTagKey): testTagValue}
 	}
 	snaps, err := s.provider.SnapshotsList(context.Background(), tags)
 	c.Assert(err, IsNil)
@@ -242,7 +250,7 @@ func (s *BlockStorageProviderSuite) createSnapshot(c *C) *blockstorage.Snapshot
 }
 
 func (s *BlockStorageProviderSuite) checkTagsExist(c *C, actual map[string]string, expected map[string]string) {
-	if s.provider.Type() != blockstorage.TypeEBS {
+	if s.provider.Type() != blockstorage.TypeEBS && s.provider.Type() != blockstorage.TypeFCD {
 		expected = blockstorage.SanitizeTags(expected)
 	}
 
@@ -278,6 +286,11 @@ func (s *BlockStorageProviderSuite) getConfig(c *C, region string) map[string]st
 		config[blockstorage.AzureCientID] = envconfig.GetEnvOrSkip(c, blockstorage.AzureCientID)
 		config[blockstorage.AzureClentSecret] = envconfig.GetEnvOrSkip(c, blockstorage.AzureClentSecret)
 		config[blockstorage.AzureResurceGroup] = envconfig.GetEnvOrSkip(c, blockstorage.AzureResurceGroup)
+	case blockstorage.TypeFCD:
+		config[vmware.VSphereEndpointKey] = envconfig.GetEnvOrSkip(c, vmware.VSphereEndpointKey)
+		config[vmware.VSphereUsernameKey] = envconfig.GetEnvOrSkip(c, vmware.VSphereUsernameKey)
+		config[vmware.VSpherePasswordKey] = envconfig.GetEnvOrSkip(c, vmware.VSpherePasswordKey)
+		config[vmware.VSphereDatastoreKey] = envconfig.GetEnvOrSkip(c, vmware.VSphereDatastoreKey)
 	default:
 		c.Errorf("Unknown blockstorage storage type %s", s.storageType)
 	}
@@ -58,6 +58,14 @@ func (s *gpdStorage) Type() blockstorage.Type {
 	return blockstorage.TypeGPD
 }
 
+func init() {
+	_ = blockstorage.RegisterProvider(blockstorage.TypeGPD, func(ctx context.Context, config map[string]string) (blockstorage.Provider, error) {
+		return NewProvider(config)
+	}, blockstorage.ConfigSchema{
+		Optional: []string{blockstorage.GoogleServiceKey},
+	})
+}
+
 // NewProvider returns a provider for the GCP storage type
 func NewProvider(config map[string]string) (blockstorage.Provider, error) {
 	serviceKey := config[blockstorage.GoogleServiceKey]
@@ -160,8 +168,78 @@ func (s *gpdStorage) VolumeDelete(ctx context.Context, volume *blockstorage.Volu
 	return s.waitOnOperation(ctx, op, volume.Az)
 }
 
+// SnapshotCopy copies snapshot 'from' to the region of 'to'. GCP snapshots
+// can't be copied directly, so a temporary disk is created from 'from' in the
+// destination region and snapshotted with its storage location set to that
+// region. The temporary disk is deleted once the copy is ready.
 func (s *gpdStorage) SnapshotCopy(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot) (*blockstorage.Snapshot, error) {
-	return nil, errors.Errorf("Not implemented")
+	if to.Region == "" {
+		return nil, errors.New("Destination snapshot region must be specified")
+	}
+	if to.ID != "" {
+		return nil, errors.Errorf("Snapshot %v destination ID must be empty", to)
+	}
+	zones, err := s.FromRegion(ctx, to.Region)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get zones for region %s", to.Region)
+	}
+	if len(zones) == 0 {
+		return nil, errors.Errorf("No zones found for region %s", to.Region)
+	}
+	snap, err := s.service.Snapshots.Get(s.project, from.ID).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	diskZone := zones[0]
+	tmpDisk := &compute.Disk{
+		Name:           fmt.Sprintf(volumeNameFmt, uuid.NewV1().String()),
+		Labels:         blockstorage.SanitizeTags(ktags.GetStdTags()),
+		SourceSnapshot: snap.SelfLink,
+	}
+	op, err := s.service.Disks.Insert(s.project, diskZone, tmpDisk).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create temporary volume from snapshot %s", from.ID)
+	}
+	defer func() {
+		if err := s.VolumeDelete(ctx, &blockstorage.Volume{ID: tmpDisk.Name, Az: diskZone}); err != nil {
+			log.WithError(err).Print("Failed to delete temporary volume", field.M{"VolumeID": tmpDisk.Name})
+		}
+	}()
+	if err = s.waitOnOperation(ctx, op, diskZone); err != nil {
+		return nil, err
+	}
+
+	// Copy tags from source snap to dest.
+	tags := ktags.Union(blockstorage.KeyValueToMap(from.Tags), blockstorage.KeyValueToMap(to.Tags))
+	rb := &compute.Snapshot{
+		Name:             fmt.Sprintf(snapshotNameFmt, uuid.NewV1().String()),
+		Description:      "Copy of " + from.ID,
+		Labels:           blockstorage.SanitizeTags(ktags.GetTags(tags)),
+		StorageLocations: []string{to.Region},
+	}
+	op, err = s.service.Disks.CreateSnapshot(s.project, diskZone, tmpDisk.Name, rb).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to copy snapshot %s", from.ID)
+	}
+	if err = s.waitOnOperation(ctx, op, diskZone); err != nil {
+		return nil, err
+	}
+	if err = s.waitOnSnapshotID(ctx, rb.Name); err != nil {
+		return nil, errors.Wrapf(err, "Snapshot %s did not complete", rb.Name)
+	}
+	rs, err := s.SnapshotGet(ctx, rb.Name)
+	if err != nil {
+		return nil, err
+	}
+	// The snapshot is taken from the temporary disk, so report the volume of
+	// the source snapshot instead.
+	if from.Volume != nil {
+		vol := *from.Volume
+		rs.Volume = &vol
+	}
+	rs.Region = to.Region
+	rs.Size = from.Size
+	return rs, nil
 }
 
 func (s *gpdStorage) SnapshotCreate(ctx context.Context, volume blockstorage.Volume, tags map[string]string) (*blockstorage.Snapshot, error) {
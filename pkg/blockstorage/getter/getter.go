@@ -16,13 +16,15 @@ package getter
 
 import (
 	"context"
-	"github.com/pkg/errors"
 
 	"github.com/kanisterio/kanister/pkg/blockstorage"
-	"github.com/kanisterio/kanister/pkg/blockstorage/awsebs"
-	"github.com/kanisterio/kanister/pkg/blockstorage/azure"
-	"github.com/kanisterio/kanister/pkg/blockstorage/gcepd"
-	"github.com/kanisterio/kanister/pkg/blockstorage/ibm"
+	// Link in the providers shipped with Kanister.
+	_ "github.com/kanisterio/kanister/pkg/blockstorage/awsebs"
+	_ "github.com/kanisterio/kanister/pkg/blockstorage/awsefs"
+	_ "github.com/kanisterio/kanister/pkg/blockstorage/azure"
+	_ "github.com/kanisterio/kanister/pkg/blockstorage/gcepd"
+	_ "github.com/kanisterio/kanister/pkg/blockstorage/ibm"
+	_ "github.com/kanisterio/kanister/pkg/blockstorage/vmware"
 )
 
 // Getter is a resolver for a storage provider.
@@ -39,39 +41,14 @@ func New() Getter {
 	return &getter{}
 }
 
-// Get returns a provider for the requested storage type in the specified region
+// Get returns a provider for the requested storage type in the specified region.
+// Providers are resolved through the blockstorage provider registry, so
+// out-of-tree providers can be linked in with a blank import of their package.
 func (*getter) Get(storageType blockstorage.Type, config map[string]string) (blockstorage.Provider, error) {
-	switch storageType {
-	case blockstorage.TypeEBS:
-		return awsebs.NewProvider(context.TODO(), config)
-	case blockstorage.TypeSoftlayerBlock:
-		return ibm.NewProvider(context.TODO(), config)
-	case blockstorage.TypeGPD:
-		return gcepd.NewProvider(config)
-	case blockstorage.TypeSoftlayerFile:
-		config[ibm.SoftlayerFileAttName] = "true"
-		return ibm.NewProvider(context.TODO(), config)
-	case blockstorage.TypeAD:
-		return azure.NewProvider(context.Background(), config)
-	default:
-		return nil, errors.Errorf("Unsupported storage type %v", storageType)
-	}
+	return blockstorage.NewProvider(context.TODO(), storageType, config)
 }
 
 // Supported returns true if the storage type is supported
 func Supported(st blockstorage.Type) bool {
-	switch st {
-	case blockstorage.TypeEBS:
-		return true
-	case blockstorage.TypeSoftlayerBlock:
-		return true
-	case blockstorage.TypeGPD:
-		return true
-	case blockstorage.TypeSoftlayerFile:
-		return true
-	case blockstorage.TypeAD:
-		return true
-	default:
-		return false
-	}
+	return blockstorage.IsProviderRegistered(st)
 }
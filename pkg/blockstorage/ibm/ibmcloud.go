@@ -52,6 +52,27 @@ func (s *ibmCloud) Type() blockstorage.Type {
 	return blockstorage.TypeSoftlayerBlock
 }
 
+func init() {
+	schema := blockstorage.ConfigSchema{
+		Optional: []string{
+			APIKeyArgName,
+			SLAPIKeyArgName,
+			SLAPIUsernameArgName,
+			CfgSecretNameArgName,
+			CfgSecretNameSpaceArgName,
+		},
+	}
+	_ = blockstorage.RegisterProvider(blockstorage.TypeSoftlayerBlock, NewProvider, schema)
+	_ = blockstorage.RegisterProvider(blockstorage.TypeSoftlayerFile, func(ctx context.Context, args map[string]string) (blockstorage.Provider, error) {
+		fileArgs := make(map[string]string, len(args)+1)
+		for k, v := range args {
+			fileArgs[k] = v
+		}
+		fileArgs[SoftlayerFileAttName] = "true"
+		return NewProvider(ctx, fileArgs)
+	}, schema)
+}
+
 // NewProvider returns a provider for the IBM Cloud
 func NewProvider(ctx context.Context, args map[string]string) (blockstorage.Provider, error) {
 	ibmCli, err := newClient(ctx, args)
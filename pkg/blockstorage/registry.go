@@ -0,0 +1,121 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockstorage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	providerMu sync.RWMutex
+	providers  = make(map[Type]providerRegistration)
+)
+
+// ProviderFactory creates a Provider with the config.
+type ProviderFactory func(ctx context.Context, config map[string]string) (Provider, error)
+
+// ConfigSchema describes the config keys accepted by a Provider.
+type ConfigSchema struct {
+	// Required are the keys that must be set in the config.
+	Required []string
+	// Optional are the keys that may be set in the config.
+	Optional []string
+}
+
+type providerRegistration struct {
+	factory ProviderFactory
+	schema  ConfigSchema
+}
+
+// RegisterProvider registers the factory and config schema of the Provider
+// for the storage type. Provider packages call it from their init function,
+// so linking a package in with a blank import makes its storage type
+// available to NewProvider.
+func RegisterProvider(storageType Type, factory ProviderFactory, schema ConfigSchema) error {
+	if storageType == "" {
+		return errors.New("Storage type must be set")
+	}
+	if factory == nil {
+		return errors.Errorf("Provider factory for storage type %s is nil", storageType)
+	}
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if _, ok := providers[storageType]; ok {
+		return errors.Errorf("Provider for storage type %s is already registered", storageType)
+	}
+	providers[storageType] = providerRegistration{
+		factory: factory,
+		schema:  schema,
+	}
+	return nil
+}
+
+// NewProvider creates a Provider for the storage type with the registered
+// factory, after checking that the config has the required keys.
+func NewProvider(ctx context.Context, storageType Type, config map[string]string) (Provider, error) {
+	providerMu.RLock()
+	reg, ok := providers[storageType]
+	providerMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("Unsupported storage type %v", storageType)
+	}
+	for _, k := range reg.schema.Required {
+		if _, ok := config[k]; !ok {
+			return nil, errors.Errorf("Config key %s is required for storage type %s", k, storageType)
+		}
+	}
+	return reg.factory(ctx, config)
+}
+
+// IsProviderRegistered returns true if a Provider is registered for the
+// storage type.
+func IsProviderRegistered(storageType Type) bool {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+	_, ok := providers[storageType]
+	return ok
+}
+
+// GetConfigSchema returns the config schema of the Provider registered for
+// the storage type.
+func GetConfigSchema(storageType Type) (*ConfigSchema, error) {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+	reg, ok := providers[storageType]
+	if !ok {
+		return nil, errors.Errorf("Unsupported storage type %v", storageType)
+	}
+	return &ConfigSchema{
+		Required: append([]string{}, reg.schema.Required...),
+		Optional: append([]string{}, reg.schema.Optional...),
+	}, nil
+}
+
+// RegisteredTypes returns the storage types with a registered Provider,
+// sorted by name.
+func RegisteredTypes() []Type {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+	types := make([]Type, 0, len(providers))
+	for t := range providers {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
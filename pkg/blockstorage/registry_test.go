@@ -0,0 +1,108 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockstorage_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+)
+
+const testStorageType blockstorage.Type = "RegistryTest"
+
+type RegistrySuite struct{}
+
+var _ = Suite(&RegistrySuite{})
+
+type testProvider struct {
+	blockstorage.Provider
+	config map[string]string
+}
+
+func (s *RegistrySuite) SetUpSuite(c *C) {
+	err := blockstorage.RegisterProvider(testStorageType, func(ctx context.Context, config map[string]string) (blockstorage.Provider, error) {
+		return &testProvider{config: config}, nil
+	}, blockstorage.ConfigSchema{
+		Required: []string{"endpoint"},
+		Optional: []string{"token"},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *RegistrySuite) TestRegisterProvider(c *C) {
+	factory := func(ctx context.Context, config map[string]string) (blockstorage.Provider, error) {
+		return nil, nil
+	}
+	// Registering the same type twice fails.
+	err := blockstorage.RegisterProvider(testStorageType, factory, blockstorage.ConfigSchema{})
+	c.Assert(err, NotNil)
+	err = blockstorage.RegisterProvider("", factory, blockstorage.ConfigSchema{})
+	c.Assert(err, NotNil)
+	err = blockstorage.RegisterProvider("RegistryTestNilFactory", nil, blockstorage.ConfigSchema{})
+	c.Assert(err, NotNil)
+	c.Assert(blockstorage.IsProviderRegistered("RegistryTestNilFactory"), Equals, false)
+}
+
+func (s *RegistrySuite) TestNewProvider(c *C) {
+	ctx := context.Background()
+	p, err := blockstorage.NewProvider(ctx, testStorageType, map[string]string{"endpoint": "localhost"})
+	c.Assert(err, IsNil)
+	c.Assert(p.(*testProvider).config["endpoint"], Equals, "localhost")
+
+	_, err = blockstorage.NewProvider(ctx, testStorageType, map[string]string{"token": "abc"})
+	c.Assert(err, NotNil)
+
+	_, err = blockstorage.NewProvider(ctx, "RegistryTestUnknown", nil)
+	c.Assert(err, NotNil)
+
+	// The getter resolves providers through the registry.
+	c.Assert(getter.Supported(testStorageType), Equals, true)
+	p, err = getter.New().Get(testStorageType, map[string]string{"endpoint": "localhost"})
+	c.Assert(err, IsNil)
+	c.Assert(p, FitsTypeOf, &testProvider{})
+}
+
+func (s *RegistrySuite) TestConfigSchema(c *C) {
+	schema, err := blockstorage.GetConfigSchema(testStorageType)
+	c.Assert(err, IsNil)
+	c.Assert(schema.Required, DeepEquals, []string{"endpoint"})
+	c.Assert(schema.Optional, DeepEquals, []string{"token"})
+
+	_, err = blockstorage.GetConfigSchema("RegistryTestUnknown")
+	c.Assert(err, NotNil)
+}
+
+func (s *RegistrySuite) TestBuiltInProviders(c *C) {
+	registered := make(map[blockstorage.Type]bool)
+	for _, t := range blockstorage.RegisteredTypes() {
+		registered[t] = true
+	}
+	for _, t := range []blockstorage.Type{
+		blockstorage.TypeAD,
+		blockstorage.TypeEBS,
+		blockstorage.TypeEFS,
+		blockstorage.TypeFCD,
+		blockstorage.TypeGPD,
+		blockstorage.TypeSoftlayerBlock,
+		blockstorage.TypeSoftlayerFile,
+	} {
+		c.Check(getter.Supported(t), Equals, true)
+		c.Check(registered[t], Equals, true)
+	}
+	c.Check(getter.Supported(blockstorage.TypeCeph), Equals, false)
+}
@@ -2,17 +2,34 @@ package vmware
 
 import (
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/vmware/govmomi/vim25/types"
+	vslmtypes "github.com/vmware/govmomi/vslm/types"
 
 	"github.com/kanisterio/kanister/pkg/blockstorage"
 )
 
+const (
+	// snapshotTagKeyPrefix is the prefix of the volume metadata keys holding
+	// the snapshot tags.
+	snapshotTagKeyPrefix = "kanister.io/snapshot/"
+	// snapshotCopyTagKey tags the volumes that SnapshotCopy creates to back
+	// the copied snapshots.
+	snapshotCopyTagKey = "kanister.io/snapshot-copy"
+)
+
 func convertFromObjectToVolume(vso *types.VStorageObject) (*blockstorage.Volume, error) {
 	if vso == nil {
 		return nil, errors.New("Empty object")
 	}
+	attributes := map[string]string{}
+	if vso.Config.Backing != nil {
+		if b := vso.Config.Backing.GetBaseConfigInfoBackingInfo(); b != nil {
+			attributes[DatastoreAttributeKey] = b.Datastore.Value
+		}
+	}
 	return &blockstorage.Volume{
 		Type:         blockstorage.TypeFCD,
 		ID:           vso.Config.Id.Id,
@@ -23,10 +40,25 @@ func convertFromObjectToVolume(vso *types.VStorageObject) (*blockstorage.Volume,
 		Encrypted:    false,
 		VolumeType:   "",
 		Tags:         blockstorage.VolumeTags{},
-		Attributes:   map[string]string{},
+		Attributes:   attributes,
 	}, nil
 }
 
+func convertFromQueryResultToVolume(res *vslmtypes.VslmVsoVStorageObjectResult) *blockstorage.Volume {
+	var createTime time.Time
+	if res.CreateTime != nil {
+		createTime = *res.CreateTime
+	}
+	return &blockstorage.Volume{
+		Type:         blockstorage.TypeFCD,
+		ID:           res.Id.Id,
+		CreationTime: blockstorage.TimeStamp(createTime),
+		Size:         res.CapacityInMB / 1024,
+		Tags:         convertKeyValueToTags(filterVolumeMetadata(res.Metadata)),
+		Attributes:   map[string]string{},
+	}
+}
+
 func convertFromObjectToSnapshot(vso *types.VStorageObjectSnapshotInfoVStorageObjectSnapshot, volID string) (*blockstorage.Snapshot, error) {
 	if vso == nil {
 		return nil, errors.New("Empty opbject")
@@ -48,6 +80,19 @@ func vimID(id string) types.ID {
 	}
 }
 
+// diskBackingSpec returns the backing spec for a disk in the datastore with
+// the given managed object ID.
+func diskBackingSpec(datastore string) *types.VslmCreateSpecDiskFileBackingSpec {
+	return &types.VslmCreateSpecDiskFileBackingSpec{
+		VslmCreateSpecBackingSpec: types.VslmCreateSpecBackingSpec{
+			Datastore: types.ManagedObjectReference{
+				Type:  "Datastore",
+				Value: datastore,
+			},
+		},
+	}
+}
+
 func snapshotFullID(volID, snapshotID string) string {
 	return volID + ":" + snapshotID
 }
@@ -81,3 +126,65 @@ func convertTagsToKeyValue(tags map[string]string) []types.KeyValue {
 	}
 	return result
 }
+
+func snapshotTagPrefix(snapshotID string) string {
+	return snapshotTagKeyPrefix + snapshotID + "/"
+}
+
+// convertSnapshotTagsToKeyValue converts the snapshot tags to the volume
+// metadata they are stored in.
+func convertSnapshotTagsToKeyValue(snapshotID string, tags map[string]string) []types.KeyValue {
+	prefixed := make(map[string]string, len(tags))
+	for k, v := range tags {
+		prefixed[snapshotTagPrefix(snapshotID)+k] = v
+	}
+	return convertTagsToKeyValue(prefixed)
+}
+
+// filterVolumeMetadata removes the snapshot tags from the volume metadata.
+func filterVolumeMetadata(kvs []types.KeyValue) []types.KeyValue {
+	result := make([]types.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		if !strings.HasPrefix(kv.Key, snapshotTagKeyPrefix) {
+			result = append(result, kv)
+		}
+	}
+	return result
+}
+
+// filterSnapshotMetadata returns the tags of the snapshot stored in the
+// volume metadata.
+func filterSnapshotMetadata(snapshotID string, kvs []types.KeyValue) []types.KeyValue {
+	prefix := snapshotTagPrefix(snapshotID)
+	result := make([]types.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		if strings.HasPrefix(kv.Key, prefix) {
+			result = append(result, types.KeyValue{Key: strings.TrimPrefix(kv.Key, prefix), Value: kv.Value})
+		}
+	}
+	return result
+}
+
+// snapshotMetadataKeys returns the keys of the volume metadata that hold the
+// tags of the snapshot.
+func snapshotMetadataKeys(snapshotID string, kvs []types.KeyValue) []string {
+	prefix := snapshotTagPrefix(snapshotID)
+	var keys []string
+	for _, kv := range kvs {
+		if strings.HasPrefix(kv.Key, prefix) {
+			keys = append(keys, kv.Key)
+		}
+	}
+	return keys
+}
+
+// isSnapshotCopyVolume returns true if the volume metadata marks a volume
+// created by SnapshotCopy.
+func isSnapshotCopyVolume(kvs []types.KeyValue) bool {
+	for _, kv := range kvs {
+		if kv.Key == snapshotCopyTagKey {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,13 @@
 package vmware
 
 import (
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+	vslmtypes "github.com/vmware/govmomi/vslm/types"
 	. "gopkg.in/check.v1"
+
+	"github.com/kanisterio/kanister/pkg/blockstorage"
 )
 
 type VMWareConversionSuite struct{}
@@ -38,3 +44,87 @@ func (s *VMWareConversionSuite) TestSnapshotIDConversion(c *C) {
 		}
 	}
 }
+
+func (s *VMWareConversionSuite) TestSnapshotTagsMetadata(c *C) {
+	tags := map[string]string{"a": "1", "b": "2"}
+	kvs := convertSnapshotTagsToKeyValue("snap1", tags)
+	c.Assert(kvs, HasLen, 2)
+	for _, kv := range kvs {
+		c.Check(kv.Key[:len(snapshotTagPrefix("snap1"))], Equals, snapshotTagPrefix("snap1"))
+	}
+	kvs = append(kvs, convertSnapshotTagsToKeyValue("snap2", map[string]string{"c": "3"})...)
+	kvs = append(kvs, types.KeyValue{Key: "vol", Value: "4"})
+
+	c.Check(blockstorage.KeyValueToMap(convertKeyValueToTags(filterVolumeMetadata(kvs))), DeepEquals, map[string]string{"vol": "4"})
+	c.Check(blockstorage.KeyValueToMap(convertKeyValueToTags(filterSnapshotMetadata("snap1", kvs))), DeepEquals, tags)
+	c.Check(blockstorage.KeyValueToMap(convertKeyValueToTags(filterSnapshotMetadata("snap2", kvs))), DeepEquals, map[string]string{"c": "3"})
+	c.Check(filterSnapshotMetadata("snap3", kvs), HasLen, 0)
+
+	keys := snapshotMetadataKeys("snap1", kvs)
+	c.Check(keys, HasLen, 2)
+	for _, k := range keys {
+		c.Check(k[:len(snapshotTagPrefix("snap1"))], Equals, snapshotTagPrefix("snap1"))
+	}
+	c.Check(snapshotMetadataKeys("snap3", kvs), HasLen, 0)
+
+	c.Check(isSnapshotCopyVolume(kvs), Equals, false)
+	c.Check(isSnapshotCopyVolume(append(kvs, types.KeyValue{Key: snapshotCopyTagKey, Value: "true"})), Equals, true)
+}
+
+func (s *VMWareConversionSuite) TestSnapshotFromInfo(c *C) {
+	vol := &blockstorage.Volume{
+		ID:   "vol1",
+		Size: 2,
+		Tags: blockstorage.MapToKeyValue(map[string]string{"vol": "4"}),
+	}
+	kvs := append(convertSnapshotTagsToKeyValue("snap1", map[string]string{"a": "1"}), types.KeyValue{Key: "vol", Value: "4"})
+	info := &types.VStorageObjectSnapshotInfoVStorageObjectSnapshot{Id: &types.ID{Id: "snap1"}}
+	snap, err := snapshotFromInfo(vol, info, kvs)
+	c.Assert(err, IsNil)
+	c.Check(snap.ID, Equals, "vol1:snap1")
+	c.Check(snap.Size, Equals, int64(2))
+	c.Check(blockstorage.KeyValueToMap(snap.Tags), DeepEquals, map[string]string{"a": "1", "vol": "4"})
+}
+
+func (s *VMWareConversionSuite) TestConvertFromQueryResultToVolume(c *C) {
+	now := time.Now()
+	res := &vslmtypes.VslmVsoVStorageObjectResult{
+		Id:           types.ID{Id: "1234-abcd-5678-9213"},
+		CapacityInMB: 2048,
+		CreateTime:   &now,
+		Metadata: append(
+			convertSnapshotTagsToKeyValue("snap1", map[string]string{"a": "1"}),
+			types.KeyValue{Key: "vol", Value: "2"},
+		),
+	}
+	vol := convertFromQueryResultToVolume(res)
+	c.Check(vol.ID, Equals, "1234-abcd-5678-9213")
+	c.Check(vol.Type, Equals, blockstorage.TypeFCD)
+	c.Check(vol.Size, Equals, int64(2))
+	c.Check(vol.CreationTime, Equals, blockstorage.TimeStamp(now))
+	c.Check(blockstorage.KeyValueToMap(vol.Tags), DeepEquals, map[string]string{"vol": "2"})
+}
+
+func (s *VMWareConversionSuite) TestConvertFromObjectToVolume(c *C) {
+	vso := &types.VStorageObject{
+		Config: types.VStorageObjectConfigInfo{
+			BaseConfigInfo: types.BaseConfigInfo{
+				Id: types.ID{Id: "1234-abcd-5678-9213"},
+				Backing: &types.BaseConfigInfoDiskFileBackingInfo{
+					BaseConfigInfoFileBackingInfo: types.BaseConfigInfoFileBackingInfo{
+						BaseConfigInfoBackingInfo: types.BaseConfigInfoBackingInfo{
+							Datastore: types.ManagedObjectReference{Type: "Datastore", Value: "datastore-1"},
+						},
+					},
+				},
+			},
+			CapacityInMB: 1024,
+		},
+	}
+	vol, err := convertFromObjectToVolume(vso)
+	c.Assert(err, IsNil)
+	c.Check(vol.ID, Equals, "1234-abcd-5678-9213")
+	c.Check(vol.Size, Equals, int64(1))
+	c.Check(vol.Attributes[DatastoreAttributeKey], Equals, "datastore-1")
+	c.Check(diskBackingSpec("datastore-1").Datastore, DeepEquals, vso.Config.Backing.GetBaseConfigInfoBackingInfo().Datastore)
+}
@@ -13,9 +13,12 @@ import (
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/govmomi/vslm"
+	vslmtypes "github.com/vmware/govmomi/vslm/types"
 
 	"github.com/kanisterio/kanister/pkg/blockstorage"
 	ktags "github.com/kanisterio/kanister/pkg/blockstorage/tags"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
 )
 
 var _ blockstorage.Provider = (*fcdProvider)(nil)
@@ -31,14 +34,33 @@ const (
 	VSphereUsernameKey = "VSphereUsername"
 	// VSpherePasswordKey represents key for the password.
 	VSpherePasswordKey = "VSpherePasswordKey"
+	// VSphereDatastoreKey represents key for the managed object ID of the
+	// datastore new volumes are created in. It is optional.
+	VSphereDatastoreKey = "VSphereDatastore"
+
+	// DatastoreAttributeKey is the volume attribute holding the managed
+	// object ID of the datastore backing the volume. It may be set by the
+	// caller of VolumeCreate and SnapshotCopy to choose the datastore.
+	DatastoreAttributeKey = "datastore"
 
 	noDescription   = ""
 	defaultWaitTime = 10 * time.Minute
+	listPageSize    = 100
 )
 
 type fcdProvider struct {
-	gom *vslm.GlobalObjectManager
-	cns *cns.Client
+	gom       *vslm.GlobalObjectManager
+	cns       *cns.Client
+	datastore string
+}
+
+func init() {
+	_ = blockstorage.RegisterProvider(blockstorage.TypeFCD, func(ctx context.Context, config map[string]string) (blockstorage.Provider, error) {
+		return NewProvider(config)
+	}, blockstorage.ConfigSchema{
+		Required: []string{VSphereEndpointKey, VSphereUsernameKey, VSpherePasswordKey},
+		Optional: []string{VSphereDatastoreKey},
+	})
 }
 
 // NewProvider creates new VMWare FCD provider with the config.
@@ -85,8 +107,9 @@ func NewProvider(config map[string]string) (blockstorage.Provider, error) {
 	}
 	gom := vslm.NewGlobalObjectManager(vslmCli)
 	return &fcdProvider{
-		cns: cnsCli,
-		gom: gom,
+		cns:       cnsCli,
+		gom:       gom,
+		datastore: config[VSphereDatastoreKey],
 	}, nil
 }
 
@@ -99,7 +122,41 @@ func (p *fcdProvider) Type() blockstorage.Type {
 }
 
 func (p *fcdProvider) VolumeCreate(ctx context.Context, volume blockstorage.Volume) (*blockstorage.Volume, error) {
-	return nil, errors.New("Not implemented")
+	ds := p.volumeDatastore(&volume)
+	if ds == "" {
+		return nil, errors.New("Failed to find datastore for the volume")
+	}
+	tags := ktags.GetTags(blockstorage.KeyValueToMap(volume.Tags))
+	spec := types.VslmCreateSpec{
+		Name:         uuid.NewV1().String(),
+		BackingSpec:  diskBackingSpec(ds),
+		CapacityInMB: volume.Size * 1024,
+		Metadata:     convertTagsToKeyValue(tags),
+	}
+	task, err := p.gom.CreateDisk(ctx, spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create disk")
+	}
+	res, err := task.Wait(ctx, defaultWaitTime)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to wait on task")
+	}
+	obj, ok := res.(types.VStorageObject)
+	if !ok {
+		return nil, errors.New("Wrong type returned")
+	}
+	return p.VolumeGet(ctx, obj.Config.Id.Id, "")
+}
+
+// volumeDatastore returns the datastore set in the volume attributes, falling
+// back to the datastore from the provider config.
+func (p *fcdProvider) volumeDatastore(volume *blockstorage.Volume) string {
+	if volume != nil {
+		if ds, ok := volume.Attributes[DatastoreAttributeKey]; ok && ds != "" {
+			return ds
+		}
+	}
+	return p.datastore
 }
 
 func (p *fcdProvider) VolumeCreateFromSnapshot(ctx context.Context, snapshot blockstorage.Snapshot, tags map[string]string) (*blockstorage.Volume, error) {
@@ -134,6 +191,9 @@ func (p *fcdProvider) VolumeCreateFromSnapshot(ctx context.Context, snapshot blo
 
 func (p *fcdProvider) VolumeDelete(ctx context.Context, volume *blockstorage.Volume) error {
 	task, err := p.gom.Delete(ctx, vimID(volume.ID))
+	if isNotFound(err) {
+		return nil
+	}
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete the disk")
 	}
@@ -142,24 +202,74 @@ func (p *fcdProvider) VolumeDelete(ctx context.Context, volume *blockstorage.Vol
 }
 
 func (p *fcdProvider) VolumeGet(ctx context.Context, id string, zone string) (*blockstorage.Volume, error) {
+	vol, _, err := p.volumeGet(ctx, id)
+	return vol, err
+}
+
+// volumeGet returns the volume along with its metadata, which includes the
+// tags of its snapshots.
+func (p *fcdProvider) volumeGet(ctx context.Context, id string) (*blockstorage.Volume, []types.KeyValue, error) {
 	obj, err := p.gom.Retrieve(ctx, vimID(id))
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to query the disk")
+		return nil, nil, errors.Wrap(err, "Failed to query the disk")
 	}
 	kvs, err := p.gom.RetrieveMetadata(ctx, vimID(id), nil, "")
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to get volume metadata")
+		return nil, nil, errors.Wrap(err, "Failed to get volume metadata")
 	}
 	vol, err := convertFromObjectToVolume(obj)
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to convert object to volume")
+		return nil, nil, errors.Wrap(err, "Failed to convert object to volume")
 	}
-	vol.Tags = convertKeyValueToTags(kvs)
-	return vol, nil
+	vol.Tags = convertKeyValueToTags(filterVolumeMetadata(kvs))
+	return vol, kvs, nil
 }
 
+// SnapshotCopy copies the snapshot by creating a new FCD from it and taking
+// a snapshot of that FCD. The new FCD is created in the datastore set in the
+// attributes of `to.Volume`, or in the datastore of the source volume if it
+// is not set. The new FCD backs the copied snapshot, so it is tagged for
+// SnapshotDelete to delete it along with the copied snapshot.
 func (p *fcdProvider) SnapshotCopy(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot) (*blockstorage.Snapshot, error) {
-	return nil, errors.New("Not implemented")
+	if to.ID != "" {
+		return nil, errors.Errorf("Snapshot %v destination ID must be empty", to)
+	}
+	tags := ktags.Union(blockstorage.KeyValueToMap(from.Tags), blockstorage.KeyValueToMap(to.Tags))
+	vol, err := p.VolumeCreateFromSnapshot(ctx, from, map[string]string{snapshotCopyTagKey: "true"})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create volume from snapshot")
+	}
+	snap, err := p.snapshotCopyVolume(ctx, vol, to, tags)
+	if err != nil {
+		if dErr := p.VolumeDelete(ctx, vol); dErr != nil {
+			log.WithError(dErr).Print("Failed to delete volume of failed snapshot copy", field.M{"VolumeID": vol.ID})
+		}
+		return nil, err
+	}
+	return snap, nil
+}
+
+// snapshotCopyVolume moves the volume created by SnapshotCopy to the
+// datastore of `to` and snapshots it.
+func (p *fcdProvider) snapshotCopyVolume(ctx context.Context, vol *blockstorage.Volume, to blockstorage.Snapshot, tags map[string]string) (*blockstorage.Snapshot, error) {
+	if ds := p.volumeDatastore(to.Volume); ds != "" && ds != vol.Attributes[DatastoreAttributeKey] {
+		spec := types.VslmRelocateSpec{
+			VslmMigrateSpec: types.VslmMigrateSpec{
+				BackingSpec: diskBackingSpec(ds),
+			},
+		}
+		task, err := p.gom.Relocate(ctx, vimID(vol.ID), spec)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to relocate disk")
+		}
+		if _, err = task.Wait(ctx, defaultWaitTime); err != nil {
+			return nil, errors.Wrap(err, "Failed to wait on task")
+		}
+		if vol, err = p.VolumeGet(ctx, vol.ID, ""); err != nil {
+			return nil, errors.Wrap(err, "Failed to get volume")
+		}
+	}
+	return p.SnapshotCreate(ctx, *vol, tags)
 }
 
 func (p *fcdProvider) SnapshotCreate(ctx context.Context, volume blockstorage.Volume, tags map[string]string) (*blockstorage.Snapshot, error) {
@@ -175,6 +285,9 @@ func (p *fcdProvider) SnapshotCreate(ctx context.Context, volume blockstorage.Vo
 	if !ok {
 		return nil, errors.New("Unexpected type")
 	}
+	if err = p.setTagsSnapshot(ctx, snapshotFullID(volume.ID, id.Id), ktags.GetTags(tags)); err != nil {
+		return nil, errors.Wrap(err, "Failed to set tags")
+	}
 	snap, err := p.SnapshotGet(ctx, snapshotFullID(volume.ID, id.Id))
 	if err != nil {
 		return nil, err
@@ -196,11 +309,43 @@ func (p *fcdProvider) SnapshotDelete(ctx context.Context, snapshot *blockstorage
 		return errors.Wrap(err, "Cannot infer volume ID from full snapshot ID")
 	}
 	task, err := p.gom.DeleteSnapshot(ctx, vimID(volID), vimID(snapshotID))
+	if isNotFound(err) {
+		return nil
+	}
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete snapshot")
 	}
-	_, err = task.Wait(ctx, defaultWaitTime)
-	return err
+	if _, err = task.Wait(ctx, defaultWaitTime); err != nil {
+		return errors.Wrap(err, "Failed to wait on task")
+	}
+	// Snapshot tags are stored in the volume metadata and need to be removed
+	// separately.
+	kvs, err := p.gom.RetrieveMetadata(ctx, vimID(volID), nil, "")
+	if err != nil {
+		return errors.Wrap(err, "Failed to get volume metadata")
+	}
+	if keys := snapshotMetadataKeys(snapshotID, kvs); len(keys) != 0 {
+		task, err = p.gom.UpdateMetadata(ctx, vimID(volID), nil, keys)
+		if err != nil {
+			return errors.Wrap(err, "Failed to update metadata")
+		}
+		if _, err = task.Wait(ctx, defaultWaitTime); err != nil {
+			return errors.Wrap(err, "Failed to wait on task")
+		}
+	}
+	if !isSnapshotCopyVolume(kvs) {
+		return nil
+	}
+	// The volume was created by SnapshotCopy to back the copied snapshot,
+	// so it is deleted along with its last snapshot.
+	results, err := p.gom.RetrieveSnapshotInfo(ctx, vimID(volID))
+	if err != nil {
+		return errors.Wrap(err, "Failed to get snapshot info")
+	}
+	if len(results) != 0 {
+		return nil
+	}
+	return p.VolumeDelete(ctx, &blockstorage.Volume{ID: volID})
 }
 
 func (p *fcdProvider) SnapshotGet(ctx context.Context, id string) (*blockstorage.Snapshot, error) {
@@ -208,34 +353,52 @@ func (p *fcdProvider) SnapshotGet(ctx context.Context, id string) (*blockstorage
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot infer volume ID from full snapshot ID")
 	}
+	vol, kvs, err := p.volumeGet(ctx, volID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get volume")
+	}
 	results, err := p.gom.RetrieveSnapshotInfo(ctx, vimID(volID))
+	if isNotFound(err) {
+		return nil, errors.Wrap(err, "Failed to find snapshot")
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to get snapshot info")
 	}
 	for _, result := range results {
 		if result.Id.Id == snapshotID {
-			snapshot, err := convertFromObjectToSnapshot(&result, volID)
-			if err != nil {
-				return nil, errors.Wrap(err, "Failed to convert object to snapshot")
-			}
-			snapID := vimID(snapshotID)
-			kvs, err := p.gom.RetrieveMetadata(ctx, vimID(volID), &snapID, "")
-			if err != nil {
-				return nil, errors.Wrap(err, "Failed to get snapshot metadata")
-			}
-			snapshot.Tags = convertKeyValueToTags(kvs)
-			return snapshot, nil
+			return snapshotFromInfo(vol, &result, kvs)
 		}
 	}
 	return nil, errors.New("Failed to find snapshot")
 }
 
+// snapshotFromInfo converts the snapshot info of the volume and populates its
+// tags from the volume metadata kvs. The tags are the tags of the volume along
+// with the tags set on the snapshot.
+func snapshotFromInfo(vol *blockstorage.Volume, info *types.VStorageObjectSnapshotInfoVStorageObjectSnapshot, kvs []types.KeyValue) (*blockstorage.Snapshot, error) {
+	snapshot, err := convertFromObjectToSnapshot(info, vol.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert object to snapshot")
+	}
+	tags := ktags.Union(
+		blockstorage.KeyValueToMap(vol.Tags),
+		blockstorage.KeyValueToMap(convertKeyValueToTags(filterSnapshotMetadata(info.Id.Id, kvs))),
+	)
+	snapshot.Tags = blockstorage.MapToKeyValue(tags)
+	snapshot.Size = vol.Size
+	snapshot.Volume = vol
+	return snapshot, nil
+}
+
 func (p *fcdProvider) SetTags(ctx context.Context, resource interface{}, tags map[string]string) error {
 	switch r := resource.(type) {
 	case *blockstorage.Volume:
 		return p.setTagsVolume(ctx, r, tags)
 	case *blockstorage.Snapshot:
-		return nil
+		if r == nil {
+			return errors.New("Empty snapshot")
+		}
+		return p.setTagsSnapshot(ctx, r.ID, tags)
 	default:
 		return errors.New("Unsupported type for resource")
 	}
@@ -256,10 +419,105 @@ func (p *fcdProvider) setTagsVolume(ctx context.Context, volume *blockstorage.Vo
 	return nil
 }
 
+// setTagsSnapshot stores the snapshot tags in the metadata of its volume since
+// FCD snapshots don't have metadata of their own.
+func (p *fcdProvider) setTagsSnapshot(ctx context.Context, id string, tags map[string]string) error {
+	volID, snapshotID, err := splitSnapshotFullID(id)
+	if err != nil {
+		return errors.Wrap(err, "Cannot infer volume ID from full snapshot ID")
+	}
+	task, err := p.gom.UpdateMetadata(ctx, vimID(volID), convertSnapshotTagsToKeyValue(snapshotID, tags), nil)
+	if err != nil {
+		return errors.Wrap(err, "Failed to update metadata")
+	}
+	_, err = task.Wait(ctx, defaultWaitTime)
+	if err != nil {
+		return errors.Wrap(err, "Failed to wait on task")
+	}
+	return nil
+}
+
 func (p *fcdProvider) VolumesList(ctx context.Context, tags map[string]string, zone string) ([]*blockstorage.Volume, error) {
-	return nil, errors.New("Not implemented")
+	objs, err := p.objectsList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*blockstorage.Volume, 0, len(objs))
+	for i := range objs {
+		vol := convertFromQueryResultToVolume(&objs[i])
+		if ktags.IsSubset(blockstorage.KeyValueToMap(vol.Tags), tags) {
+			result = append(result, vol)
+		}
+	}
+	return result, nil
+}
+
+// objectsList lists all the FCDs along with their metadata, a page at a time
+// ordered by ID.
+func (p *fcdProvider) objectsList(ctx context.Context) ([]vslmtypes.VslmVsoVStorageObjectResult, error) {
+	var objs []vslmtypes.VslmVsoVStorageObjectResult
+	lastID := ""
+	for {
+		query := []vslmtypes.VslmVsoVStorageObjectQuerySpec{
+			{
+				QueryField:    string(vslmtypes.VslmVsoVStorageObjectQuerySpecQueryFieldEnumId),
+				QueryOperator: string(vslmtypes.VslmVsoVStorageObjectQuerySpecQueryOperatorEnumGreaterThan),
+				QueryValue:    []string{lastID},
+			},
+		}
+		res, err := p.gom.ListObjectsForSpec(ctx, query, listPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list disks")
+		}
+		for i := range res.QueryResults {
+			if res.QueryResults[i].Error != nil {
+				continue
+			}
+			objs = append(objs, res.QueryResults[i])
+		}
+		if res.AllRecordsReturned || len(res.Id) == 0 {
+			return objs, nil
+		}
+		lastID = res.Id[len(res.Id)-1].Id
+	}
 }
 
+// SnapshotsList lists the snapshots of all the FCDs. The tags of the snapshots
+// are taken from the metadata returned by the listing, so that only the
+// snapshot info is retrieved for each FCD.
 func (p *fcdProvider) SnapshotsList(ctx context.Context, tags map[string]string) ([]*blockstorage.Snapshot, error) {
-	return nil, errors.New("Not implemented")
+	objs, err := p.objectsList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var snaps []*blockstorage.Snapshot
+	for i := range objs {
+		vol := convertFromQueryResultToVolume(&objs[i])
+		results, err := p.gom.RetrieveSnapshotInfo(ctx, vimID(vol.ID))
+		if isNotFound(err) {
+			// The volume was deleted after it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get snapshot info")
+		}
+		for j := range results {
+			snap, err := snapshotFromInfo(vol, &results[j], objs[i].Metadata)
+			if err != nil {
+				return nil, err
+			}
+			if ktags.IsSubset(blockstorage.KeyValueToMap(snap.Tags), tags) {
+				snaps = append(snaps, snap)
+			}
+		}
+	}
+	return snaps, nil
+}
+
+func isNotFound(err error) bool {
+	if err == nil || !soap.IsSoapFault(err) {
+		return false
+	}
+	_, ok := soap.ToSoapFault(err).VimFault().(types.NotFound)
+	return ok
 }
@@ -28,6 +28,7 @@ import (
 type CrV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ActionSetsGetter
+	BackupSchedulesGetter
 	BlueprintsGetter
 	ProfilesGetter
 }
@@ -41,6 +42,10 @@ func (c *CrV1alpha1Client) ActionSets(namespace string) ActionSetInterface {
 	return newActionSets(c, namespace)
 }
 
+func (c *CrV1alpha1Client) BackupSchedules(namespace string) BackupScheduleInterface {
+	return newBackupSchedules(c, namespace)
+}
+
 func (c *CrV1alpha1Client) Blueprints(namespace string) BlueprintInterface {
 	return newBlueprints(c, namespace)
 }
@@ -32,6 +32,10 @@ func (c *FakeCrV1alpha1) ActionSets(namespace string) v1alpha1.ActionSetInterfac
 	return &FakeActionSets{c, namespace}
 }
 
+func (c *FakeCrV1alpha1) BackupSchedules(namespace string) v1alpha1.BackupScheduleInterface {
+	return &FakeBackupSchedules{c, namespace}
+}
+
 func (c *FakeCrV1alpha1) Blueprints(namespace string) v1alpha1.BlueprintInterface {
 	return &FakeBlueprints{c, namespace}
 }
@@ -20,6 +20,8 @@ package v1alpha1
 
 type ActionSetExpansion interface{}
 
+type BackupScheduleExpansion interface{}
+
 type BlueprintExpansion interface{}
 
 type ProfileExpansion interface{}
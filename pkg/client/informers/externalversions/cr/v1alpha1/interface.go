@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// ActionSets returns a ActionSetInformer.
 	ActionSets() ActionSetInformer
+	// BackupSchedules returns a BackupScheduleInformer.
+	BackupSchedules() BackupScheduleInformer
 	// Blueprints returns a BlueprintInformer.
 	Blueprints() BlueprintInformer
 	// Profiles returns a ProfileInformer.
@@ -48,6 +50,11 @@ func (v *version) ActionSets() ActionSetInformer {
 	return &actionSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// BackupSchedules returns a BackupScheduleInformer.
+func (v *version) BackupSchedules() BackupScheduleInformer {
+	return &backupScheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Blueprints returns a BlueprintInformer.
 func (v *version) Blueprints() BlueprintInformer {
 	return &blueprintInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -55,6 +55,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=cr, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("actionsets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().ActionSets().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("backupschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().BackupSchedules().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("blueprints"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().Blueprints().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("profiles"):
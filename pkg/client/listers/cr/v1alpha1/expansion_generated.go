@@ -26,6 +26,14 @@ type ActionSetListerExpansion interface{}
 // ActionSetNamespaceLister.
 type ActionSetNamespaceListerExpansion interface{}
 
+// BackupScheduleListerExpansion allows custom methods to be added to
+// BackupScheduleLister.
+type BackupScheduleListerExpansion interface{}
+
+// BackupScheduleNamespaceListerExpansion allows custom methods to be added to
+// BackupScheduleNamespaceLister.
+type BackupScheduleNamespaceListerExpansion interface{}
+
 // BlueprintListerExpansion allows custom methods to be added to
 // BlueprintLister.
 type BlueprintListerExpansion interface{}
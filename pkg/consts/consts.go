@@ -1,9 +1,17 @@
 package consts
 
 const (
-	ActionsetNameKey         = "ActionSet"
-	PodNameKey               = "Pod"
-	ContainerNameKey         = "Container"
-	PhaseNameKey             = "Phase"
-	GoogleCloudCredsFilePath = "/tmp/creds.txt"
+	ActionsetNameKey           = "ActionSet"
+	PodNameKey                 = "Pod"
+	ContainerNameKey           = "Container"
+	PhaseNameKey               = "Phase"
+	GoogleCloudCredsFilePath   = "/tmp/creds.txt"
+	ResticPasswordFilePath     = "/tmp/restic-password"
+	ActionSetUIDLabel          = "kanister.io/actionset-uid"
+	DeferPhaseLabel            = "kanister.io/defer-phase"
+	BackupScheduleLabel        = "kanister.io/backupschedule"
+	RetentionLabel             = "kanister.io/retention"
+	ExpiredActionSetAnnotation = "kanister.io/expired-actionset"
+	RetentionAttemptAnnotation = "kanister.io/retention-attempt"
+	BackupScheduleNameKey      = "BackupSchedule"
 )
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	customresource "github.com/kanisterio/kanister/pkg/customresource"
 	"github.com/pkg/errors"
@@ -46,7 +47,9 @@ import (
 	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/eventer"
 	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/metrics"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/reconcile"
 	"github.com/kanisterio/kanister/pkg/validate"
@@ -60,6 +63,7 @@ type Controller struct {
 	dynClient        dynamic.Interface
 	recorder         record.EventRecorder
 	actionSetTombMap sync.Map
+	scheduleMap      sync.Map
 }
 
 // New create controller for watching kanister custom resources created
@@ -69,7 +73,8 @@ func New(c *rest.Config) *Controller {
 	}
 }
 
-// StartWatch watches for instances of ActionSets and Blueprints acts on them.
+// StartWatch watches for instances of ActionSets, Blueprints and
+// BackupSchedules and acts on them.
 func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
 	crClient, err := versioned.NewForConfig(c.config)
 	if err != nil {
@@ -93,8 +98,9 @@ func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
 	c.recorder = eventer.NewEventRecorder(c.clientset, "Kanister Controller")
 
 	for cr, o := range map[customresource.CustomResource]runtime.Object{
-		crv1alpha1.ActionSetResource: &crv1alpha1.ActionSet{},
-		crv1alpha1.BlueprintResource: &crv1alpha1.Blueprint{},
+		crv1alpha1.ActionSetResource:      &crv1alpha1.ActionSet{},
+		crv1alpha1.BlueprintResource:      &crv1alpha1.Blueprint{},
+		crv1alpha1.BackupScheduleResource: &crv1alpha1.BackupSchedule{},
 	} {
 		resourceHandlers := cache.ResourceEventHandlerFuncs{
 			AddFunc:    c.onAdd,
@@ -123,6 +129,9 @@ func checkCRAccess(cli versioned.Interface, ns string) error {
 	if _, err := cli.CrV1alpha1().Profiles(ns).List(v1.ListOptions{}); err != nil {
 		return errors.Wrap(err, "Could not list Profiles")
 	}
+	if _, err := cli.CrV1alpha1().BackupSchedules(ns).List(v1.ListOptions{}); err != nil {
+		return errors.Wrap(err, "Could not list BackupSchedules")
+	}
 	return nil
 }
 
@@ -143,6 +152,10 @@ func (c *Controller) onAdd(obj interface{}) {
 		if err := c.onAddBlueprint(v); err != nil {
 			log.Error().WithError(err).Print("Callback onAddBlueprint() failed")
 		}
+	case *crv1alpha1.BackupSchedule:
+		if err := c.onAddBackupSchedule(v); err != nil {
+			c.logAndErrorEvent(context.TODO(), "Callback onAddBackupSchedule() failed:", "Error", err, v)
+		}
 	default:
 		objType := fmt.Sprintf("%T", o)
 		log.Error().Print("Unknown object type", field.M{"ObjectType": objType})
@@ -164,6 +177,11 @@ func (c *Controller) onUpdate(oldObj, newObj interface{}) {
 		if err := c.onUpdateBlueprint(old, new); err != nil {
 			c.logAndErrorEvent(context.TODO(), "Callback onUpdateBlueprint() failed:", "Error", err, new)
 		}
+	case *crv1alpha1.BackupSchedule:
+		new := newObj.(*crv1alpha1.BackupSchedule)
+		if err := c.onUpdateBackupSchedule(old, new); err != nil {
+			c.logAndErrorEvent(context.TODO(), "Callback onUpdateBackupSchedule() failed:", "Error", err, new)
+		}
 	default:
 		objType := fmt.Sprintf("%T", oldObj)
 		log.Error().Print("Unknown object type", field.M{"ObjectType": objType})
@@ -182,6 +200,10 @@ func (c *Controller) onDelete(obj interface{}) {
 		if err := c.onDeleteBlueprint(v); err != nil {
 			c.logAndErrorEvent(context.TODO(), "Callback onDeleteBlueprint() failed:", "Error", err, v)
 		}
+	case *crv1alpha1.BackupSchedule:
+		if err := c.onDeleteBackupSchedule(v); err != nil {
+			log.Error().WithError(err).Print("Callback onDeleteBackupSchedule() failed")
+		}
 	default:
 		objType := fmt.Sprintf("%T", obj)
 		log.Error().Print("Unknown object type", field.M{"ObjectType": objType})
@@ -208,6 +230,10 @@ func (c *Controller) onAddActionSet(as *crv1alpha1.ActionSet) error {
 }
 
 func (c *Controller) onAddBlueprint(bp *crv1alpha1.Blueprint) error {
+	if err := validate.Blueprint(bp); err != nil {
+		c.logAndErrorEvent(context.TODO(), fmt.Sprintf("Invalid blueprint %s:", bp.GetName()), "Invalid", err, bp)
+		return nil
+	}
 	c.logAndSuccessEvent(context.TODO(), fmt.Sprintf("Added blueprint %s", bp.GetName()), "Added", bp)
 	return nil
 }
@@ -217,6 +243,12 @@ func (c *Controller) onUpdateActionSet(oldAS, newAS *crv1alpha1.ActionSet) error
 		log.Print("Updated ActionSet", field.M{"ActionSetName": newAS.Name})
 		return err
 	}
+	if newAS.Status != nil && (oldAS.Status == nil || oldAS.Status.State != newAS.Status.State) {
+		metrics.ActionSets.WithLabelValues(string(newAS.Status.State)).Inc()
+	}
+	if newAS.Spec.Cancel && newAS.Status != nil && (newAS.Status.State == crv1alpha1.StatePending || newAS.Status.State == crv1alpha1.StateRunning) {
+		return c.cancelActionSet(newAS)
+	}
 	if newAS.Status == nil || newAS.Status.State != crv1alpha1.StateRunning {
 		if newAS.Status == nil {
 			log.Print("Updated ActionSet", field.M{"Actionset": newAS.Name, "Status": "nil"})
@@ -246,25 +278,96 @@ func (c *Controller) onUpdateActionSet(oldAS, newAS *crv1alpha1.ActionSet) error
 
 func (c *Controller) onUpdateBlueprint(oldBP, newBP *crv1alpha1.Blueprint) error {
 	log.Print("Updated Blueprint", field.M{"BlueprintName": newBP.Name})
+	if err := validate.Blueprint(newBP); err != nil {
+		c.logAndErrorEvent(context.TODO(), fmt.Sprintf("Invalid blueprint %s:", newBP.GetName()), "Invalid", err, newBP)
+	}
 	return nil
 }
 
 func (c *Controller) onDeleteActionSet(as *crv1alpha1.ActionSet) error {
 	asName := as.GetName()
 	log.Print("Deleted ActionSet", field.M{"ActionSetName": asName})
+	c.killActionSet(asName)
+	return nil
+}
+
+// killActionSet stops the goroutines executing the actions of an ActionSet.
+func (c *Controller) killActionSet(asName string) {
 	v, ok := c.actionSetTombMap.Load(asName)
 	if !ok {
-		return nil
+		return
 	}
 	t, castOk := v.(*tomb.Tomb)
 	if !castOk {
-		return nil
+		return
 	}
 	t.Kill(nil) // TODO: @Deepika Give reason for ActionSet kill
 	c.actionSetTombMap.Delete(asName)
+}
+
+// cancelActionSet stops the execution of an ActionSet, marks it as cancelled
+// and deletes the pods that were created while executing its phases. If an
+// action has a deferPhase, the pods are deleted by the action once its
+// deferPhase has finished instead.
+func (c *Controller) cancelActionSet(as *crv1alpha1.ActionSet) error {
+	ctx := field.Context(context.Background(), consts.ActionsetNameKey, as.GetName())
+	c.killActionSet(as.GetName())
+	if err := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		cancelActionSetStatus(ras)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if !hasDeferPhase(as) {
+		if err := c.deleteActionSetPods(ctx, as); err != nil {
+			return err
+		}
+	}
+	c.logAndSuccessEvent(ctx, fmt.Sprintf("Cancelled ActionSet %s", as.GetName()), "Cancelled", as)
+	return nil
+}
+
+// hasDeferPhase returns true if any action of the ActionSet has a deferPhase.
+func hasDeferPhase(as *crv1alpha1.ActionSet) bool {
+	for _, a := range as.Status.Actions {
+		if a.DeferPhase != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteActionSetPods deletes the pods that were labelled with the UID of the
+// ActionSet by a PodRunner. Pods created by a deferPhase are left alone.
+func (c *Controller) deleteActionSetPods(ctx context.Context, as *crv1alpha1.ActionSet) error {
+	sel := fmt.Sprintf("%s=%s,!%s", consts.ActionSetUIDLabel, as.GetUID(), consts.DeferPhaseLabel)
+	pods, err := c.clientset.CoreV1().Pods(v1.NamespaceAll).List(v1.ListOptions{LabelSelector: sel})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list pods created for ActionSet %s", as.GetName())
+	}
+	for i := range pods.Items {
+		log.WithContext(ctx).Print("Deleting pod of cancelled ActionSet", field.M{"PodName": pods.Items[i].GetName(), "Namespace": pods.Items[i].GetNamespace()})
+		if err := kube.DeletePod(ctx, c.clientset, &pods.Items[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// cancelActionSetStatus sets the state of the ActionSet and of its running
+// phases to cancelled.
+func cancelActionSetStatus(as *crv1alpha1.ActionSet) {
+	as.Status.State = crv1alpha1.StateCancelled
+	for i := range as.Status.Actions {
+		for j := range as.Status.Actions[i].Phases {
+			p := &as.Status.Actions[i].Phases[j]
+			if p.State == crv1alpha1.StateRunning {
+				p.State = crv1alpha1.StateCancelled
+			}
+		}
+	}
+}
+
 func (c *Controller) onDeleteBlueprint(bp *crv1alpha1.Blueprint) error {
 	log.Print("Deleted Blueprint ", field.M{"BlueprintName": bp.GetName()})
 	return nil
@@ -326,16 +429,25 @@ func (c *Controller) initialActionStatus(namespace string, a crv1alpha1.ActionSp
 	phases := make([]crv1alpha1.Phase, 0, len(bpa.Phases))
 	for _, p := range bpa.Phases {
 		phases = append(phases, crv1alpha1.Phase{
-			Name:  p.Name,
-			State: crv1alpha1.StatePending,
+			Name:      p.Name,
+			State:     crv1alpha1.StatePending,
+			DependsOn: p.DependsOn,
 		})
 	}
+	var deferPhase *crv1alpha1.Phase
+	if bpa.DeferPhase != nil {
+		deferPhase = &crv1alpha1.Phase{
+			Name:  bpa.DeferPhase.Name,
+			State: crv1alpha1.StatePending,
+		}
+	}
 	return &crv1alpha1.ActionStatus{
-		Name:      a.Name,
-		Object:    a.Object,
-		Blueprint: a.Blueprint,
-		Phases:    phases,
-		Artifacts: bpa.OutputArtifacts,
+		Name:       a.Name,
+		Object:     a.Object,
+		Blueprint:  a.Blueprint,
+		Phases:     phases,
+		DeferPhase: deferPhase,
+		Artifacts:  bpa.OutputArtifacts,
 	}, nil
 
 }
@@ -347,14 +459,28 @@ func (c *Controller) handleActionSet(as *crv1alpha1.ActionSet) (err error) {
 	if as.Status.State != crv1alpha1.StatePending {
 		return nil
 	}
+	if as.Spec.Cancel {
+		cancelActionSetStatus(as)
+		_, err = c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Update(as)
+		return errors.WithStack(err)
+	}
 	as.Status.State = crv1alpha1.StateRunning
 	if as, err = c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Update(as); err != nil {
 		return errors.WithStack(err)
 	}
 	ctx := context.Background()
 	ctx = field.Context(ctx, consts.ActionsetNameKey, as.GetName())
+	if as.Spec.DryRun {
+		return c.dryRunActionSet(ctx, as)
+	}
+	// All of the actions share a tomb so that killing it stops every action
+	// of the ActionSet.
+	var t *tomb.Tomb
+	t, ctx = tomb.WithContext(ctx)
+	runs := make([]func() error, 0, len(as.Status.Actions))
 	for i := range as.Status.Actions {
-		if err = c.runAction(ctx, as, i); err != nil {
+		var run func() error
+		if run, err = c.runAction(ctx, as, i); err != nil {
 			// If runAction returns an error, it is a failure in the synchronous
 			// part of running the action.
 			bpName := as.Spec.Actions[i].Blueprint
@@ -369,83 +495,76 @@ func (c *Controller) handleActionSet(as *crv1alpha1.ActionSet) (err error) {
 			_, err = c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Update(as)
 			return errors.WithStack(err)
 		}
+		runs = append(runs, run)
 	}
+	c.actionSetTombMap.Store(as.Name, t)
+	// The actions are started from a goroutine of the tomb, which keeps it
+	// alive until all of them have been started.
+	t.Go(func() error {
+		for _, run := range runs {
+			t.Go(run)
+		}
+		return nil
+	})
 	log.WithContext(ctx).Print("Created actionset and started executing actions", field.M{"NewActionSetName": as.GetName()})
 	return nil
 }
 
-func (c *Controller) runAction(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) error {
+// runAction prepares an action of the ActionSet and returns the function that
+// executes it. ctx must be the context of the ActionSet's tomb.
+func (c *Controller) runAction(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) (func() error, error) {
 	action := as.Spec.Actions[aIDX]
 	c.logAndSuccessEvent(ctx, fmt.Sprintf("Executing action %s", action.Name), "Started Action", as)
 	bpName := as.Spec.Actions[aIDX].Blueprint
 	bp, err := c.crClient.CrV1alpha1().Blueprints(as.GetNamespace()).Get(bpName, v1.GetOptions{})
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 	tp, err := param.New(ctx, c.clientset, c.dynClient, c.crClient, action)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	phases, err := kanister.GetPhases(*bp, action.Name, action.PreferredVersion, *tp)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	deferPhase, err := kanister.GetDeferPhase(*bp, action.Name, action.PreferredVersion, *tp)
+	if err != nil {
+		return nil, err
 	}
 	ns, name := as.GetNamespace(), as.GetName()
-	var t *tomb.Tomb
-	t, ctx = tomb.WithContext(ctx)
-	c.actionSetTombMap.Store(as.Name, t)
+	podLabels := map[string]string{consts.ActionSetUIDLabel: string(as.GetUID())}
+	// The deferPhase context is not cancelled with the ActionSet so that it
+	// can clean up after a cancelled action. Its pods are labelled separately
+	// so that they aren't deleted when the ActionSet is cancelled.
+	deferCtx := field.Context(context.Background(), consts.ActionsetNameKey, as.GetName())
+	deferCtx = kube.ContextWithPodLabels(deferCtx, podLabels)
+	deferCtx = kube.ContextWithPodLabels(deferCtx, map[string]string{consts.DeferPhaseLabel: "true"})
 	ctx = field.Context(ctx, consts.ActionsetNameKey, as.GetName())
-	t.Go(func() error {
-		for i, p := range phases {
-			ctx = field.Context(ctx, consts.PhaseNameKey, p.Name())
-			c.logAndSuccessEvent(ctx, fmt.Sprintf("Executing phase %s", p.Name()), "Started Phase", as)
-			err = param.InitPhaseParams(ctx, c.clientset, tp, p.Name(), p.Objects())
-			var output map[string]interface{}
-			var msg string
-			if err == nil {
-				output, err = p.Exec(ctx, *bp, action.Name, *tp)
-			} else {
-				msg = fmt.Sprintf("Failed to init phase params: %#v:", as.Status.Actions[aIDX].Phases[i])
-			}
-			var rf func(*crv1alpha1.ActionSet) error
-			if err != nil {
-				rf = func(ras *crv1alpha1.ActionSet) error {
-					ras.Status.State = crv1alpha1.StateFailed
-					ras.Status.Error = crv1alpha1.Error{
-						Message: err.Error(),
-					}
-					ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateFailed
-					return nil
-				}
-			} else {
-				rf = func(ras *crv1alpha1.ActionSet) error {
-					ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateComplete
-					ras.Status.Actions[aIDX].Phases[i].Output = output
-					return nil
+	ctx = kube.ContextWithPodLabels(ctx, podLabels)
+	return func() error {
+		ok := c.runPhases(ctx, as, aIDX, phases, bp, tp)
+		if deferPhase != nil {
+			ok = c.runDeferPhase(deferCtx, as, aIDX, deferPhase, bp, tp) && ok
+			if ctx.Err() != nil {
+				// The action was cancelled. Delete the remaining pods now that
+				// the deferPhase has finished.
+				if err := c.deleteActionSetPods(deferCtx, as); err != nil {
+					log.WithContext(deferCtx).WithError(err).Print("Failed to delete pods of cancelled ActionSet")
 				}
 			}
-			if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, rf); rErr != nil {
-				reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
-				msg := fmt.Sprintf("Failed to update phase: %#v:", as.Status.Actions[aIDX].Phases[i])
-				c.logAndErrorEvent(ctx, msg, reason, rErr, as, bp)
-				return nil
-			}
-			if err != nil {
-				reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
-				if msg == "" {
-					msg = fmt.Sprintf("Failed to execute phase: %#v:", as.Status.Actions[aIDX].Phases[i])
-				}
-				c.logAndErrorEvent(ctx, msg, reason, err, as, bp)
-				return nil
-			}
-			param.UpdatePhaseParams(ctx, tp, p.Name(), output)
-			c.logAndSuccessEvent(ctx, fmt.Sprintf("Completed phase %s", p.Name()), "Ended Phase", as)
+		}
+		if !ok {
+			return nil
 		}
 		// Check if output artifacts are present
 		artTpls := as.Status.Actions[aIDX].Artifacts
 		if len(artTpls) == 0 {
 			// No artifacts, set ActionSetStatus to complete
 			if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, func(ras *crv1alpha1.ActionSet) error {
+				if ras.Spec.Cancel {
+					return nil
+				}
 				ras.Status.State = crv1alpha1.StateComplete
 				return nil
 			}); rErr != nil {
@@ -468,6 +587,9 @@ func (c *Controller) runAction(ctx context.Context, as *crv1alpha1.ActionSet, aI
 			}
 		} else {
 			af = func(ras *crv1alpha1.ActionSet) error {
+				if ras.Spec.Cancel {
+					return nil
+				}
 				ras.Status.Actions[aIDX].Artifacts = arts
 				ras.Status.State = crv1alpha1.StateComplete
 				return nil
@@ -487,8 +609,219 @@ func (c *Controller) runAction(ctx context.Context, as *crv1alpha1.ActionSet, aI
 			return nil
 		}
 		return nil
+	}, nil
+}
+
+// runPhases executes the phases of an action and records their outcome in
+// the action status. Phases are executed once the phases they depend on are
+// complete, up to the parallelism of the action at the same time. No phase is
+// started after a phase fails. It returns false if a phase failed.
+func (c *Controller) runPhases(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int, phases []*kanister.Phase, bp *crv1alpha1.Blueprint, tp *param.TemplateParams) bool {
+	deps, err := kanister.PhaseDependencies(phases)
+	if err != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		c.logAndErrorEvent(ctx, "Failed to resolve phase dependencies:", reason, err, as, bp)
+		return false
+	}
+	var parallelism int
+	if bpa, ok := bp.Actions[as.Spec.Actions[aIDX].Name]; ok {
+		parallelism = bpa.Parallelism
+	}
+	// tpMu guards the phase params in tp, which are updated as phases
+	// complete.
+	var tpMu sync.Mutex
+	return runDAG(deps, parallelism, func(i int) bool {
+		return c.runPhase(ctx, as, aIDX, i, phases[i], bp, tp, &tpMu)
 	})
-	return nil
+}
+
+// runPhase executes a single phase of an action and records its outcome in the
+// action status. It returns false if the phase failed.
+func (c *Controller) runPhase(ctx context.Context, as *crv1alpha1.ActionSet, aIDX, i int, p *kanister.Phase, bp *crv1alpha1.Blueprint, tp *param.TemplateParams, tpMu *sync.Mutex) bool {
+	ns, name := as.GetNamespace(), as.GetName()
+	ctx = field.Context(ctx, consts.PhaseNameKey, p.Name())
+	c.logAndSuccessEvent(ctx, fmt.Sprintf("Executing phase %s", p.Name()), "Started Phase", as)
+	if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, func(ras *crv1alpha1.ActionSet) error {
+		if ras.Spec.Cancel {
+			return nil
+		}
+		ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateRunning
+		return nil
+	}); rErr != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		msg := fmt.Sprintf("Failed to update phase: %#v:", as.Status.Actions[aIDX].Phases[i])
+		c.logAndErrorEvent(ctx, msg, reason, rErr, as, bp)
+		return false
+	}
+	tpMu.Lock()
+	err := param.InitPhaseParams(ctx, c.clientset, tp, p.Name(), p.Objects())
+	ptp := copyTemplateParams(tp)
+	tpMu.Unlock()
+	var output map[string]interface{}
+	var msg string
+	if err == nil {
+		output, err = c.execPhase(ctx, as, aIDX, p, bp, ptp, func(ras *crv1alpha1.ActionSet) *crv1alpha1.Phase {
+			return &ras.Status.Actions[aIDX].Phases[i]
+		})
+	} else {
+		msg = fmt.Sprintf("Failed to init phase params: %#v:", as.Status.Actions[aIDX].Phases[i])
+	}
+	var rf func(*crv1alpha1.ActionSet) error
+	if err != nil {
+		rf = func(ras *crv1alpha1.ActionSet) error {
+			if ras.Spec.Cancel {
+				cancelActionSetStatus(ras)
+				return nil
+			}
+			ras.Status.State = crv1alpha1.StateFailed
+			ras.Status.Error = crv1alpha1.Error{
+				Message: err.Error(),
+			}
+			ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateFailed
+			return nil
+		}
+	} else {
+		rf = func(ras *crv1alpha1.ActionSet) error {
+			if ras.Spec.Cancel {
+				return nil
+			}
+			ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateComplete
+			ras.Status.Actions[aIDX].Phases[i].Output = output
+			return nil
+		}
+	}
+	if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, rf); rErr != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		msg := fmt.Sprintf("Failed to update phase: %#v:", as.Status.Actions[aIDX].Phases[i])
+		c.logAndErrorEvent(ctx, msg, reason, rErr, as, bp)
+		return false
+	}
+	if err != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		if msg == "" {
+			msg = fmt.Sprintf("Failed to execute phase: %#v:", as.Status.Actions[aIDX].Phases[i])
+		}
+		c.logAndErrorEvent(ctx, msg, reason, err, as, bp)
+		return false
+	}
+	tpMu.Lock()
+	param.UpdatePhaseParams(ctx, tp, p.Name(), output)
+	tpMu.Unlock()
+	c.logAndSuccessEvent(ctx, fmt.Sprintf("Completed phase %s", p.Name()), "Ended Phase", as)
+	return true
+}
+
+// copyTemplateParams returns a copy of tp whose phase params can be read while
+// the phase params of tp are updated.
+func copyTemplateParams(tp *param.TemplateParams) *param.TemplateParams {
+	ctp := *tp
+	ctp.Phases = make(map[string]*param.Phase, len(tp.Phases))
+	for k, v := range tp.Phases {
+		p := *v
+		ctp.Phases[k] = &p
+	}
+	return &ctp
+}
+
+// runDeferPhase executes the deferPhase of an action and records its outcome
+// in the action status. If the phases of the action succeeded, a failure of
+// the deferPhase fails the ActionSet. It returns false if the deferPhase failed.
+func (c *Controller) runDeferPhase(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int, p *kanister.Phase, bp *crv1alpha1.Blueprint, tp *param.TemplateParams) bool {
+	ns, name := as.GetNamespace(), as.GetName()
+	ctx = field.Context(ctx, consts.PhaseNameKey, p.Name())
+	c.logAndSuccessEvent(ctx, fmt.Sprintf("Executing deferPhase %s", p.Name()), "Started DeferPhase", as)
+	deferPhaseStatus := func(ras *crv1alpha1.ActionSet) *crv1alpha1.Phase {
+		if ras.Status.Actions[aIDX].DeferPhase == nil {
+			ras.Status.Actions[aIDX].DeferPhase = &crv1alpha1.Phase{Name: p.Name()}
+		}
+		return ras.Status.Actions[aIDX].DeferPhase
+	}
+	if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, func(ras *crv1alpha1.ActionSet) error {
+		deferPhaseStatus(ras).State = crv1alpha1.StateRunning
+		return nil
+	}); rErr != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		c.logAndErrorEvent(ctx, "Failed to update deferPhase:", reason, rErr, as, bp)
+	}
+	var output map[string]interface{}
+	err := param.InitPhaseParams(ctx, c.clientset, tp, p.Name(), p.Objects())
+	msg := "Failed to init deferPhase params:"
+	if err == nil {
+		output, err = c.execPhase(ctx, as, aIDX, p, bp, tp, deferPhaseStatus)
+		msg = "Failed to execute deferPhase:"
+	}
+	if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, func(ras *crv1alpha1.ActionSet) error {
+		dp := deferPhaseStatus(ras)
+		if err == nil {
+			dp.State = crv1alpha1.StateComplete
+			dp.Output = output
+			return nil
+		}
+		dp.State = crv1alpha1.StateFailed
+		if ras.Status.State == crv1alpha1.StateRunning {
+			ras.Status.State = crv1alpha1.StateFailed
+			ras.Status.Error = crv1alpha1.Error{
+				Message: err.Error(),
+			}
+		}
+		return nil
+	}); rErr != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		c.logAndErrorEvent(ctx, "Failed to update deferPhase:", reason, rErr, as, bp)
+		return false
+	}
+	if err != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		c.logAndErrorEvent(ctx, msg, reason, err, as, bp)
+		return false
+	}
+	param.UpdatePhaseParams(ctx, tp, p.Name(), output)
+	c.logAndSuccessEvent(ctx, fmt.Sprintf("Completed deferPhase %s", p.Name()), "Ended DeferPhase", as)
+	return true
+}
+
+// execPhase executes a phase, retrying it as allowed by the phase's retry
+// policy. Attempts are only recorded in the phase status for phases that may
+// be retried, so phases without a retry policy don't pay for the extra write.
+func (c *Controller) execPhase(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int, p *kanister.Phase, bp *crv1alpha1.Blueprint, tp *param.TemplateParams, phaseStatus func(*crv1alpha1.ActionSet) *crv1alpha1.Phase) (map[string]interface{}, error) {
+	ns, name := as.GetNamespace(), as.GetName()
+	b := p.Backoff()
+	for attempt := 1; ; attempt++ {
+		start := v1.Now()
+		output, err := p.Exec(ctx, *bp, as.Spec.Actions[aIDX].Name, *tp)
+		pa := crv1alpha1.PhaseAttempt{
+			Attempt:   attempt,
+			State:     crv1alpha1.StateComplete,
+			StartTime: start,
+			EndTime:   v1.Now(),
+		}
+		if err != nil {
+			pa.State = crv1alpha1.StateFailed
+			pa.Error = crv1alpha1.Error{
+				Message: err.Error(),
+			}
+		}
+		if p.MaxAttempts() > 1 {
+			if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, func(ras *crv1alpha1.ActionSet) error {
+				ps := phaseStatus(ras)
+				ps.Attempts = append(ps.Attempts, pa)
+				return nil
+			}); rErr != nil {
+				log.WithContext(ctx).WithError(rErr).Print("Failed to record phase attempt", field.M{"Attempt": attempt})
+			}
+		}
+		if err == nil || attempt >= p.MaxAttempts() || !p.IsRetryable(err) {
+			return output, err
+		}
+		d := b.Duration()
+		msg := fmt.Sprintf("Attempt %d of %d for phase %s failed, retrying in %s:", attempt, p.MaxAttempts(), p.Name(), d)
+		c.logAndErrorEvent(ctx, msg, "Retrying Phase", err, as)
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "Context done while waiting to retry phase")
+		case <-time.After(d):
+		}
+	}
 }
 
 func (c *Controller) logAndErrorEvent(ctx context.Context, msg, reason string, err error, objects ...runtime.Object) {
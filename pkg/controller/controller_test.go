@@ -38,6 +38,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/poll"
+	"github.com/kanisterio/kanister/pkg/reconcile"
 	"github.com/kanisterio/kanister/pkg/resource"
 	"github.com/kanisterio/kanister/pkg/testutil"
 )
@@ -519,3 +520,156 @@ func (s *ControllerSuite) TestRenderArtifactsFailure(c *C) {
 	err = s.waitOnActionSetState(c, as, crv1alpha1.StateFailed)
 	c.Assert(err, IsNil)
 }
+
+func (s *ControllerSuite) TestCancelActionSet(c *C) {
+	bp := testutil.NewTestBlueprint("Deployment", testutil.WaitFuncName)
+	bp = testutil.BlueprintWithConfigMap(bp)
+	bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+	c.Assert(err, IsNil)
+
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace, kanister.DefaultVersion)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as, err = s.crCli.ActionSets(s.namespace).Create(as)
+	c.Assert(err, IsNil)
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateRunning)
+	c.Assert(err, IsNil)
+
+	err = reconcile.ActionSet(context.Background(), s.crCli, s.namespace, as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		ras.Spec.Cancel = true
+		return nil
+	})
+	c.Assert(err, IsNil)
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateCancelled)
+	c.Assert(err, IsNil)
+	testutil.ReleaseWaitFunc()
+
+	as, err = s.crCli.ActionSets(s.namespace).Get(as.GetName(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(as.Status.Actions[0].Phases[0].State, Equals, crv1alpha1.StateCancelled)
+
+	err = s.crCli.Blueprints(s.namespace).Delete(bp.GetName(), nil)
+	c.Assert(err, IsNil)
+	err = s.crCli.ActionSets(s.namespace).Delete(as.GetName(), nil)
+	c.Assert(err, IsNil)
+}
+
+func (s *ControllerSuite) TestCancelActionSetWithMultipleActions(c *C) {
+	bp := testutil.NewTestBlueprint("Deployment", testutil.CancelFuncName)
+	bp = testutil.BlueprintWithConfigMap(bp)
+	bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+	c.Assert(err, IsNil)
+
+	// Run the same action twice so that the ActionSet has two actions
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace, kanister.DefaultVersion)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as.Spec.Actions = append(as.Spec.Actions, as.Spec.Actions[0])
+	as, err = s.crCli.ActionSets(s.namespace).Create(as)
+	c.Assert(err, IsNil)
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateRunning)
+	c.Assert(err, IsNil)
+
+	err = reconcile.ActionSet(context.Background(), s.crCli, s.namespace, as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		ras.Spec.Cancel = true
+		return nil
+	})
+	c.Assert(err, IsNil)
+
+	// Both actions must be stopped, not only the last one
+	for range as.Spec.Actions {
+		c.Assert(testutil.CancelFuncOut(), ErrorMatches, "context canceled")
+	}
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateCancelled)
+	c.Assert(err, IsNil)
+
+	err = s.crCli.Blueprints(s.namespace).Delete(bp.GetName(), nil)
+	c.Assert(err, IsNil)
+	err = s.crCli.ActionSets(s.namespace).Delete(as.GetName(), nil)
+	c.Assert(err, IsNil)
+}
+
+func (s *ControllerSuite) TestDeferPhase(c *C) {
+	for _, tc := range []struct {
+		funcName string
+		final    crv1alpha1.State
+	}{
+		{
+			funcName: testutil.FailFuncName,
+			final:    crv1alpha1.StateFailed,
+		},
+		{
+			funcName: testutil.WaitFuncName,
+			final:    crv1alpha1.StateComplete,
+		},
+	} {
+		bp := testutil.NewTestBlueprint("Deployment", tc.funcName)
+		bp = testutil.BlueprintWithConfigMap(bp)
+		for _, a := range bp.Actions {
+			a.DeferPhase = &crv1alpha1.BlueprintPhase{
+				Name: "myDeferPhase",
+				Func: testutil.OutputFuncName,
+				Args: map[string]interface{}{"key": "{{ .ConfigMaps.myCM.Data.myKey }}"},
+			}
+		}
+		bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+		c.Assert(err, IsNil)
+
+		as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace, kanister.DefaultVersion)
+		as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+		as, err = s.crCli.ActionSets(s.namespace).Create(as)
+		c.Assert(err, IsNil)
+
+		err = s.waitOnActionSetState(c, as, crv1alpha1.StateRunning)
+		c.Assert(err, IsNil)
+		if tc.funcName == testutil.WaitFuncName {
+			testutil.ReleaseWaitFunc()
+		}
+		// The deferPhase runs whether the phase failed or not.
+		c.Assert(testutil.OutputFuncOut(), DeepEquals, map[string]interface{}{"key": "myValue"})
+
+		err = s.waitOnActionSetState(c, as, tc.final)
+		c.Assert(err, IsNil)
+		as, err = s.crCli.ActionSets(s.namespace).Get(as.GetName(), metav1.GetOptions{})
+		c.Assert(err, IsNil)
+		c.Assert(as.Status.Actions[0].DeferPhase, NotNil)
+		c.Assert(as.Status.Actions[0].DeferPhase.State, Equals, crv1alpha1.StateComplete)
+
+		err = s.crCli.Blueprints(s.namespace).Delete(bp.GetName(), nil)
+		c.Assert(err, IsNil)
+		err = s.crCli.ActionSets(s.namespace).Delete(as.GetName(), nil)
+		c.Assert(err, IsNil)
+	}
+}
+
+func (s *ControllerSuite) TestParallelPhases(c *C) {
+	bp := testutil.NewTestBlueprint("Deployment", testutil.WaitFuncName, testutil.OutputFuncName, testutil.ArgFuncName)
+	for _, a := range bp.Actions {
+		a.Phases[1].Args = map[string]interface{}{"key": "myValue"}
+		a.Phases[2].DependsOn = []string{a.Phases[0].Name, a.Phases[1].Name}
+	}
+	bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+	c.Assert(err, IsNil)
+
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace, kanister.DefaultVersion)
+	as, err = s.crCli.ActionSets(s.namespace).Create(as)
+	c.Assert(err, IsNil)
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateRunning)
+	c.Assert(err, IsNil)
+	// The second phase runs while the first one is waiting.
+	c.Assert(testutil.OutputFuncOut(), DeepEquals, map[string]interface{}{"key": "myValue"})
+	testutil.ReleaseWaitFunc()
+	// The last phase runs once both phases are complete.
+	testutil.ArgFuncArgs()
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateComplete)
+	c.Assert(err, IsNil)
+
+	err = s.crCli.Blueprints(s.namespace).Delete(bp.GetName(), nil)
+	c.Assert(err, IsNil)
+	err = s.crCli.ActionSets(s.namespace).Delete(as.GetName(), nil)
+	c.Assert(err, IsNil)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sort"
+)
+
+type dagResult struct {
+	node int
+	ok   bool
+}
+
+// runDAG calls run for every node once all the nodes it depends on have
+// succeeded. deps holds, for each node, the indices of the nodes it depends
+// on. At most limit nodes run at the same time; a limit of zero or less means
+// no limit. Once a node fails, no new node is started. runDAG waits for the
+// running nodes to finish and returns true if all nodes succeeded.
+func runDAG(deps [][]int, limit int, run func(int) bool) bool {
+	n := len(deps)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	remaining := make([]int, n)
+	dependents := make([][]int, n)
+	var ready []int
+	for i, ds := range deps {
+		remaining[i] = len(ds)
+		for _, d := range ds {
+			dependents[d] = append(dependents[d], i)
+		}
+		if len(ds) == 0 {
+			ready = append(ready, i)
+		}
+	}
+	done := make(chan dagResult)
+	var running, succeeded int
+	failed := false
+	for {
+		// Start nodes in the order they are declared.
+		sort.Ints(ready)
+		for !failed && running < limit && len(ready) != 0 {
+			i := ready[0]
+			ready = ready[1:]
+			running++
+			go func(i int) {
+				done <- dagResult{node: i, ok: run(i)}
+			}(i)
+		}
+		if running == 0 {
+			break
+		}
+		r := <-done
+		running--
+		if !r.ok {
+			failed = true
+			continue
+		}
+		succeeded++
+		for _, d := range dependents[r.node] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	return succeeded == n
+}
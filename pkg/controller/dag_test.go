@@ -0,0 +1,125 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+type DAGSuite struct{}
+
+var _ = Suite(&DAGSuite{})
+
+func (s *DAGSuite) TestRunDAG(c *C) {
+	for _, tc := range []struct {
+		deps     [][]int
+		limit    int
+		together []int
+		fail     map[int]bool
+		ok       bool
+		ran      []int
+		parallel int
+	}{
+		{
+			// Sequential
+			deps:     [][]int{nil, {0}, {1}},
+			ok:       true,
+			ran:      []int{0, 1, 2},
+			parallel: 1,
+		},
+		{
+			// Fan out and fan in
+			deps:     [][]int{nil, {0}, {0}, {0}, {1, 2, 3}},
+			together: []int{1, 2, 3},
+			ok:       true,
+			ran:      []int{0, 1, 2, 3, 4},
+			parallel: 3,
+		},
+		{
+			// Fan out with a limit
+			deps:     [][]int{nil, {0}, {0}, {0}, {1, 2, 3}},
+			limit:    2,
+			together: []int{1, 2},
+			ok:       true,
+			ran:      []int{0, 1, 2, 3, 4},
+			parallel: 2,
+		},
+		{
+			// A failure stops dependent nodes
+			deps:     [][]int{nil, {0}, {1}},
+			fail:     map[int]bool{1: true},
+			ok:       false,
+			ran:      []int{0, 1},
+			parallel: 1,
+		},
+		{
+			// Independent nodes
+			deps:     [][]int{nil, nil, nil, nil},
+			together: []int{0, 1, 2, 3},
+			ok:       true,
+			ran:      []int{0, 1, 2, 3},
+			parallel: 4,
+		},
+		{
+			// Empty
+			deps:     [][]int{},
+			ok:       true,
+			ran:      []int{},
+			parallel: 0,
+		},
+	} {
+		var mu sync.Mutex
+		// Nodes in together wait for each other so that they are running at
+		// the same time.
+		var wg sync.WaitGroup
+		wg.Add(len(tc.together))
+		ran := []int{}
+		running, maxRunning := 0, 0
+		ok := runDAG(tc.deps, tc.limit, func(i int) bool {
+			mu.Lock()
+			ran = append(ran, i)
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+			if contains(tc.together, i) {
+				wg.Done()
+				wg.Wait()
+			}
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return !tc.fail[i]
+		})
+		c.Check(ok, Equals, tc.ok)
+		c.Check(maxRunning, Equals, tc.parallel)
+		c.Check(len(ran), Equals, len(tc.ran))
+		for _, i := range tc.ran {
+			c.Check(contains(ran, i), Equals, true)
+		}
+	}
+}
+
+func contains(s []int, i int) bool {
+	for _, v := range s {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// dryRunActionSet renders the phases of every action of the ActionSet without
+// executing them. The rendered args are recorded in the phase statuses and the
+// ActionSet is marked complete, or failed if any phase could not be rendered.
+func (c *Controller) dryRunActionSet(ctx context.Context, as *crv1alpha1.ActionSet) error {
+	as.Status.State = crv1alpha1.StateComplete
+	for i := range as.Status.Actions {
+		if err := c.dryRunAction(ctx, as, i); err != nil {
+			reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Status.Actions[i].Name)
+			c.logAndErrorEvent(ctx, fmt.Sprintf("Failed dry run of Action %s:", as.GetName()), reason, err, as)
+			as.Status.State = crv1alpha1.StateFailed
+			as.Status.Error = crv1alpha1.Error{
+				Message: err.Error(),
+			}
+			break
+		}
+	}
+	if as.Status.State == crv1alpha1.StateComplete {
+		c.logAndSuccessEvent(ctx, fmt.Sprintf("Rendered all phases of ActionSet %s", as.GetName()), "Dry Run", as)
+	}
+	_, err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Update(as)
+	return errors.WithStack(err)
+}
+
+// dryRunAction renders the phases of an action in dependency order. The output
+// of each rendered phase is replaced with placeholders for the keys declared
+// by its function, so that later phases can reference it.
+func (c *Controller) dryRunAction(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) error {
+	action := as.Spec.Actions[aIDX]
+	bp, err := c.crClient.CrV1alpha1().Blueprints(as.GetNamespace()).Get(action.Blueprint, v1.GetOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tp, err := param.New(ctx, c.clientset, c.dynClient, c.crClient, action)
+	if err != nil {
+		return err
+	}
+	phases, err := kanister.GetPhases(*bp, action.Name, action.PreferredVersion, *tp)
+	if err != nil {
+		return err
+	}
+	deferPhase, err := kanister.GetDeferPhase(*bp, action.Name, action.PreferredVersion, *tp)
+	if err != nil {
+		return err
+	}
+	deps, err := kanister.PhaseDependencies(phases)
+	if err != nil {
+		return err
+	}
+	status := &as.Status.Actions[aIDX]
+	runDAG(deps, 1, func(i int) bool {
+		err = c.dryRunPhase(ctx, *bp, action.Name, phases[i], tp, &status.Phases[i])
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if deferPhase != nil {
+		if err = c.dryRunPhase(ctx, *bp, action.Name, deferPhase, tp, status.DeferPhase); err != nil {
+			return err
+		}
+	}
+	arts, err := param.RenderArtifacts(status.Artifacts, *redactTemplateParams(tp))
+	if err != nil {
+		return errors.Wrap(err, "Failed to render output artifacts")
+	}
+	status.Artifacts = arts
+	return nil
+}
+
+func (c *Controller) dryRunPhase(ctx context.Context, bp crv1alpha1.Blueprint, action string, p *kanister.Phase, tp *param.TemplateParams, ps *crv1alpha1.Phase) error {
+	if err := param.InitPhaseParams(ctx, c.clientset, tp, p.Name(), p.Objects()); err != nil {
+		ps.State = crv1alpha1.StateFailed
+		return errors.Wrapf(err, "Failed to initialize params of phase %s", p.Name())
+	}
+	// The rendered args are recorded in the ActionSet, so they are rendered
+	// without the values of Secrets and Profile credentials.
+	args, err := p.RenderArgs(bp, action, *redactTemplateParams(tp))
+	if err != nil {
+		ps.State = crv1alpha1.StateFailed
+		return errors.Wrapf(err, "Failed to render args of phase %s", p.Name())
+	}
+	ps.RenderedArgs = args
+	ps.State = crv1alpha1.StateComplete
+	param.UpdatePhaseParams(ctx, tp, p.Name(), p.PlaceholderOutput())
+	return nil
+}
+
+// redactedValue replaces the values of Secrets and Profile credentials in
+// the args rendered by a dry run.
+const redactedValue = "<redacted>"
+
+// redactTemplateParams returns a copy of tp in which the values of Secrets,
+// including the Secrets of phase objects, and of the Profile credentials and
+// keys are replaced with redactedValue.
+func redactTemplateParams(tp *param.TemplateParams) *param.TemplateParams {
+	rtp := *copyTemplateParams(tp)
+	rtp.Secrets = redactSecrets(tp.Secrets)
+	for _, p := range rtp.Phases {
+		p.Secrets = redactSecrets(p.Secrets)
+	}
+	if tp.Profile != nil {
+		prof := *tp.Profile
+		switch {
+		case prof.Credential.KeyPair != nil:
+			prof.Credential.KeyPair = &param.KeyPair{ID: redactedValue, Secret: redactedValue}
+		case prof.Credential.Secret != nil:
+			prof.Credential.Secret = redactSecret(*prof.Credential.Secret)
+		}
+		if prof.EncryptionKey != nil {
+			prof.EncryptionKey = []byte(redactedValue)
+		}
+		if prof.RepositoryPassword != nil {
+			prof.RepositoryPassword = []byte(redactedValue)
+		}
+		rtp.Profile = &prof
+	}
+	return &rtp
+}
+
+func redactSecrets(secrets map[string]corev1.Secret) map[string]corev1.Secret {
+	if secrets == nil {
+		return nil
+	}
+	rs := make(map[string]corev1.Secret, len(secrets))
+	for k, s := range secrets {
+		rs[k] = *redactSecret(s)
+	}
+	return rs
+}
+
+func redactSecret(s corev1.Secret) *corev1.Secret {
+	rs := s.DeepCopy()
+	for k := range rs.Data {
+		rs.Data[k] = []byte(redactedValue)
+	}
+	for k := range rs.StringData {
+		rs.StringData[k] = redactedValue
+	}
+	return rs
+}
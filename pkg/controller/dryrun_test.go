@@ -0,0 +1,56 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	. "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type DryRunSuite struct{}
+
+var _ = Suite(&DryRunSuite{})
+
+func (s *DryRunSuite) TestRedactTemplateParams(c *C) {
+	secret := corev1.Secret{Data: map[string][]byte{"password": []byte("hunter2")}}
+	tp := &param.TemplateParams{
+		Secrets: map[string]corev1.Secret{"db": secret},
+		Phases: map[string]*param.Phase{
+			"backup": {Secrets: map[string]corev1.Secret{"db": secret}, Output: map[string]interface{}{"id": "1"}},
+		},
+		Profile: &param.Profile{
+			Credential: param.Credential{
+				Type:    param.CredentialTypeKeyPair,
+				KeyPair: &param.KeyPair{ID: "id", Secret: "secret"},
+			},
+			EncryptionKey: []byte("key"),
+		},
+	}
+	rtp := redactTemplateParams(tp)
+	c.Assert(string(rtp.Secrets["db"].Data["password"]), Equals, redactedValue)
+	c.Assert(string(rtp.Phases["backup"].Secrets["db"].Data["password"]), Equals, redactedValue)
+	c.Assert(rtp.Phases["backup"].Output, DeepEquals, map[string]interface{}{"id": "1"})
+	c.Assert(rtp.Profile.Credential.KeyPair.Secret, Equals, redactedValue)
+	c.Assert(string(rtp.Profile.EncryptionKey), Equals, redactedValue)
+	c.Assert(rtp.Profile.RepositoryPassword, IsNil)
+
+	// The template params that are used to execute the phases are unchanged.
+	c.Assert(string(tp.Secrets["db"].Data["password"]), Equals, "hunter2")
+	c.Assert(string(tp.Phases["backup"].Secrets["db"].Data["password"]), Equals, "hunter2")
+	c.Assert(tp.Profile.Credential.KeyPair.Secret, Equals, "secret")
+	c.Assert(string(tp.Profile.EncryptionKey), Equals, "key")
+}
@@ -0,0 +1,186 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jpillora/backoff"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kanisterio/kanister/pkg/actionset"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/retention"
+)
+
+const (
+	defaultRetentionDeleteAction = "delete"
+	// retentionDeleteAttempts is the number of delete ActionSets that are
+	// created for an expired ActionSet before a failure is left for
+	// inspection.
+	retentionDeleteAttempts = 5
+)
+
+// retentionDeleteBackoff is the time to wait after a delete ActionSet was
+// created before it is retried, should it fail.
+var retentionDeleteBackoff = backoff.Backoff{
+	Min:    10 * time.Minute,
+	Max:    12 * time.Hour,
+	Factor: 2,
+}
+
+// applyRetention evaluates the retention policy of the BackupSchedule against
+// its complete ActionSets. An ActionSet that expires is deleted in two steps:
+// first a delete ActionSet is created from it, then, once the delete
+// ActionSet is complete, both are removed. A failed delete ActionSet is
+// replaced by a new one with a backoff. Each decision is recorded as an event
+// on the BackupSchedule.
+func (c *Controller) applyRetention(ctx context.Context, bs *crv1alpha1.BackupSchedule, backups []*crv1alpha1.ActionSet) error {
+	if bs.Spec.Retention == nil {
+		return nil
+	}
+	ns := bs.GetNamespace()
+	sel := fmt.Sprintf("%s=%s", consts.RetentionLabel, bs.GetName())
+	dl, err := c.crClient.CrV1alpha1().ActionSets(ns).List(v1.ListOptions{LabelSelector: sel})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list delete ActionSets of BackupSchedule %s", bs.GetName())
+	}
+	expiring := make(map[string]bool, len(dl.Items))
+	for _, das := range dl.Items {
+		name := das.Annotations[consts.ExpiredActionSetAnnotation]
+		expiring[name] = true
+		if das.Status == nil {
+			continue
+		}
+		switch das.Status.State {
+		case crv1alpha1.StateComplete:
+		case crv1alpha1.StateFailed:
+			if err := c.retryRetentionActionSet(ctx, bs, das, name); err != nil {
+				return err
+			}
+			continue
+		default:
+			continue
+		}
+		for _, asName := range []string{name, das.GetName()} {
+			if err := c.crClient.CrV1alpha1().ActionSets(ns).Delete(asName, nil); err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "Failed to delete ActionSet %s", asName)
+			}
+		}
+		c.logAndSuccessEvent(ctx, fmt.Sprintf("Pruned ActionSet %s, whose artifacts were deleted by ActionSet %s", name, das.GetName()), "Pruned", bs)
+	}
+
+	byName := make(map[string]*crv1alpha1.ActionSet, len(backups))
+	var candidates []retention.Backup
+	for _, as := range backups {
+		if as.Status == nil || as.Status.State != crv1alpha1.StateComplete || expiring[as.GetName()] {
+			continue
+		}
+		byName[as.GetName()] = as
+		candidates = append(candidates, retention.Backup{Name: as.GetName(), Time: as.CreationTimestamp.Time})
+	}
+	for _, d := range retention.Expired(*bs.Spec.Retention, candidates, time.Now()) {
+		das, err := newRetentionActionSet(bs, byName[d.Name], 1)
+		if err == nil {
+			das, err = c.crClient.CrV1alpha1().ActionSets(ns).Create(das)
+		}
+		if err != nil {
+			c.logAndErrorEvent(ctx, fmt.Sprintf("Failed to create delete ActionSet for expired ActionSet %s:", d.Name), "Error", err, bs)
+			continue
+		}
+		c.logAndSuccessEvent(ctx, fmt.Sprintf("Expired ActionSet %s: %s. Created ActionSet %s to delete its artifacts", d.Name, d.Reason, das.GetName()), "Expired", bs)
+	}
+	return nil
+}
+
+// retryRetentionActionSet replaces the failed delete ActionSet das of the
+// expired ActionSet name by a new one once its backoff has passed. After
+// retentionDeleteAttempts failures, das is kept along with the expired
+// ActionSet so that the failure can be inspected.
+func (c *Controller) retryRetentionActionSet(ctx context.Context, bs *crv1alpha1.BackupSchedule, das *crv1alpha1.ActionSet, name string) error {
+	attempt := retentionAttempt(das)
+	if attempt >= retentionDeleteAttempts {
+		return nil
+	}
+	if time.Since(das.CreationTimestamp.Time) < retentionDeleteBackoff.ForAttempt(float64(attempt-1)) {
+		return nil
+	}
+	ns := bs.GetNamespace()
+	expired, err := c.crClient.CrV1alpha1().ActionSets(ns).Get(name, v1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		expired = nil
+	case err != nil:
+		return errors.Wrapf(err, "Failed to get expired ActionSet %s", name)
+	}
+	if expired != nil {
+		nas, err := newRetentionActionSet(bs, expired, attempt+1)
+		if err == nil {
+			nas, err = c.crClient.CrV1alpha1().ActionSets(ns).Create(nas)
+		}
+		if err != nil {
+			c.logAndErrorEvent(ctx, fmt.Sprintf("Failed to create delete ActionSet for expired ActionSet %s:", name), "Error", err, bs)
+			return nil
+		}
+		c.logAndSuccessEvent(ctx, fmt.Sprintf("Delete ActionSet %s of expired ActionSet %s failed. Created ActionSet %s to retry", das.GetName(), name, nas.GetName()), "Retried", bs)
+	}
+	if err := c.crClient.CrV1alpha1().ActionSets(ns).Delete(das.GetName(), nil); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "Failed to delete ActionSet %s", das.GetName())
+	}
+	return nil
+}
+
+// retentionAttempt returns the attempt recorded on a delete ActionSet. Delete
+// ActionSets without the annotation are the first attempt.
+func retentionAttempt(das *crv1alpha1.ActionSet) int {
+	attempt, err := strconv.Atoi(das.Annotations[consts.RetentionAttemptAnnotation])
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return attempt
+}
+
+// newRetentionActionSet returns an ActionSet that runs the delete action of
+// the retention policy on the artifacts of an expired ActionSet, as
+// `kanctl create actionset --action delete --from` does. The name of the
+// expired ActionSet is recorded in an annotation, since it can be longer than
+// a label value may be.
+func newRetentionActionSet(bs *crv1alpha1.BackupSchedule, expired *crv1alpha1.ActionSet, attempt int) (*crv1alpha1.ActionSet, error) {
+	action := bs.Spec.Retention.DeleteAction
+	if action == "" {
+		action = defaultRetentionDeleteAction
+	}
+	das, err := actionset.Child(expired, actionset.ChildParams{ActionName: action})
+	if err != nil {
+		return nil, err
+	}
+	das.Labels = map[string]string{
+		consts.RetentionLabel: bs.GetName(),
+	}
+	das.Annotations = map[string]string{
+		consts.ExpiredActionSetAnnotation: expired.GetName(),
+		consts.RetentionAttemptAnnotation: strconv.Itoa(attempt),
+	}
+	das.OwnerReferences = []v1.OwnerReference{
+		*v1.NewControllerRef(bs, crv1alpha1.SchemeGroupVersion.WithKind(crv1alpha1.BackupScheduleResource.Kind)),
+	}
+	return das, nil
+}
@@ -0,0 +1,299 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+const (
+	defaultSuccessfulHistoryLimit = 3
+	defaultFailedHistoryLimit     = 1
+)
+
+func (c *Controller) onAddBackupSchedule(bs *crv1alpha1.BackupSchedule) error {
+	if err := validate.BackupSchedule(bs); err != nil {
+		return err
+	}
+	if err := c.startSchedule(bs); err != nil {
+		return err
+	}
+	c.logAndSuccessEvent(context.TODO(), fmt.Sprintf("Added backupschedule %s", bs.GetName()), "Added", bs)
+	return nil
+}
+
+func (c *Controller) onUpdateBackupSchedule(oldBS, newBS *crv1alpha1.BackupSchedule) error {
+	// The controller updates the status after every run. The schedule only
+	// needs to be restarted if the spec changed.
+	if reflect.DeepEqual(oldBS.Spec, newBS.Spec) {
+		return nil
+	}
+	log.Print("Updated BackupSchedule", field.M{"BackupScheduleName": newBS.GetName()})
+	if err := validate.BackupSchedule(newBS); err != nil {
+		c.stopSchedule(newBS.GetNamespace(), newBS.GetName())
+		return err
+	}
+	return c.startSchedule(newBS)
+}
+
+func (c *Controller) onDeleteBackupSchedule(bs *crv1alpha1.BackupSchedule) error {
+	log.Print("Deleted BackupSchedule", field.M{"BackupScheduleName": bs.GetName()})
+	c.stopSchedule(bs.GetNamespace(), bs.GetName())
+	return nil
+}
+
+// startSchedule starts a goroutine that creates the ActionSets of the
+// BackupSchedule at the scheduled times. A goroutine that was started for an
+// earlier version of the BackupSchedule is stopped.
+func (c *Controller) startSchedule(bs *crv1alpha1.BackupSchedule) error {
+	sched, err := cron.ParseStandard(bs.Spec.Schedule)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to parse schedule of BackupSchedule %s", bs.GetName())
+	}
+	ns, name := bs.GetNamespace(), bs.GetName()
+	c.stopSchedule(ns, name)
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = field.Context(ctx, consts.BackupScheduleNameKey, name)
+	c.scheduleMap.Store(scheduleKey(ns, name), cancel)
+	go c.runSchedule(ctx, ns, name, sched)
+	return nil
+}
+
+// stopSchedule stops the goroutine started by startSchedule.
+func (c *Controller) stopSchedule(ns, name string) {
+	v, ok := c.scheduleMap.Load(scheduleKey(ns, name))
+	if !ok {
+		return
+	}
+	cancel, castOk := v.(context.CancelFunc)
+	if !castOk {
+		return
+	}
+	cancel()
+	c.scheduleMap.Delete(scheduleKey(ns, name))
+}
+
+func scheduleKey(ns, name string) string {
+	return fmt.Sprintf("%s/%s", ns, name)
+}
+
+// runSchedule waits for each scheduled time and runs the BackupSchedule until
+// the context is cancelled. Times that were missed while the controller was
+// not running are skipped.
+func (c *Controller) runSchedule(ctx context.Context, ns, name string, sched cron.Schedule) {
+	for {
+		next := sched.Next(time.Now())
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+		if err := c.scheduleActionSet(ctx, ns, name, next); err != nil {
+			log.WithContext(ctx).WithError(err).Print("Failed to run BackupSchedule")
+		}
+	}
+}
+
+// scheduleActionSet creates an ActionSet from the template of the
+// BackupSchedule, applying its concurrency policy, prunes the ActionSets that
+// exceed its history limits and applies its retention policy.
+func (c *Controller) scheduleActionSet(ctx context.Context, ns, name string, t time.Time) error {
+	bs, err := c.crClient.CrV1alpha1().BackupSchedules(ns).Get(name, v1.GetOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if bs.Spec.Suspend {
+		log.WithContext(ctx).Print("Skipping suspended BackupSchedule")
+		return nil
+	}
+	sel := fmt.Sprintf("%s=%s", consts.BackupScheduleLabel, name)
+	asl, err := c.crClient.CrV1alpha1().ActionSets(ns).List(v1.ListOptions{LabelSelector: sel})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list ActionSets of BackupSchedule %s", name)
+	}
+	active := activeActionSets(asl.Items)
+	switch bs.Spec.ConcurrencyPolicy {
+	case crv1alpha1.ConcurrencyPolicyForbid:
+		if len(active) != 0 {
+			log.WithContext(ctx).Print("Skipping run of BackupSchedule while ActionSets are active", field.M{"ActiveActionSets": active})
+			if err := c.updateScheduleStatus(ctx, bs, nil, active, nil); err != nil {
+				return err
+			}
+			return c.applyRetention(ctx, bs, asl.Items)
+		}
+	case crv1alpha1.ConcurrencyPolicyReplace:
+		for _, asName := range active {
+			if err := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, asName, func(ras *crv1alpha1.ActionSet) error {
+				ras.Spec.Cancel = true
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		active = nil
+	}
+	as, err := c.crClient.CrV1alpha1().ActionSets(ns).Create(newScheduledActionSet(bs))
+	if err != nil {
+		c.logAndErrorEvent(ctx, "Failed to create scheduled ActionSet:", "Error", err, bs)
+		return c.updateScheduleStatus(ctx, bs, nil, active, err)
+	}
+	c.logAndSuccessEvent(ctx, fmt.Sprintf("Created ActionSet %s", as.GetName()), "Scheduled", bs)
+	if err := c.updateScheduleStatus(ctx, bs, &v1.Time{Time: t}, append(active, as.GetName()), nil); err != nil {
+		return err
+	}
+	if err := c.pruneActionSets(ctx, bs, asl.Items); err != nil {
+		return err
+	}
+	return c.applyRetention(ctx, bs, asl.Items)
+}
+
+func (c *Controller) updateScheduleStatus(ctx context.Context, bs *crv1alpha1.BackupSchedule, t *v1.Time, active []string, err error) error {
+	return reconcile.BackupSchedule(ctx, c.crClient.CrV1alpha1(), bs.GetNamespace(), bs.GetName(), func(rbs *crv1alpha1.BackupSchedule) error {
+		if rbs.Status == nil {
+			rbs.Status = &crv1alpha1.BackupScheduleStatus{}
+		}
+		rbs.Status.Active = active
+		rbs.Status.Error = crv1alpha1.Error{}
+		if err != nil {
+			rbs.Status.Error.Message = err.Error()
+		}
+		if t != nil {
+			rbs.Status.LastScheduleTime = t
+			rbs.Status.LastActionSet = active[len(active)-1]
+		}
+		return nil
+	})
+}
+
+// pruneActionSets deletes the oldest finished ActionSets of the BackupSchedule
+// that exceed its history limits. Complete ActionSets are left to the
+// retention policy if there is one.
+func (c *Controller) pruneActionSets(ctx context.Context, bs *crv1alpha1.BackupSchedule, ass []*crv1alpha1.ActionSet) error {
+	successLimit := historyLimit(bs.Spec.SuccessfulHistoryLimit, defaultSuccessfulHistoryLimit)
+	if bs.Spec.Retention != nil {
+		successLimit = len(ass)
+	}
+	for _, as := range actionSetsToPrune(ass, successLimit, historyLimit(bs.Spec.FailedHistoryLimit, defaultFailedHistoryLimit)) {
+		log.WithContext(ctx).Print("Deleting ActionSet of BackupSchedule", field.M{"ActionSetName": as.GetName()})
+		if err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Delete(as.GetName(), nil); err != nil {
+			return errors.Wrapf(err, "Failed to delete ActionSet %s", as.GetName())
+		}
+	}
+	return nil
+}
+
+func historyLimit(limit *int32, def int) int {
+	if limit == nil {
+		return def
+	}
+	return int(*limit)
+}
+
+// newScheduledActionSet returns an ActionSet created from the template of the
+// BackupSchedule. The ActionSet is labelled with the name of the
+// BackupSchedule and owned by it.
+func newScheduledActionSet(bs *crv1alpha1.BackupSchedule) *crv1alpha1.ActionSet {
+	tmpl := bs.Spec.ActionSetTemplate.DeepCopy()
+	labels := tmpl.Labels
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[consts.BackupScheduleLabel] = bs.GetName()
+	return &crv1alpha1.ActionSet{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", bs.GetName()),
+			Labels:       labels,
+			Annotations:  tmpl.Annotations,
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(bs, crv1alpha1.SchemeGroupVersion.WithKind(crv1alpha1.BackupScheduleResource.Kind)),
+			},
+		},
+		Spec: tmpl.Spec,
+	}
+}
+
+// activeActionSets returns the names of the ActionSets that have not finished.
+func activeActionSets(ass []*crv1alpha1.ActionSet) []string {
+	var active []string
+	for _, as := range ass {
+		if !actionSetFinished(as) {
+			active = append(active, as.GetName())
+		}
+	}
+	sort.Strings(active)
+	return active
+}
+
+func actionSetFinished(as *crv1alpha1.ActionSet) bool {
+	if as.Status == nil {
+		return false
+	}
+	switch as.Status.State {
+	case crv1alpha1.StateComplete, crv1alpha1.StateFailed, crv1alpha1.StateCancelled:
+		return true
+	}
+	return false
+}
+
+// actionSetsToPrune returns the complete ActionSets beyond the newest
+// successLimit ones and the failed or cancelled ActionSets beyond the newest
+// failedLimit ones.
+func actionSetsToPrune(ass []*crv1alpha1.ActionSet, successLimit, failedLimit int) []*crv1alpha1.ActionSet {
+	var succeeded, failed []*crv1alpha1.ActionSet
+	for _, as := range ass {
+		if !actionSetFinished(as) {
+			continue
+		}
+		if as.Status.State == crv1alpha1.StateComplete {
+			succeeded = append(succeeded, as)
+		} else {
+			failed = append(failed, as)
+		}
+	}
+	var prune []*crv1alpha1.ActionSet
+	for _, l := range []struct {
+		ass   []*crv1alpha1.ActionSet
+		limit int
+	}{
+		{ass: succeeded, limit: successLimit},
+		{ass: failed, limit: failedLimit},
+	} {
+		if len(l.ass) <= l.limit {
+			continue
+		}
+		sort.Slice(l.ass, func(i, j int) bool {
+			return l.ass[i].CreationTimestamp.Before(&l.ass[j].CreationTimestamp)
+		})
+		prune = append(prune, l.ass[:len(l.ass)-l.limit]...)
+	}
+	return prune
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+)
+
+type ScheduleSuite struct{}
+
+var _ = Suite(&ScheduleSuite{})
+
+func (s *ScheduleSuite) TestNewScheduledActionSet(c *C) {
+	bs := &crv1alpha1.BackupSchedule{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "nightly",
+			UID:  "1234",
+		},
+		Spec: &crv1alpha1.BackupScheduleSpec{
+			Schedule: "@daily",
+			ActionSetTemplate: crv1alpha1.ActionSetTemplate{
+				Labels: map[string]string{"app": "mysql"},
+				Spec: &crv1alpha1.ActionSetSpec{
+					Actions: []crv1alpha1.ActionSpec{
+						{
+							Name:      "backup",
+							Blueprint: "mysql-blueprint",
+						},
+					},
+				},
+			},
+		},
+	}
+	as := newScheduledActionSet(bs)
+	c.Assert(as.GenerateName, Equals, "nightly-")
+	c.Assert(as.Labels, DeepEquals, map[string]string{"app": "mysql", consts.BackupScheduleLabel: "nightly"})
+	c.Assert(as.OwnerReferences, HasLen, 1)
+	c.Assert(as.OwnerReferences[0].Kind, Equals, "BackupSchedule")
+	c.Assert(as.OwnerReferences[0].Name, Equals, "nightly")
+	c.Assert(as.Spec, DeepEquals, bs.Spec.ActionSetTemplate.Spec)
+	// The template is not modified.
+	c.Assert(bs.Spec.ActionSetTemplate.Labels, DeepEquals, map[string]string{"app": "mysql"})
+}
+
+func (s *ScheduleSuite) TestActionSetsToPrune(c *C) {
+	now := time.Now()
+	newAS := func(name string, age time.Duration, state crv1alpha1.State) *crv1alpha1.ActionSet {
+		as := &crv1alpha1.ActionSet{
+			ObjectMeta: v1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: v1.NewTime(now.Add(-age)),
+			},
+		}
+		if state != "" {
+			as.Status = &crv1alpha1.ActionSetStatus{State: state}
+		}
+		return as
+	}
+	ass := []*crv1alpha1.ActionSet{
+		newAS("complete-1", 1*time.Hour, crv1alpha1.StateComplete),
+		newAS("complete-3", 3*time.Hour, crv1alpha1.StateComplete),
+		newAS("complete-2", 2*time.Hour, crv1alpha1.StateComplete),
+		newAS("failed-1", 1*time.Hour, crv1alpha1.StateFailed),
+		newAS("cancelled-2", 2*time.Hour, crv1alpha1.StateCancelled),
+		newAS("running", 4*time.Hour, crv1alpha1.StateRunning),
+		newAS("new", 5*time.Hour, ""),
+	}
+	for _, tc := range []struct {
+		successLimit int
+		failedLimit  int
+		pruned       []string
+	}{
+		{
+			successLimit: 3,
+			failedLimit:  2,
+			pruned:       nil,
+		},
+		{
+			successLimit: 1,
+			failedLimit:  1,
+			pruned:       []string{"complete-3", "complete-2", "cancelled-2"},
+		},
+		{
+			successLimit: 0,
+			failedLimit:  0,
+			pruned:       []string{"complete-3", "complete-2", "complete-1", "cancelled-2", "failed-1"},
+		},
+	} {
+		var pruned []string
+		for _, as := range actionSetsToPrune(ass, tc.successLimit, tc.failedLimit) {
+			pruned = append(pruned, as.GetName())
+		}
+		c.Check(pruned, DeepEquals, tc.pruned)
+	}
+	c.Assert(activeActionSets(ass), DeepEquals, []string{"new", "running"})
+}
+
+func (s *ScheduleSuite) TestNewRetentionActionSet(c *C) {
+	bs := &crv1alpha1.BackupSchedule{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "nightly",
+			UID:  "1234",
+		},
+		Spec: &crv1alpha1.BackupScheduleSpec{
+			Retention: &crv1alpha1.RetentionPolicy{KeepLast: 1},
+		},
+	}
+	expired := &crv1alpha1.ActionSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "nightly-abcde",
+		},
+		Spec: &crv1alpha1.ActionSetSpec{
+			Actions: []crv1alpha1.ActionSpec{
+				{
+					Name:      "backup",
+					Blueprint: "mysql-blueprint",
+				},
+			},
+		},
+		Status: &crv1alpha1.ActionSetStatus{
+			State: crv1alpha1.StateComplete,
+			Actions: []crv1alpha1.ActionStatus{
+				{
+					Blueprint: "mysql-blueprint",
+					Artifacts: map[string]crv1alpha1.Artifact{
+						"snapshot": {KeyValue: map[string]string{"id": "snap-1"}},
+					},
+				},
+			},
+		},
+	}
+	das, err := newRetentionActionSet(bs, expired, 1)
+	c.Assert(err, IsNil)
+	c.Assert(das.Labels, DeepEquals, map[string]string{consts.RetentionLabel: "nightly"})
+	c.Assert(das.Annotations, DeepEquals, map[string]string{consts.ExpiredActionSetAnnotation: "nightly-abcde", consts.RetentionAttemptAnnotation: "1"})
+	c.Assert(retentionAttempt(das), Equals, 1)
+	c.Assert(das.OwnerReferences, HasLen, 1)
+	c.Assert(das.Spec.Actions, HasLen, 1)
+	c.Assert(das.Spec.Actions[0].Name, Equals, defaultRetentionDeleteAction)
+	c.Assert(das.Spec.Actions[0].Artifacts, DeepEquals, expired.Status.Actions[0].Artifacts)
+
+	bs.Spec.Retention.DeleteAction = "forget"
+	das, err = newRetentionActionSet(bs, expired, 3)
+	c.Assert(err, IsNil)
+	c.Assert(das.Spec.Actions[0].Name, Equals, "forget")
+	c.Assert(retentionAttempt(das), Equals, 3)
+}
@@ -58,6 +58,26 @@ func NamespacedGVRsIgnoreGroupErrs(ctx context.Context, cli discovery.DiscoveryI
 	return apiToGroupVersion(arls)
 }
 
+// IsGroupVersionAvailable returns true if the API server serves the version of
+// the API group.
+func IsGroupVersionAvailable(ctx context.Context, cli discovery.DiscoveryInterface, groupName, version string) (bool, error) {
+	sgs, err := cli.ServerGroups()
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to list APIGroups")
+	}
+	for _, g := range sgs.Groups {
+		if g.Name != groupName {
+			continue
+		}
+		for _, v := range g.Versions {
+			if v.Version == version {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func apiToGroupVersion(arls []*metav1.APIResourceList) ([]schema.GroupVersionResource, error) {
 	gvrs := make([]schema.GroupVersionResource, 0, len(arls))
 	for _, arl := range arls {
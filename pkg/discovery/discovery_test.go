@@ -19,6 +19,9 @@ import (
 	"testing"
 
 	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
 
 	"github.com/kanisterio/kanister/pkg/kube"
 )
@@ -53,3 +56,29 @@ func (s *DiscoverSuite) TestDiscover(c *C) {
 	}
 
 }
+
+func (s *DiscoverSuite) TestIsGroupVersionAvailable(c *C) {
+	ctx := context.Background()
+	cli := &fake.FakeDiscovery{
+		Fake: &kubetesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{GroupVersion: "snapshot.storage.k8s.io/v1beta1"},
+				{GroupVersion: "v1"},
+			},
+		},
+	}
+	for _, tc := range []struct {
+		group     string
+		version   string
+		available bool
+	}{
+		{"snapshot.storage.k8s.io", "v1beta1", true},
+		{"snapshot.storage.k8s.io", "v1", false},
+		{"", "v1", true},
+		{"cr.kanister.io", "v1alpha1", false},
+	} {
+		ok, err := IsGroupVersionAvailable(ctx, cli, tc.group, tc.version)
+		c.Assert(err, IsNil)
+		c.Check(ok, Equals, tc.available, Commentf("%s/%s", tc.group, tc.version))
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	// AddRepositoryKeyFuncName gives the name of the function
+	AddRepositoryKeyFuncName = "AddRepositoryKey"
+	// AddRepositoryKeyArtifactPrefixArg provides the path of the repository
+	AddRepositoryKeyArtifactPrefixArg = "backupArtifactPrefix"
+	// AddRepositoryKeyEncryptionKeyArg provides the current encryption key of the repository
+	AddRepositoryKeyEncryptionKeyArg = "encryptionKey"
+	// AddRepositoryKeyNewKeySecretArg names the ActionSet Secret that holds the new key
+	AddRepositoryKeyNewKeySecretArg = "newKeySecret"
+	// AddRepositoryKeyNewKeyFieldArg provides the field of the Secret that holds the new key
+	AddRepositoryKeyNewKeyFieldArg = "newKeyField"
+	// AddRepositoryKeyPodOverrideArg contains pod specs to override default pod specs
+	AddRepositoryKeyPodOverrideArg = "podOverride"
+	// AddRepositoryKeyOutputKeyID is the ID of the added key
+	AddRepositoryKeyOutputKeyID = "keyID"
+	// AddRepositoryKeyOutputPreviousKeyID is the ID of the key opened by the current encryption key
+	AddRepositoryKeyOutputPreviousKeyID = "previousKeyID"
+	addRepositoryKeyJobPrefix           = "add-repository-key-"
+)
+
+func init() {
+	_ = kanister.Register(&addRepositoryKeyFunc{})
+}
+
+var _ kanister.Func = (*addRepositoryKeyFunc)(nil)
+
+type addRepositoryKeyFunc struct{}
+
+func (*addRepositoryKeyFunc) Name() string {
+	return AddRepositoryKeyFuncName
+}
+
+func addRepositoryKey(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, newKey, targetPath, jobPrefix string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+	namespace, err := kube.GetControllerNamespace()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get controller namespace")
+	}
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: jobPrefix,
+		Image:        kanisterToolsImage,
+		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:  podOverride,
+	}
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := addRepositoryKeyPodFunc(cli, tp, namespace, encryptionKey, newKey, targetPath)
+	return pr.Run(ctx, podFunc)
+}
+
+func addRepositoryKeyPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, newKey, targetPath string) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+		// Wait for pod to reach running state
+		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pod.Name)
+		}
+		container := pod.Spec.Containers[0].Name
+		pw, err := GetPodWriter(cli, ctx, pod.Namespace, pod.Name, container, tp.Profile)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, container)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, container, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, container)
+		before, err := keyList(cli, tp, namespace, pod.Name, container, passwordFile, targetPath)
+		if err != nil {
+			return nil, err
+		}
+		// Pass the new key in a file as well
+		newPasswordFile, nw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, container, newKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, nw, pod.Namespace, pod.Name, container)
+		cmd, err := restic.KeyAddCommand(tp.Profile, targetPath, passwordFile, newPasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		stdout, stderr, err := kube.Exec(cli, namespace, pod.Name, container, cmd, nil)
+		format.Log(pod.Name, container, stdout)
+		format.Log(pod.Name, container, stderr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to add repository key")
+		}
+		// Opening the repository with the new key confirms that it works
+		// and marks the added key as current
+		cmd, err = restic.KeyListCommand(tp.Profile, targetPath, newPasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		after, err := execKeyList(cli, namespace, pod.Name, container, cmd)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to open repository with new key")
+		}
+		return map[string]interface{}{
+			AddRepositoryKeyOutputKeyID:         currentKeyID(after),
+			AddRepositoryKeyOutputPreviousKeyID: currentKeyID(before),
+			FunctionOutputVersion:               kanister.DefaultVersion,
+		}, nil
+	}
+}
+
+// newKeyFromSecret returns the new key from the ActionSet Secret `secret`,
+// so that it is not passed as plaintext in the Blueprint's arguments
+func newKeyFromSecret(tp param.TemplateParams, secret, field string) (string, error) {
+	s, ok := tp.Secrets[secret]
+	if !ok {
+		return "", errors.Errorf("Secret '%s' not found in ActionSet secrets", secret)
+	}
+	key, ok := s.Data[field]
+	if !ok || len(key) == 0 {
+		return "", errors.Errorf("Field '%s' not found in secret '%s:%s'", field, s.GetNamespace(), s.GetName())
+	}
+	return string(key), nil
+}
+
+func (*addRepositoryKeyFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var artifactPrefix, encryptionKey, newKeySecret, newKeyField string
+	if err := Arg(args, AddRepositoryKeyArtifactPrefixArg, &artifactPrefix); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, AddRepositoryKeyEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, AddRepositoryKeyNewKeySecretArg, &newKeySecret); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, AddRepositoryKeyNewKeyFieldArg, &newKeyField); err != nil {
+		return nil, err
+	}
+	newKey, err := newKeyFromSecret(tp, newKeySecret, newKeyField)
+	if err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, AddRepositoryKeyPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateProfile(tp.Profile); err != nil {
+		return nil, err
+	}
+
+	artifactPrefix = ResolveArtifactPrefix(artifactPrefix, tp.Profile)
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	return addRepositoryKey(ctx, cli, tp, encryptionKey, newKey, artifactPrefix, addRepositoryKeyJobPrefix, podOverride)
+}
+
+func (*addRepositoryKeyFunc) RequiredArgs() []string {
+	return []string{AddRepositoryKeyArtifactPrefixArg, AddRepositoryKeyNewKeySecretArg, AddRepositoryKeyNewKeyFieldArg}
+}
+
+func (*addRepositoryKeyFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		AddRepositoryKeyArtifactPrefixArg: kanister.ArgTypeString,
+		AddRepositoryKeyEncryptionKeyArg:  kanister.ArgTypeString,
+		AddRepositoryKeyNewKeySecretArg:   kanister.ArgTypeString,
+		AddRepositoryKeyNewKeyFieldArg:    kanister.ArgTypeString,
+		AddRepositoryKeyPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*addRepositoryKeyFunc) Outputs() []string {
+	return []string{
+		AddRepositoryKeyOutputKeyID,
+		AddRepositoryKeyOutputPreviousKeyID,
+		FunctionOutputVersion,
+	}
+}
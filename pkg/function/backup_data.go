@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/kubernetes"
 
 	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/field"
 	"github.com/kanisterio/kanister/pkg/format"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/metrics"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/restic"
 )
@@ -46,6 +50,24 @@ const (
 	BackupDataBackupArtifactPrefixArg = "backupArtifactPrefix"
 	// BackupDataEncryptionKeyArg provides the encryption key to be used for backups
 	BackupDataEncryptionKeyArg = "encryptionKey"
+	// BackupDataModeArg selects where restic runs. Defaults to BackupDataModeExec
+	BackupDataModeArg = "mode"
+	// BackupDataPodOverrideArg contains pod specs to override default pod specs in BackupDataModePod
+	BackupDataPodOverrideArg = "podOverride"
+	// BackupDataExcludeArg provides patterns for files and directories to leave out of the backup
+	BackupDataExcludeArg = "exclude"
+	// BackupDataExcludeIfPresentArg provides file names that mark directories to leave out of the backup
+	BackupDataExcludeIfPresentArg = "excludeIfPresent"
+	// BackupDataTagsArg provides tags to add to the snapshot
+	BackupDataTagsArg = "tags"
+	// BackupDataHostnameArg provides the hostname recorded in the snapshot
+	BackupDataHostnameArg = "hostname"
+	// BackupDataModeExec runs restic in the application container
+	BackupDataModeExec = "exec"
+	// BackupDataModePod runs restic in an ephemeral pod that mounts the
+	// application pod's PVCs read-only
+	BackupDataModePod   = "pod"
+	backupDataJobPrefix = "backup-data-"
 	// BackupDataOutputBackupID is the key used for returning backup ID output
 	BackupDataOutputBackupID = "backupID"
 	// BackupDataOutputBackupTag is the key used for returning backupTag output
@@ -71,7 +93,7 @@ func (*backupDataFunc) Name() string {
 }
 
 func (*backupDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
-	var namespace, pod, container, includePath, backupArtifactPrefix, encryptionKey string
+	var namespace, pod, container, includePath, backupArtifactPrefix, encryptionKey, mode string
 	var err error
 	if err = Arg(args, BackupDataNamespaceArg, &namespace); err != nil {
 		return nil, err
@@ -88,7 +110,21 @@ func (*backupDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args m
 	if err = Arg(args, BackupDataBackupArtifactPrefixArg, &backupArtifactPrefix); err != nil {
 		return nil, err
 	}
-	if err = OptArg(args, BackupDataEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, BackupDataEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupDataModeArg, &mode, BackupDataModeExec); err != nil {
+		return nil, err
+	}
+	if mode != BackupDataModeExec && mode != BackupDataModePod {
+		return nil, errors.Errorf("Unsupported %s '%s'. Must be one of '%s' or '%s'", BackupDataModeArg, mode, BackupDataModeExec, BackupDataModePod)
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, BackupDataPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := backupOptionsFromArgs(args, BackupDataExcludeArg, BackupDataExcludeIfPresentArg, BackupDataTagsArg, BackupDataHostnameArg)
+	if err != nil {
 		return nil, err
 	}
 
@@ -104,10 +140,16 @@ func (*backupDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args m
 	}
 	ctx = field.Context(ctx, consts.PodNameKey, pod)
 	ctx = field.Context(ctx, consts.ContainerNameKey, container)
-	backupOutputs, err := backupData(ctx, cli, namespace, pod, container, backupArtifactPrefix, includePath, encryptionKey, tp)
+	var backupOutputs backupDataParsedOutput
+	if mode == BackupDataModePod {
+		backupOutputs, err = backupDataWithPod(ctx, cli, namespace, pod, backupArtifactPrefix, includePath, encryptionKey, opts, podOverride, tp)
+	} else {
+		backupOutputs, err = backupData(ctx, cli, namespace, pod, container, backupArtifactPrefix, includePath, encryptionKey, opts, tp)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to backup data")
 	}
+	metrics.DataBytes.WithLabelValues(BackupDataFuncName).Add(float64(restic.ParseResticSizeStringBytes(backupOutputs.phySize)))
 	output := map[string]interface{}{
 		BackupDataOutputBackupID:           backupOutputs.backupID,
 		BackupDataOutputBackupTag:          backupOutputs.backupTag,
@@ -124,6 +166,34 @@ func (*backupDataFunc) RequiredArgs() []string {
 		BackupDataIncludePathArg, BackupDataBackupArtifactPrefixArg}
 }
 
+func (*backupDataFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		BackupDataNamespaceArg:            kanister.ArgTypeString,
+		BackupDataPodArg:                  kanister.ArgTypeString,
+		BackupDataContainerArg:            kanister.ArgTypeString,
+		BackupDataIncludePathArg:          kanister.ArgTypeString,
+		BackupDataBackupArtifactPrefixArg: kanister.ArgTypeString,
+		BackupDataEncryptionKeyArg:        kanister.ArgTypeString,
+		BackupDataModeArg:                 kanister.ArgTypeString,
+		BackupDataPodOverrideArg:          kanister.ArgTypeMap,
+		BackupDataExcludeArg:              kanister.ArgTypeStringSlice,
+		BackupDataExcludeIfPresentArg:     kanister.ArgTypeStringSlice,
+		BackupDataTagsArg:                 kanister.ArgTypeStringSlice,
+		BackupDataHostnameArg:             kanister.ArgTypeString,
+	}
+}
+
+func (*backupDataFunc) Outputs() []string {
+	return []string{
+		BackupDataOutputBackupID,
+		BackupDataOutputBackupTag,
+		BackupDataOutputBackupFileCount,
+		BackupDataOutputBackupSize,
+		BackupDataOutputBackupPhysicalSize,
+		FunctionOutputVersion,
+	}
+}
+
 type backupDataParsedOutput struct {
 	backupID   string
 	backupTag  string
@@ -132,19 +202,43 @@ type backupDataParsedOutput struct {
 	phySize    string
 }
 
-func backupData(ctx context.Context, cli kubernetes.Interface, namespace, pod, container, backupArtifactPrefix, includePath, encryptionKey string, tp param.TemplateParams) (backupDataParsedOutput, error) {
+// backupOptionsFromArgs reads the optional exclusion and snapshot metadata
+// arguments shared by BackupData and BackupDataAll
+func backupOptionsFromArgs(args map[string]interface{}, excludeArg, excludeIfPresentArg, tagsArg, hostnameArg string) (restic.BackupOptions, error) {
+	var opts restic.BackupOptions
+	if err := OptArg(args, excludeArg, &opts.Exclude, nil); err != nil {
+		return opts, err
+	}
+	if err := OptArg(args, excludeIfPresentArg, &opts.ExcludeIfPresent, nil); err != nil {
+		return opts, err
+	}
+	if err := OptArg(args, tagsArg, &opts.Tags, nil); err != nil {
+		return opts, err
+	}
+	if err := OptArg(args, hostnameArg, &opts.Hostname, ""); err != nil {
+		return opts, err
+	}
+	return opts, opts.Validate()
+}
+
+func backupData(ctx context.Context, cli kubernetes.Interface, namespace, pod, container, backupArtifactPrefix, includePath, encryptionKey string, opts restic.BackupOptions, tp param.TemplateParams) (backupDataParsedOutput, error) {
 	pw, err := GetPodWriter(cli, ctx, namespace, pod, container, tp.Profile)
 	if err != nil {
 		return backupDataParsedOutput{}, err
 	}
 	defer CleanUpCredsFile(ctx, pw, namespace, pod, container)
-	if err = restic.GetOrCreateRepository(cli, namespace, pod, container, backupArtifactPrefix, encryptionKey, tp.Profile); err != nil {
+	passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, namespace, pod, container, encryptionKey)
+	if err != nil {
+		return backupDataParsedOutput{}, err
+	}
+	defer CleanUpCredsFile(ctx, kw, namespace, pod, container)
+	if err = restic.GetOrCreateRepository(cli, namespace, pod, container, backupArtifactPrefix, passwordFile, tp.Profile); err != nil {
 		return backupDataParsedOutput{}, err
 	}
 
 	// Create backup and dump it on the object store
 	backupTag := rand.String(10)
-	cmd, err := restic.BackupCommandByTag(tp.Profile, backupArtifactPrefix, backupTag, includePath, encryptionKey)
+	cmd, err := restic.BackupCommandByTag(tp.Profile, backupArtifactPrefix, backupTag, includePath, passwordFile, opts)
 	if err != nil {
 		return backupDataParsedOutput{}, err
 	}
@@ -172,3 +266,49 @@ func backupData(ctx context.Context, cli kubernetes.Interface, namespace, pod, c
 		phySize:    phySize,
 	}, nil
 }
+
+// backupDataWithPod runs the backup in an ephemeral pod on the same node as
+// `pod`, which mounts its PVCs read-only at the same paths. Unless a hostname
+// is given, snapshots are recorded with the name of `pod`, as in exec mode.
+func backupDataWithPod(ctx context.Context, cli kubernetes.Interface, namespace, pod, backupArtifactPrefix, includePath, encryptionKey string, opts restic.BackupOptions, podOverride crv1alpha1.JSONMap, tp param.TemplateParams) (backupDataParsedOutput, error) {
+	if opts.Hostname == "" {
+		opts.Hostname = pod
+	}
+	vols, err := FetchPodVolumes(pod, tp)
+	if err != nil {
+		return backupDataParsedOutput{}, err
+	}
+	p, err := cli.CoreV1().Pods(namespace).Get(pod, metav1.GetOptions{})
+	if err != nil {
+		return backupDataParsedOutput{}, errors.Wrapf(err, "Failed to get pod %s", pod)
+	}
+	// ReadWriteOnce volumes can only be shared with pods on the same node
+	if p.Spec.NodeName != "" {
+		nodeOverride := crv1alpha1.JSONMap{"nodeName": p.Spec.NodeName}
+		if podOverride == nil {
+			podOverride = nodeOverride
+		} else if podOverride, err = kube.CreateAndMergeJsonPatch(nodeOverride, podOverride); err != nil {
+			return backupDataParsedOutput{}, err
+		}
+	}
+	options := &kube.PodOptions{
+		Namespace:       namespace,
+		GenerateName:    backupDataJobPrefix,
+		Image:           kanisterToolsImage,
+		Command:         []string{"sh", "-c", "tail -f /dev/null"},
+		Volumes:         vols,
+		ReadOnlyVolumes: true,
+		PodOverride:     podOverride,
+	}
+	var out backupDataParsedOutput
+	pr := kube.NewPodRunner(cli, options)
+	_, err = pr.Run(ctx, func(ctx context.Context, bp *v1.Pod) (map[string]interface{}, error) {
+		if err := kube.WaitForPodReady(ctx, cli, bp.Namespace, bp.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", bp.Name)
+		}
+		o, err := backupData(ctx, cli, bp.Namespace, bp.Name, bp.Spec.Containers[0].Name, backupArtifactPrefix, includePath, encryptionKey, opts, tp)
+		out = o
+		return nil, err
+	})
+	return out, err
+}
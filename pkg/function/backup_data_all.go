@@ -46,6 +46,14 @@ const (
 	BackupDataAllBackupArtifactPrefixArg = "backupArtifactPrefix"
 	// BackupDataAllEncryptionKeyArg provides the encryption key to be used for backups
 	BackupDataAllEncryptionKeyArg = "encryptionKey"
+	// BackupDataAllExcludeArg provides patterns for files and directories to leave out of the backup
+	BackupDataAllExcludeArg = "exclude"
+	// BackupDataAllExcludeIfPresentArg provides file names that mark directories to leave out of the backup
+	BackupDataAllExcludeIfPresentArg = "excludeIfPresent"
+	// BackupDataAllTagsArg provides tags to add to each snapshot
+	BackupDataAllTagsArg = "tags"
+	// BackupDataAllHostnameArg provides the hostname recorded in each snapshot
+	BackupDataAllHostnameArg = "hostname"
 	// BackupDataAllOutput is the key name of the output generated by BackupDataAll func
 	BackupDataAllOutput = "BackupAllInfo"
 )
@@ -86,7 +94,11 @@ func (*backupDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, arg
 	if err = OptArg(args, BackupDataAllPodsArg, &pods, ""); err != nil {
 		return nil, err
 	}
-	if err = OptArg(args, BackupDataAllEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, BackupDataAllEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	opts, err := backupOptionsFromArgs(args, BackupDataAllExcludeArg, BackupDataAllExcludeIfPresentArg, BackupDataAllTagsArg, BackupDataAllHostnameArg)
+	if err != nil {
 		return nil, err
 	}
 
@@ -114,7 +126,7 @@ func (*backupDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, arg
 		ps = strings.Fields(pods)
 	}
 	ctx = field.Context(ctx, consts.ContainerNameKey, container)
-	return backupDataAll(ctx, cli, namespace, ps, container, backupArtifactPrefix, includePath, encryptionKey, tp)
+	return backupDataAll(ctx, cli, namespace, ps, container, backupArtifactPrefix, includePath, encryptionKey, opts, tp)
 }
 
 func (*backupDataAllFunc) RequiredArgs() []string {
@@ -122,7 +134,29 @@ func (*backupDataAllFunc) RequiredArgs() []string {
 		BackupDataAllIncludePathArg, BackupDataAllBackupArtifactPrefixArg}
 }
 
-func backupDataAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps []string, container string, backupArtifactPrefix, includePath, encryptionKey string, tp param.TemplateParams) (map[string]interface{}, error) {
+func (*backupDataAllFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		BackupDataAllNamespaceArg:            kanister.ArgTypeString,
+		BackupDataAllPodsArg:                 kanister.ArgTypeString,
+		BackupDataAllContainerArg:            kanister.ArgTypeString,
+		BackupDataAllIncludePathArg:          kanister.ArgTypeString,
+		BackupDataAllBackupArtifactPrefixArg: kanister.ArgTypeString,
+		BackupDataAllEncryptionKeyArg:        kanister.ArgTypeString,
+		BackupDataAllExcludeArg:              kanister.ArgTypeStringSlice,
+		BackupDataAllExcludeIfPresentArg:     kanister.ArgTypeStringSlice,
+		BackupDataAllTagsArg:                 kanister.ArgTypeStringSlice,
+		BackupDataAllHostnameArg:             kanister.ArgTypeString,
+	}
+}
+
+func (*backupDataAllFunc) Outputs() []string {
+	return []string{
+		BackupDataAllOutput,
+		FunctionOutputVersion,
+	}
+}
+
+func backupDataAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps []string, container string, backupArtifactPrefix, includePath, encryptionKey string, opts restic.BackupOptions, tp param.TemplateParams) (map[string]interface{}, error) {
 	errChan := make(chan error, len(ps))
 	outChan := make(chan BackupInfo, len(ps))
 	Output := make(map[string]BackupInfo)
@@ -130,7 +164,7 @@ func backupDataAll(ctx context.Context, cli kubernetes.Interface, namespace stri
 	for _, pod := range ps {
 		go func(pod string, container string) {
 			ctx = field.Context(ctx, consts.PodNameKey, pod)
-			backupOutputs, err := backupData(ctx, cli, namespace, pod, container, fmt.Sprintf("%s/%s", backupArtifactPrefix, pod), includePath, encryptionKey, tp)
+			backupOutputs, err := backupData(ctx, cli, namespace, pod, container, fmt.Sprintf("%s/%s", backupArtifactPrefix, pod), includePath, encryptionKey, opts, tp)
 			errChan <- errors.Wrapf(err, "Failed to backup data for pod %s", pod)
 			outChan <- BackupInfo{PodName: pod, BackupID: backupOutputs.backupID, BackupTag: backupOutputs.backupTag}
 		}(pod, container)
@@ -83,7 +83,12 @@ func backupDataStatsPodFunc(cli kubernetes.Interface, tp param.TemplateParams, n
 			return nil, err
 		}
 		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
-		cmd, err := restic.StatsCommandByID(tp.Profile, backupArtifactPrefix, backupID, mode, encryptionKey)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		cmd, err := restic.StatsCommandByID(tp.Profile, backupArtifactPrefix, backupID, mode, passwordFile)
 		if err != nil {
 			return nil, err
 		}
@@ -123,7 +128,7 @@ func (*BackupDataStatsFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err = OptArg(args, BackupDataStatsMode, &mode, defaultStatsMode); err != nil {
 		return nil, err
 	}
-	if err = OptArg(args, BackupDataStatsEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, BackupDataStatsEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
 		return nil, err
 	}
 
@@ -143,3 +148,22 @@ func (*BackupDataStatsFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 func (*BackupDataStatsFunc) RequiredArgs() []string {
 	return []string{BackupDataStatsNamespaceArg, BackupDataStatsBackupArtifactPrefixArg}
 }
+
+func (*BackupDataStatsFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		BackupDataStatsNamespaceArg:            kanister.ArgTypeString,
+		BackupDataStatsBackupArtifactPrefixArg: kanister.ArgTypeString,
+		BackupDataStatsBackupIdentifierArg:     kanister.ArgTypeString,
+		BackupDataStatsMode:                    kanister.ArgTypeString,
+		BackupDataStatsEncryptionKeyArg:        kanister.ArgTypeString,
+	}
+}
+
+func (*BackupDataStatsFunc) Outputs() []string {
+	return []string{
+		BackupDataStatsOutputMode,
+		BackupDataStatsOutputFileCount,
+		BackupDataStatsOutputSize,
+		FunctionOutputVersion,
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+type BackupDataSuite struct{}
+
+var _ = Suite(&BackupDataSuite{})
+
+func (s *BackupDataSuite) TestInvalidMode(c *C) {
+	tp := newValidStatefulSetTP()
+	tp.Profile = newValidProfile()
+	args := map[string]interface{}{
+		BackupDataNamespaceArg:            "test-namespace",
+		BackupDataPodArg:                  "pod1",
+		BackupDataContainerArg:            "test-container",
+		BackupDataIncludePathArg:          "path1",
+		BackupDataBackupArtifactPrefixArg: "bucket/prefix",
+		BackupDataModeArg:                 "sidecar",
+	}
+	_, err := (&backupDataFunc{}).Exec(context.Background(), tp, args)
+	c.Assert(err, ErrorMatches, "Unsupported mode 'sidecar'.*")
+}
+
+func (s *BackupDataSuite) TestBackupOptionsFromArgs(c *C) {
+	for _, tc := range []struct {
+		name       string
+		args       map[string]interface{}
+		expected   restic.BackupOptions
+		errChecker Checker
+	}{
+		{
+			name:       "No options",
+			args:       map[string]interface{}{},
+			expected:   restic.BackupOptions{},
+			errChecker: IsNil,
+		},
+		{
+			name: "All options",
+			args: map[string]interface{}{
+				BackupDataExcludeArg:          []interface{}{"/data/tmp", "*.log"},
+				BackupDataExcludeIfPresentArg: []interface{}{"CACHEDIR.TAG"},
+				BackupDataTagsArg:             []interface{}{"app=mysql", "namespace=prod"},
+				BackupDataHostnameArg:         "mysql",
+			},
+			expected: restic.BackupOptions{
+				Exclude:          []string{"/data/tmp", "*.log"},
+				ExcludeIfPresent: []string{"CACHEDIR.TAG"},
+				Tags:             []string{"app=mysql", "namespace=prod"},
+				Hostname:         "mysql",
+			},
+			errChecker: IsNil,
+		},
+		{
+			name:       "Tag with comma",
+			args:       map[string]interface{}{BackupDataTagsArg: []interface{}{"a,b"}},
+			errChecker: NotNil,
+		},
+		{
+			name:       "Empty tag",
+			args:       map[string]interface{}{BackupDataTagsArg: []interface{}{""}},
+			errChecker: NotNil,
+		},
+	} {
+		opts, err := backupOptionsFromArgs(tc.args, BackupDataExcludeArg, BackupDataExcludeIfPresentArg, BackupDataTagsArg, BackupDataHostnameArg)
+		c.Check(err, tc.errChecker, Commentf("Case %s failed", tc.name))
+		if err == nil {
+			c.Check(opts, DeepEquals, tc.expected, Commentf("Case %s failed", tc.name))
+		}
+	}
+}
+
+func (s *BackupDataSuite) TestBackupPodSpec(c *C) {
+	ctx := context.Background()
+	tp := newValidStatefulSetTP()
+	tp.Profile = newValidProfile()
+	appPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod2",
+			Namespace: "test-namespace",
+		},
+		Spec: v1.PodSpec{
+			NodeName: "node1",
+		},
+	}
+	cli := fake.NewSimpleClientset(appPod)
+	var created *v1.Pod
+	cli.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created = action.(k8stesting.CreateAction).GetObject().(*v1.Pod)
+		return true, nil, errors.New("not creating pods in test")
+	})
+
+	podOverride := crv1alpha1.JSONMap{"serviceAccountName": "backup-sa"}
+	_, err := backupDataWithPod(ctx, cli, "test-namespace", "pod2", "bucket/prefix", "path2", "key", restic.BackupOptions{}, podOverride, tp)
+	c.Assert(err, NotNil)
+	c.Assert(created, NotNil)
+	// The pod runs on the same node as the application pod
+	c.Check(created.Spec.NodeName, Equals, "node1")
+	c.Check(created.Spec.ServiceAccountName, Equals, "backup-sa")
+	c.Assert(created.Spec.Volumes, HasLen, 2)
+	mounts := map[string]string{}
+	for _, vol := range created.Spec.Volumes {
+		c.Check(vol.PersistentVolumeClaim.ReadOnly, Equals, true)
+	}
+	for _, m := range created.Spec.Containers[0].VolumeMounts {
+		c.Check(m.ReadOnly, Equals, true)
+		mounts[m.Name] = m.MountPath
+	}
+	c.Check(mounts, DeepEquals, map[string]string{"vol-pvc2": "path2", "vol-pvc3": "path3"})
+
+	// Pods without PVCs cannot be backed up this way
+	created = nil
+	_, err = backupDataWithPod(ctx, cli, "test-namespace", "pod3", "bucket/prefix", "path2", "key", restic.BackupOptions{}, nil, tp)
+	c.Assert(err, NotNil)
+	c.Assert(created, IsNil)
+}
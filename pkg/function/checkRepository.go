@@ -69,7 +69,12 @@ func CheckRepositoryPodFunc(cli kubernetes.Interface, tp param.TemplateParams, n
 			return nil, err
 		}
 		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
-		err = restic.CheckIfRepoIsReachable(tp.Profile, targetPath, encryptionKey, cli, namespace, pod.Name, pod.Spec.Containers[0].Name)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		err = restic.CheckIfRepoIsReachable(tp.Profile, targetPath, passwordFile, cli, namespace, pod.Name, pod.Spec.Containers[0].Name)
 		switch {
 		case err == nil:
 			break
@@ -102,7 +107,7 @@ func (*CheckRepositoryFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err := Arg(args, CheckRepositoryArtifactPrefixArg, &checkRepositoryArtifactPrefix); err != nil {
 		return nil, err
 	}
-	if err := OptArg(args, CheckRepositoryEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err := OptArg(args, CheckRepositoryEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
 		return nil, err
 	}
 	podOverride, err := GetPodSpecOverride(tp, args, CheckRepositoryPodOverrideArg)
@@ -126,3 +131,19 @@ func (*CheckRepositoryFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 func (*CheckRepositoryFunc) RequiredArgs() []string {
 	return []string{CheckRepositoryArtifactPrefixArg}
 }
+
+func (*CheckRepositoryFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		CheckRepositoryArtifactPrefixArg: kanister.ArgTypeString,
+		CheckRepositoryEncryptionKeyArg:  kanister.ArgTypeString,
+		CheckRepositoryPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*CheckRepositoryFunc) Outputs() []string {
+	return []string{
+		CheckRepositoryPasswordIncorrect,
+		CheckRepositoryRepoDoesNotExist,
+		FunctionOutputVersion,
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/format"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/metrics"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/restic"
 )
@@ -95,13 +96,18 @@ func copyVolumeDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, na
 			return nil, err
 		}
 		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
 		// Get restic repository
-		if err := restic.GetOrCreateRepository(cli, namespace, pod.Name, pod.Spec.Containers[0].Name, targetPath, encryptionKey, tp.Profile); err != nil {
+		if err := restic.GetOrCreateRepository(cli, namespace, pod.Name, pod.Spec.Containers[0].Name, targetPath, passwordFile, tp.Profile); err != nil {
 			return nil, err
 		}
 		// Copy data to object store
 		backupTag := rand.String(10)
-		cmd, err := restic.BackupCommandByTag(tp.Profile, targetPath, backupTag, mountPoint, encryptionKey)
+		cmd, err := restic.BackupCommandByTag(tp.Profile, targetPath, backupTag, mountPoint, passwordFile, restic.BackupOptions{})
 		if err != nil {
 			return nil, err
 		}
@@ -120,6 +126,7 @@ func copyVolumeDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, na
 		if backupSize == "" {
 			log.Debug().Print("Could not parse backup stats from backup log")
 		}
+		metrics.DataBytes.WithLabelValues(CopyVolumeDataFuncName).Add(float64(restic.ParseResticSizeStringBytes(phySize)))
 		return map[string]interface{}{
 				CopyVolumeDataOutputBackupID:               backupID,
 				CopyVolumeDataOutputBackupRoot:             mountPoint,
@@ -146,7 +153,7 @@ func (*copyVolumeDataFunc) Exec(ctx context.Context, tp param.TemplateParams, ar
 	if err = Arg(args, CopyVolumeDataArtifactPrefixArg, &targetPath); err != nil {
 		return nil, err
 	}
-	if err = OptArg(args, CopyVolumeDataEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, CopyVolumeDataEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
 		return nil, err
 	}
 	podOverride, err := GetPodSpecOverride(tp, args, CopyVolumeDataPodOverrideArg)
@@ -170,3 +177,26 @@ func (*copyVolumeDataFunc) Exec(ctx context.Context, tp param.TemplateParams, ar
 func (*copyVolumeDataFunc) RequiredArgs() []string {
 	return []string{CopyVolumeDataNamespaceArg, CopyVolumeDataVolumeArg, CopyVolumeDataArtifactPrefixArg}
 }
+
+func (*copyVolumeDataFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		CopyVolumeDataNamespaceArg:      kanister.ArgTypeString,
+		CopyVolumeDataVolumeArg:         kanister.ArgTypeString,
+		CopyVolumeDataArtifactPrefixArg: kanister.ArgTypeString,
+		CopyVolumeDataEncryptionKeyArg:  kanister.ArgTypeString,
+		CopyVolumeDataPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*copyVolumeDataFunc) Outputs() []string {
+	return []string{
+		CopyVolumeDataOutputBackupID,
+		CopyVolumeDataOutputBackupRoot,
+		CopyVolumeDataOutputBackupArtifactLocation,
+		CopyVolumeDataOutputBackupTag,
+		CopyVolumeDataOutputBackupFileCount,
+		CopyVolumeDataOutputBackupSize,
+		CopyVolumeDataOutputPhysicalSize,
+		FunctionOutputVersion,
+	}
+}
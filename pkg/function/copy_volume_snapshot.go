@@ -0,0 +1,177 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	awsconfig "github.com/kanisterio/kanister/pkg/aws"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+	"github.com/kanisterio/kanister/pkg/blockstorage/zone"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+func init() {
+	_ = kanister.Register(&copyVolumeSnapshotFunc{})
+}
+
+var (
+	_ kanister.Func = (*copyVolumeSnapshotFunc)(nil)
+)
+
+const (
+	// CopyVolumeSnapshotFuncName gives the name of the function
+	CopyVolumeSnapshotFuncName     = "CopyVolumeSnapshot"
+	CopyVolumeSnapshotSnapshotsArg = "snapshots"
+	CopyVolumeSnapshotRegionArg    = "region"
+	// CopyVolumeSnapshotOutputVolumeSnapshotInfo gives the info of the copied snapshots
+	CopyVolumeSnapshotOutputVolumeSnapshotInfo = "volumeSnapshotInfo"
+)
+
+type copyVolumeSnapshotFunc struct{}
+
+func (*copyVolumeSnapshotFunc) Name() string {
+	return CopyVolumeSnapshotFuncName
+}
+
+func (*copyVolumeSnapshotFunc) RequiredArgs() []string {
+	return []string{CopyVolumeSnapshotSnapshotsArg, CopyVolumeSnapshotRegionArg}
+}
+
+func (*copyVolumeSnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		CopyVolumeSnapshotSnapshotsArg: kanister.ArgTypeString,
+		CopyVolumeSnapshotRegionArg:    kanister.ArgTypeString,
+	}
+}
+
+func (*copyVolumeSnapshotFunc) Outputs() []string {
+	return []string{CopyVolumeSnapshotOutputVolumeSnapshotInfo}
+}
+
+func (*copyVolumeSnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var snapshotinfo, region string
+	if err := Arg(args, CopyVolumeSnapshotSnapshotsArg, &snapshotinfo); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, CopyVolumeSnapshotRegionArg, &region); err != nil {
+		return nil, err
+	}
+	return copyVolumeSnapshots(ctx, snapshotinfo, region, tp.Profile, getter.New())
+}
+
+func copyVolumeSnapshots(ctx context.Context, snapshotinfo, region string, profile *param.Profile, getter getter.Getter) (map[string]interface{}, error) {
+	PVCData := []VolumeSnapshotInfo{}
+	err := json.Unmarshal([]byte(snapshotinfo), &PVCData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not decode JSON data")
+	}
+	if region == "" {
+		return nil, errors.New("Destination region must be specified")
+	}
+
+	copies := make([]VolumeSnapshotInfo, 0, len(PVCData))
+	for _, pvcInfo := range PVCData {
+		if err = ValidateLocationForBlockstorage(profile, pvcInfo.Type); err != nil {
+			return nil, errors.Wrap(err, "Profile validation failed")
+		}
+		config := getConfig(profile, pvcInfo.Type)
+		if pvcInfo.Type == blockstorage.TypeEBS {
+			config[awsconfig.ConfigRegion] = pvcInfo.Region
+		}
+
+		provider, err := getter.Get(pvcInfo.Type, config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not get storage provider %v", pvcInfo.Type)
+		}
+		snapshot, err := provider.SnapshotGet(ctx, pvcInfo.SnapshotID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get Snapshot from Provider")
+		}
+		if snapshot.Region == "" {
+			snapshot.Region = pvcInfo.Region
+		}
+		to := blockstorage.Snapshot{
+			Type:      snapshot.Type,
+			Encrypted: snapshot.Encrypted,
+			Size:      snapshot.Size,
+			Region:    region,
+		}
+		snap, err := provider.SnapshotCopy(ctx, *snapshot, to)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to copy snapshot %s to region %s", pvcInfo.SnapshotID, region)
+		}
+		log.Print("Snapshot copied", field.M{"FromSnapshotID": pvcInfo.SnapshotID, "ToSnapshotID": snap.ID, "Region": region})
+		copies = append(copies, VolumeSnapshotInfo{
+			SnapshotID: snap.ID,
+			Type:       pvcInfo.Type,
+			Region:     region,
+			PVCName:    pvcInfo.PVCName,
+			Az:         copiedSnapshotAz(ctx, provider, pvcInfo.Type, region, pvcInfo.Az),
+			Tags:       pvcInfo.Tags,
+			VolumeType: pvcInfo.VolumeType,
+		})
+	}
+
+	manifestData, err := json.Marshal(copies)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to encode JSON data")
+	}
+	return map[string]interface{}{CopyVolumeSnapshotOutputVolumeSnapshotInfo: string(manifestData)}, nil
+}
+
+// copiedSnapshotAz returns the zones of the target region that a volume
+// restored from a snapshot copy should be created in. Each source zone is
+// mapped to the zone of the region with the same suffix, or an arbitrary one.
+// An empty string is returned if the zones of the region are unknown.
+func copiedSnapshotAz(ctx context.Context, provider blockstorage.Provider, sType blockstorage.Type, region, az string) string {
+	if sType == blockstorage.TypeAD {
+		// Azure locations are regions.
+		return region
+	}
+	m, ok := provider.(zone.Mapper)
+	if !ok || az == "" {
+		return ""
+	}
+	zs, err := m.FromRegion(ctx, region)
+	if err != nil {
+		log.WithError(err).Print("Could not get zones of region", field.M{"Region": region})
+		return ""
+	}
+	if len(zs) == 0 {
+		log.Print("Region has no zones", field.M{"Region": region})
+		return ""
+	}
+	// Regional GCE persistent disks span two zones separated by "__".
+	newZones := make(map[string]struct{})
+	var zones []string
+	for _, z := range strings.Split(az, "__") {
+		nz := zone.WithUnknownNodeZones(ctx, m, region, z, newZones)
+		if nz == "" {
+			continue
+		}
+		newZones[nz] = struct{}{}
+		zones = append(zones, nz)
+	}
+	return strings.Join(zones, "__")
+}
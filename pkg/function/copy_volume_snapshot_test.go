@@ -0,0 +1,134 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/testutil/mockblockstorage"
+)
+
+type CopyVolumeSnapshotTestSuite struct{}
+
+var _ = Suite(&CopyVolumeSnapshotTestSuite{})
+
+func (s *CopyVolumeSnapshotTestSuite) TestCopyVolumeSnapshots(c *C) {
+	ctx := context.Background()
+	mockGetter := mockblockstorage.NewGetter()
+	profile := &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Region: "us-west-2",
+		},
+		Credential: param.Credential{
+			Type: param.CredentialTypeKeyPair,
+			KeyPair: &param.KeyPair{
+				ID:     "foo",
+				Secret: "bar",
+			},
+		},
+	}
+	pvcData := []VolumeSnapshotInfo{
+		{SnapshotID: "snap-1", Type: blockstorage.TypeEBS, Region: "us-west-2", PVCName: "pvc-1", Az: "us-west-2a", VolumeType: "ssd"},
+		{SnapshotID: "snap-2", Type: blockstorage.TypeEBS, Region: "us-west-2", PVCName: "pvc-2", Az: "us-west-2a", VolumeType: "ssd"},
+	}
+	info, err := json.Marshal(pvcData)
+	c.Assert(err, IsNil)
+
+	for _, tc := range []struct {
+		snapshotinfo string
+		region       string
+		check        Checker
+	}{
+		{
+			snapshotinfo: string(info),
+			region:       "us-east-1",
+			check:        IsNil,
+		},
+		{
+			snapshotinfo: string(info),
+			region:       "",
+			check:        NotNil,
+		},
+		{
+			snapshotinfo: "invalid",
+			region:       "us-east-1",
+			check:        NotNil,
+		},
+	} {
+		out, err := copyVolumeSnapshots(ctx, tc.snapshotinfo, tc.region, profile, mockGetter)
+		c.Assert(err, tc.check)
+		if err != nil {
+			continue
+		}
+		copies := []VolumeSnapshotInfo{}
+		err = json.Unmarshal([]byte(out[CopyVolumeSnapshotOutputVolumeSnapshotInfo].(string)), &copies)
+		c.Assert(err, IsNil)
+		c.Assert(copies, HasLen, len(pvcData))
+		for i, cp := range copies {
+			c.Check(cp.Region, Equals, tc.region)
+			c.Check(cp.PVCName, Equals, pvcData[i].PVCName)
+			c.Check(cp.Type, Equals, pvcData[i].Type)
+			c.Check(cp.SnapshotID, Not(Equals), "")
+			// The mock provider doesn't know the zones of the region.
+			c.Check(cp.Az, Equals, "")
+		}
+	}
+}
+
+type zoneMapperProvider struct {
+	*mockblockstorage.Provider
+	zones map[string][]string
+}
+
+func (p zoneMapperProvider) FromRegion(ctx context.Context, region string) ([]string, error) {
+	return p.zones[region], nil
+}
+
+func (s *CopyVolumeSnapshotTestSuite) TestCopiedSnapshotAz(c *C) {
+	ctx := context.Background()
+	provider := zoneMapperProvider{
+		Provider: mockblockstorage.Get(blockstorage.TypeEBS),
+		zones: map[string][]string{
+			"us-east-1":   {"us-east-1a", "us-east-1b"},
+			"us-central1": {"us-central1-a", "us-central1-b", "us-central1-c"},
+		},
+	}
+	for _, tc := range []struct {
+		provider blockstorage.Provider
+		sType    blockstorage.Type
+		region   string
+		az       string
+		out      string
+	}{
+		{provider: provider, sType: blockstorage.TypeEBS, region: "us-east-1", az: "us-west-2b", out: "us-east-1b"},
+		// No zone with the same suffix.
+		{provider: provider, sType: blockstorage.TypeEBS, region: "us-east-1", az: "us-west-2d", out: "us-east-1a"},
+		{provider: provider, sType: blockstorage.TypeGPD, region: "us-central1", az: "us-west1-a__us-west1-c", out: "us-central1-a__us-central1-c"},
+		// Unknown region.
+		{provider: provider, sType: blockstorage.TypeEBS, region: "eu-west-1", az: "us-west-2b", out: ""},
+		// Provider without zone mapping.
+		{provider: mockblockstorage.Get(blockstorage.TypeEBS), sType: blockstorage.TypeEBS, region: "us-east-1", az: "us-west-2b", out: ""},
+		{provider: mockblockstorage.Get(blockstorage.TypeAD), sType: blockstorage.TypeAD, region: "westus", az: "eastus", out: "westus"},
+	} {
+		c.Check(copiedSnapshotAz(ctx, tc.provider, tc.sType, tc.region, tc.az), Equals, tc.out, Commentf("%+v", tc))
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+func init() {
+	_ = kanister.Register(&createCSISnapshotFunc{})
+}
+
+var (
+	_ kanister.Func = (*createCSISnapshotFunc)(nil)
+)
+
+const (
+	// CreateCSISnapshotFuncName gives the name of the function
+	CreateCSISnapshotFuncName = "CreateCSISnapshot"
+	// CreateCSISnapshotNameArg provides the name of the VolumeSnapshot
+	CreateCSISnapshotNameArg = "name"
+	// CreateCSISnapshotPVCNameArg provides the name of the PVC to be snapshotted
+	CreateCSISnapshotPVCNameArg = "pvc"
+	// CreateCSISnapshotNamespaceArg mentions the namespace of the PVC
+	CreateCSISnapshotNamespaceArg = "namespace"
+	// CreateCSISnapshotSnapshotClassArg specifies the name of the VolumeSnapshotClass
+	CreateCSISnapshotSnapshotClassArg = "snapshotClass"
+	// CreateCSISnapshotWaitForReadyArg specifies whether to wait for the VolumeSnapshot to be ready to use
+	CreateCSISnapshotWaitForReadyArg = "waitForReady"
+
+	// CSISnapshotOutputNameArg gives the name of the VolumeSnapshot
+	CSISnapshotOutputNameArg = "name"
+	// CSISnapshotOutputNamespaceArg gives the namespace of the VolumeSnapshot
+	CSISnapshotOutputNamespaceArg = "namespace"
+	// CSISnapshotOutputRestoreSizeArg gives the restore size of the VolumeSnapshot.
+	// It is empty until the VolumeSnapshot is ready to use.
+	CSISnapshotOutputRestoreSizeArg = "restoreSize"
+)
+
+type createCSISnapshotFunc struct{}
+
+func (*createCSISnapshotFunc) Name() string {
+	return CreateCSISnapshotFuncName
+}
+
+func (*createCSISnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var name, pvc, namespace, snapshotClass string
+	var waitForReady bool
+	if err := Arg(args, CreateCSISnapshotPVCNameArg, &pvc); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, CreateCSISnapshotNamespaceArg, &namespace); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, CreateCSISnapshotSnapshotClassArg, &snapshotClass); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CreateCSISnapshotNameArg, &name, defaultSnapshotName(pvc)); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CreateCSISnapshotWaitForReadyArg, &waitForReady, true); err != nil {
+		return nil, err
+	}
+	_, snapshotter, err := newSnapshotter()
+	if err != nil {
+		return nil, err
+	}
+	return createCSISnapshot(ctx, snapshotter, name, namespace, pvc, snapshotClass, waitForReady)
+}
+
+func (*createCSISnapshotFunc) RequiredArgs() []string {
+	return []string{
+		CreateCSISnapshotPVCNameArg,
+		CreateCSISnapshotNamespaceArg,
+		CreateCSISnapshotSnapshotClassArg,
+	}
+}
+
+func (*createCSISnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		CreateCSISnapshotNameArg:          kanister.ArgTypeString,
+		CreateCSISnapshotPVCNameArg:       kanister.ArgTypeString,
+		CreateCSISnapshotNamespaceArg:     kanister.ArgTypeString,
+		CreateCSISnapshotSnapshotClassArg: kanister.ArgTypeString,
+		CreateCSISnapshotWaitForReadyArg:  kanister.ArgTypeBool,
+	}
+}
+
+func (*createCSISnapshotFunc) Outputs() []string {
+	return csiSnapshotOutputs()
+}
+
+func createCSISnapshot(ctx context.Context, snapshotter snapshot.Snapshotter, name, namespace, pvc, snapshotClass string, waitForReady bool) (map[string]interface{}, error) {
+	if err := snapshotter.Create(ctx, name, namespace, pvc, &snapshotClass, waitForReady); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create VolumeSnapshot %s of PVC %s, Namespace %s", name, pvc, namespace)
+	}
+	return csiSnapshotOutput(ctx, snapshotter, name, namespace)
+}
+
+// csiSnapshotOutput returns the output of the CSI snapshot functions for the
+// VolumeSnapshot.
+func csiSnapshotOutput(ctx context.Context, snapshotter snapshot.Snapshotter, name, namespace string) (map[string]interface{}, error) {
+	snap, err := snapshotter.Get(ctx, name, namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get VolumeSnapshot %s, Namespace %s", name, namespace)
+	}
+	restoreSize := ""
+	if snap.Status.RestoreSize != nil {
+		restoreSize = snap.Status.RestoreSize.String()
+	}
+	return map[string]interface{}{
+		CSISnapshotOutputNameArg:        name,
+		CSISnapshotOutputNamespaceArg:   namespace,
+		CSISnapshotOutputRestoreSizeArg: restoreSize,
+	}, nil
+}
+
+func csiSnapshotOutputs() []string {
+	return []string{
+		CSISnapshotOutputNameArg,
+		CSISnapshotOutputNamespaceArg,
+		CSISnapshotOutputRestoreSizeArg,
+	}
+}
+
+func defaultSnapshotName(pvc string) string {
+	return pvc + "-snapshot-" + rand.String(5)
+}
@@ -100,3 +100,17 @@ func (crs *createRDSSnapshotFunc) Exec(ctx context.Context, tp param.TemplatePar
 func (*createRDSSnapshotFunc) RequiredArgs() []string {
 	return []string{CreateRDSSnapshotInstanceIDArg, CreateRDSSnapshotSnapshotIDArg}
 }
+
+func (*createRDSSnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		CreateRDSSnapshotInstanceIDArg: kanister.ArgTypeString,
+		CreateRDSSnapshotSnapshotIDArg: kanister.ArgTypeString,
+	}
+}
+
+func (*createRDSSnapshotFunc) Outputs() []string {
+	return []string{
+		CreateRDSSnapshotSnapshotIDArg,
+		CreateRDSSnapshotInstanceIDArg,
+	}
+}
@@ -110,7 +110,7 @@ func createVolumeFromSnapshot(ctx context.Context, cli kubernetes.Interface, nam
 		if err != nil {
 			return nil, errors.Wrapf(err, "Unable to create PVC for volume %v", *vol)
 		}
-		pv, err := kubevolume.CreatePV(ctx, cli, vol, vol.Type, annotations)
+		pv, err := kubevolume.CreatePV(ctx, cli, vol, vol.Type, annotations, pvcInfo.FSType)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Unable to create PV for volume %v", *vol)
 		}
@@ -143,3 +143,15 @@ func (kef *createVolumeFromSnapshotFunc) Exec(ctx context.Context, tp param.Temp
 func (*createVolumeFromSnapshotFunc) RequiredArgs() []string {
 	return []string{CreateVolumeFromSnapshotNamespaceArg, CreateVolumeFromSnapshotManifestArg}
 }
+
+func (*createVolumeFromSnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		CreateVolumeFromSnapshotNamespaceArg: kanister.ArgTypeString,
+		CreateVolumeFromSnapshotManifestArg:  kanister.ArgTypeString,
+		CreateVolumeFromSnapshotPVCNamesArg:  kanister.ArgTypeStringSlice,
+	}
+}
+
+func (*createVolumeFromSnapshotFunc) Outputs() []string {
+	return []string{}
+}
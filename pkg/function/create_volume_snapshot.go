@@ -33,6 +33,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/blockstorage"
 	"github.com/kanisterio/kanister/pkg/blockstorage/awsebs"
 	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+	"github.com/kanisterio/kanister/pkg/blockstorage/vmware"
 	"github.com/kanisterio/kanister/pkg/kube"
 	kubevolume "github.com/kanisterio/kanister/pkg/kube/volume"
 	"github.com/kanisterio/kanister/pkg/param"
@@ -53,6 +54,9 @@ const (
 	CreateVolumeSnapshotNamespaceArg = "namespace"
 	CreateVolumeSnapshotPVCsArg      = "pvcs"
 	CreateVolumeSnapshotSkipWaitArg  = "skipWait"
+	// CreateVolumeSnapshotOutputVolumeSnapshotInfo is the key of the output
+	// listing the snapshots that were created
+	CreateVolumeSnapshotOutputVolumeSnapshotInfo = "volumeSnapshotInfo"
 )
 
 type createVolumeSnapshotFunc struct{}
@@ -69,6 +73,8 @@ type VolumeSnapshotInfo struct {
 	Az         string
 	Tags       blockstorage.VolumeTags
 	VolumeType string
+	// FSType is the filesystem of the volume. It is only set for FCDs.
+	FSType string `json:",omitempty"`
 }
 
 type volumeInfo struct {
@@ -79,6 +85,7 @@ type volumeInfo struct {
 	pvc      string
 	size     int64
 	region   string
+	fsType   string
 }
 
 func ValidateLocationForBlockstorage(profile *param.Profile, sType blockstorage.Type) error {
@@ -97,6 +104,10 @@ func ValidateLocationForBlockstorage(profile *param.Profile, sType blockstorage.
 		if profile.Location.Type != crv1alpha1.LocationTypeGCS {
 			return errors.Errorf("Location type %s not supported for blockstorage type %s", profile.Location.Type, sType)
 		}
+	case blockstorage.TypeFCD:
+		if profile.Credential.Type != param.CredentialTypeSecret || string(profile.Credential.Secret.Type) != secrets.VSphereSecretType {
+			return errors.Errorf("Credential must be a secret of type %s for blockstorage type %s", secrets.VSphereSecretType, sType)
+		}
 	default:
 		return errors.Errorf("Storage provider not supported %s", sType)
 	}
@@ -140,7 +151,7 @@ func createVolumeSnapshot(ctx context.Context, tp param.TemplateParams, cli kube
 		return nil, errors.Wrapf(err, "Failed to encode JSON data")
 	}
 
-	return map[string]interface{}{"volumeSnapshotInfo": string(manifestData)}, nil
+	return map[string]interface{}{CreateVolumeSnapshotOutputVolumeSnapshotInfo: string(manifestData)}, nil
 }
 
 func snapshotVolume(ctx context.Context, volume volumeInfo, namespace string, skipWait bool) (*VolumeSnapshotInfo, error) {
@@ -169,7 +180,7 @@ func snapshotVolume(ctx context.Context, volume volumeInfo, namespace string, sk
 			return nil, errors.Wrap(err, "Snapshot creation did not complete")
 		}
 	}
-	return &VolumeSnapshotInfo{SnapshotID: snap.ID, Type: volume.sType, Region: volume.region, PVCName: volume.pvc, Az: snap.Volume.Az, Tags: snap.Volume.Tags, VolumeType: snap.Volume.VolumeType}, nil
+	return &VolumeSnapshotInfo{SnapshotID: snap.ID, Type: volume.sType, Region: volume.region, PVCName: volume.pvc, Az: snap.Volume.Az, Tags: snap.Volume.Tags, VolumeType: snap.Volume.VolumeType, FSType: volume.fsType}, nil
 }
 
 func getPVCInfo(ctx context.Context, kubeCli kubernetes.Interface, namespace string, name string, tp param.TemplateParams, getter getter.Getter) (*volumeInfo, error) {
@@ -236,6 +247,21 @@ func getPVCInfo(ctx context.Context, kubeCli kubernetes.Interface, namespace str
 			return &volumeInfo{provider: provider, volumeID: filepath.Base(gpd.PDName), sType: blockstorage.TypeGPD, volZone: pvZone, pvc: name, size: size, region: region}, nil
 		}
 		return nil, errors.Errorf("PV zone label is empty, pvName: %s, namespace: %s", pvName, namespace)
+
+	case pv.Spec.CSI != nil && pv.Spec.CSI.Driver == kubevolume.VSphereCSIDriverName:
+		if err = ValidateLocationForBlockstorage(tp.Profile, blockstorage.TypeFCD); err != nil {
+			return nil, errors.Wrap(err, "Profile validation failed")
+		}
+		config := getConfig(tp.Profile, blockstorage.TypeFCD)
+		provider, err = getter.Get(blockstorage.TypeFCD, config)
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not get storage provider")
+		}
+		fsType, err := kubevolume.CSIFSType(kubeCli, pv)
+		if err != nil {
+			return nil, err
+		}
+		return &volumeInfo{provider: provider, volumeID: pv.Spec.CSI.VolumeHandle, sType: blockstorage.TypeFCD, pvc: name, size: size, fsType: fsType}, nil
 	}
 	return nil, errors.New("Storage type not supported!")
 }
@@ -303,6 +329,11 @@ func getConfig(profile *param.Profile, sType blockstorage.Type) map[string]strin
 	case blockstorage.TypeGPD:
 		config[blockstorage.GoogleProjectID] = profile.Credential.KeyPair.ID
 		config[blockstorage.GoogleServiceKey] = profile.Credential.KeyPair.Secret
+	case blockstorage.TypeFCD:
+		config[vmware.VSphereEndpointKey] = string(profile.Credential.Secret.Data[secrets.VSphereEndpoint])
+		config[vmware.VSphereUsernameKey] = string(profile.Credential.Secret.Data[secrets.VSphereUsername])
+		config[vmware.VSpherePasswordKey] = string(profile.Credential.Secret.Data[secrets.VSpherePassword])
+		config[vmware.VSphereDatastoreKey] = string(profile.Credential.Secret.Data[secrets.VSphereDatastore])
 	}
 	return config
 }
@@ -310,3 +341,17 @@ func getConfig(profile *param.Profile, sType blockstorage.Type) map[string]strin
 func (*createVolumeSnapshotFunc) RequiredArgs() []string {
 	return []string{CreateVolumeSnapshotNamespaceArg}
 }
+
+func (*createVolumeSnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		CreateVolumeSnapshotNamespaceArg: kanister.ArgTypeString,
+		CreateVolumeSnapshotPVCsArg:      kanister.ArgTypeStringSlice,
+		CreateVolumeSnapshotSkipWaitArg:  kanister.ArgTypeBool,
+	}
+}
+
+func (*createVolumeSnapshotFunc) Outputs() []string {
+	return []string{
+		CreateVolumeSnapshotOutputVolumeSnapshotInfo,
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 	. "gopkg.in/check.v1"
 	"k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -27,6 +28,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/blockstorage"
 	kubevolume "github.com/kanisterio/kanister/pkg/kube/volume"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/secrets"
 	"github.com/kanisterio/kanister/pkg/testutil/mockblockstorage"
 )
 
@@ -177,3 +179,88 @@ func (s *CreateVolumeSnapshotTestSuite) TestGetPVCInfo(c *C) {
 		c.Assert(volInfo.region, Equals, tc.wantRegion)
 	}
 }
+
+func (s *CreateVolumeSnapshotTestSuite) TestGetPVCInfoFCD(c *C) {
+	ctx := context.Background()
+	ns := "ns"
+	mockGetter := mockblockstorage.NewGetter()
+	tp := param.TemplateParams{
+		Profile: &param.Profile{
+			Location: crv1alpha1.Location{
+				Type: crv1alpha1.LocationTypeS3Compliant,
+			},
+			Credential: param.Credential{
+				Type: param.CredentialTypeSecret,
+				Secret: &v1.Secret{
+					Type: v1.SecretType(secrets.VSphereSecretType),
+					Data: map[string][]byte{
+						secrets.VSphereEndpoint: []byte("vcenter.example.com"),
+						secrets.VSphereUsername: []byte("user"),
+						secrets.VSpherePassword: []byte("pass"),
+					},
+				},
+			},
+		},
+	}
+	cli := fake.NewSimpleClientset(
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pvc-test-1",
+				Namespace: ns,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeName: "pv-test-1",
+			},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pv-test-1",
+			},
+			Spec: v1.PersistentVolumeSpec{
+				Capacity: v1.ResourceList{
+					v1.ResourceStorage: *k8sresource.NewQuantity(1, k8sresource.BinarySI),
+				},
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					CSI: &v1.CSIPersistentVolumeSource{
+						Driver:       kubevolume.VSphereCSIDriverName,
+						VolumeHandle: "1234-abcd-5678-9213",
+					},
+				},
+			},
+		},
+	)
+	volInfo, err := getPVCInfo(ctx, cli, ns, "pvc-test-1", tp, mockGetter)
+	c.Assert(err, IsNil)
+	c.Assert(volInfo.volumeID, Equals, "1234-abcd-5678-9213")
+	c.Assert(volInfo.sType, Equals, blockstorage.TypeFCD)
+	c.Assert(volInfo.pvc, Equals, "pvc-test-1")
+	c.Assert(volInfo.size, Equals, int64(1))
+	c.Assert(volInfo.fsType, Equals, "")
+
+	// The filesystem is taken from the StorageClass if the PV doesn't set it
+	pv, err := cli.CoreV1().PersistentVolumes().Get("pv-test-1", metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	pv.Spec.StorageClassName = "vsphere-xfs"
+	_, err = cli.CoreV1().PersistentVolumes().Update(pv)
+	c.Assert(err, IsNil)
+	_, err = cli.StorageV1().StorageClasses().Create(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "vsphere-xfs"},
+		Provisioner: kubevolume.VSphereCSIDriverName,
+		Parameters:  map[string]string{"csi.storage.k8s.io/fstype": "xfs"},
+	})
+	c.Assert(err, IsNil)
+	volInfo, err = getPVCInfo(ctx, cli, ns, "pvc-test-1", tp, mockGetter)
+	c.Assert(err, IsNil)
+	c.Assert(volInfo.fsType, Equals, "xfs")
+
+	// vSphere credentials are required for FCD volumes
+	tp.Profile.Credential = param.Credential{
+		Type: param.CredentialTypeKeyPair,
+		KeyPair: &param.KeyPair{
+			ID:     "foo",
+			Secret: "bar",
+		},
+	}
+	_, err = getPVCInfo(ctx, cli, ns, "pvc-test-1", tp, mockGetter)
+	c.Assert(err, NotNil)
+}
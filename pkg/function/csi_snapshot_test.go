@@ -0,0 +1,103 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+)
+
+type CSISnapshotTestSuite struct{}
+
+var _ = Suite(&CSISnapshotTestSuite{})
+
+func (s *CSISnapshotTestSuite) TestCSISnapshotLifecycle(c *C) {
+	ctx := context.Background()
+	ns := "ns"
+	cli := fake.NewSimpleClientset(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: ns},
+	})
+	dynCli := dynfake.NewSimpleDynamicClient(runtime.NewScheme())
+	snapshotter := snapshot.NewSnapshotBeta(cli, dynCli)
+
+	out, err := createCSISnapshot(ctx, snapshotter, "snap", ns, "pvc", "csi-class", false)
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, map[string]interface{}{
+		CSISnapshotOutputNameArg:        "snap",
+		CSISnapshotOutputNamespaceArg:   ns,
+		CSISnapshotOutputRestoreSizeArg: "",
+	})
+	_, err = createCSISnapshot(ctx, snapshotter, "snap", ns, "missing-pvc", "csi-class", false)
+	c.Assert(err, NotNil)
+
+	// Mark the VolumeSnapshot ready, as the CSI snapshotter would.
+	gvr := schema.GroupVersionResource{Group: snapshot.GroupName, Version: snapshot.VersionBeta, Resource: "volumesnapshots"}
+	u, err := dynCli.Resource(gvr).Namespace(ns).Get("snap", metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	err = unstructured.SetNestedMap(u.Object, map[string]interface{}{
+		"creationTime": "2019-11-20T10:00:00Z",
+		"readyToUse":   true,
+		"restoreSize":  "1Gi",
+	}, "status")
+	c.Assert(err, IsNil)
+	_, err = dynCli.Resource(gvr).Namespace(ns).Update(u, metav1.UpdateOptions{})
+	c.Assert(err, IsNil)
+
+	out, err = waitForCSISnapshot(ctx, snapshotter, "snap", ns)
+	c.Assert(err, IsNil)
+	c.Assert(out[CSISnapshotOutputRestoreSizeArg], Equals, "1Gi")
+
+	size := 2
+	for _, tc := range []struct {
+		pvc         string
+		restoreSize *int
+		expected    string
+	}{
+		{pvc: "restored", expected: "1Gi"},
+		{pvc: "restored-larger", restoreSize: &size, expected: "2Gi"},
+	} {
+		out, err = restoreCSISnapshot(ctx, cli, snapshotter, "snap", ns, tc.pvc, "csi-sc", tc.restoreSize)
+		c.Assert(err, IsNil)
+		c.Assert(out, DeepEquals, map[string]interface{}{
+			RestoreCSISnapshotOutputPVCNameArg:     tc.pvc,
+			RestoreCSISnapshotOutputNamespaceArg:   ns,
+			RestoreCSISnapshotOutputRestoreSizeArg: tc.expected,
+		})
+		pvc, err := cli.CoreV1().PersistentVolumeClaims(ns).Get(tc.pvc, metav1.GetOptions{})
+		c.Assert(err, IsNil)
+		c.Assert(pvc.Spec.DataSource.Name, Equals, "snap")
+		c.Assert(*pvc.Spec.StorageClassName, Equals, "csi-sc")
+	}
+
+	out, err = deleteCSISnapshot(ctx, snapshotter, "snap", ns)
+	c.Assert(err, IsNil)
+	c.Assert(out[CSISnapshotOutputRestoreSizeArg], Equals, "1Gi")
+	_, err = snapshotter.Get(ctx, "snap", ns)
+	c.Assert(err, NotNil)
+	// Deleting a VolumeSnapshot that does not exist succeeds.
+	out, err = deleteCSISnapshot(ctx, snapshotter, "snap", ns)
+	c.Assert(err, IsNil)
+	c.Assert(out[CSISnapshotOutputNameArg], Equals, "snap")
+}
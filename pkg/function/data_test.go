@@ -209,6 +209,96 @@ func newCheckRepositoryBlueprint() *crv1alpha1.Blueprint {
 	}
 }
 
+func newRepositoryMaintenanceBlueprint() *crv1alpha1.Blueprint {
+	prefix := "{{ .Profile.Location.Bucket }}/{{ .Profile.Location.Prefix }}"
+	key := "{{ .Secrets.backupKey.Data.password | toString }}"
+	return &crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"maintenance": &crv1alpha1.BlueprintAction{
+				Kind: param.StatefulSetKind,
+				Phases: []crv1alpha1.BlueprintPhase{
+					crv1alpha1.BlueprintPhase{
+						Name: "testUnlockRepository",
+						Func: UnlockRepositoryFuncName,
+						Args: map[string]interface{}{
+							UnlockRepositoryArtifactPrefixArg: prefix,
+							UnlockRepositoryEncryptionKeyArg:  key,
+						},
+					},
+					crv1alpha1.BlueprintPhase{
+						Name: "testVerifyRepository",
+						Func: VerifyRepositoryFuncName,
+						Args: map[string]interface{}{
+							VerifyRepositoryArtifactPrefixArg: prefix,
+							VerifyRepositoryEncryptionKeyArg:  key,
+							VerifyRepositoryReadDataSubsetArg: "1/2",
+						},
+					},
+					crv1alpha1.BlueprintPhase{
+						Name: "testRepositoryStats",
+						Func: RepositoryStatsFuncName,
+						Args: map[string]interface{}{
+							RepositoryStatsArtifactPrefixArg: prefix,
+							RepositoryStatsEncryptionKeyArg:  key,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newRepositoryKeysBlueprint() *crv1alpha1.Blueprint {
+	prefix := "{{ .Profile.Location.Bucket }}/{{ .Profile.Location.Prefix }}"
+	key := "{{ .Secrets.backupKey.Data.password | toString }}"
+	newKey := "{{ .Secrets.newKey.Data.password | toString }}"
+	return &crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"addKey": &crv1alpha1.BlueprintAction{
+				Kind: param.StatefulSetKind,
+				Phases: []crv1alpha1.BlueprintPhase{
+					crv1alpha1.BlueprintPhase{
+						Name: "testAddRepositoryKey",
+						Func: AddRepositoryKeyFuncName,
+						Args: map[string]interface{}{
+							AddRepositoryKeyArtifactPrefixArg: prefix,
+							AddRepositoryKeyEncryptionKeyArg:  key,
+							AddRepositoryKeyNewKeySecretArg:   "newKey",
+							AddRepositoryKeyNewKeyFieldArg:    "password",
+						},
+					},
+				},
+			},
+			"listKeys": &crv1alpha1.BlueprintAction{
+				Kind: param.StatefulSetKind,
+				Phases: []crv1alpha1.BlueprintPhase{
+					crv1alpha1.BlueprintPhase{
+						Name: "testListRepositoryKeys",
+						Func: ListRepositoryKeysFuncName,
+						Args: map[string]interface{}{
+							ListRepositoryKeysArtifactPrefixArg: prefix,
+							ListRepositoryKeysEncryptionKeyArg:  newKey,
+						},
+					},
+				},
+			},
+			"removeKey": &crv1alpha1.BlueprintAction{
+				Kind: param.StatefulSetKind,
+				Phases: []crv1alpha1.BlueprintPhase{
+					crv1alpha1.BlueprintPhase{
+						Name: "testRemoveRepositoryKey",
+						Func: RemoveRepositoryKeyFuncName,
+						Args: map[string]interface{}{
+							RemoveRepositoryKeyArtifactPrefixArg: prefix,
+							RemoveRepositoryKeyEncryptionKeyArg:  newKey,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func newLocationDeleteBlueprint() *crv1alpha1.Blueprint {
 	return &crv1alpha1.Blueprint{
 		Actions: map[string]*crv1alpha1.BlueprintAction{
@@ -662,6 +752,59 @@ func (s *DataSuite) TestCheckRepository(c *C) {
 	c.Assert(out2[FunctionOutputVersion].(string), Equals, kanister.DefaultVersion)
 }
 
+func (s *DataSuite) TestRepositoryMaintenance(c *C) {
+	tp, _ := s.getTemplateParamsAndPVCName(c, 1)
+
+	// Test backup
+	bp := *newBackupDataBlueprint()
+	out := runAction(c, bp, "backup", tp)
+	c.Assert(out[BackupDataOutputBackupID].(string), Not(Equals), "")
+
+	// Test UnlockRepository, VerifyRepository and RepositoryStats
+	bp2 := *newRepositoryMaintenanceBlueprint()
+	out2 := runAction(c, bp2, "maintenance", tp)
+	c.Assert(out2[UnlockRepositoryOutputLocksRemaining].(string), Equals, "0")
+	c.Assert(out2[VerifyRepositoryOutputErrorsFound].(string), Equals, "false")
+	c.Assert(out2[RepositoryStatsOutputSnapshotCount].(string), Not(Equals), "0")
+	c.Assert(out2[RepositoryStatsOutputSizeBytes].(string), Not(Equals), "0")
+	c.Assert(out2[FunctionOutputVersion].(string), Equals, kanister.DefaultVersion)
+}
+
+func (s *DataSuite) TestRepositoryKeys(c *C) {
+	tp, _ := s.getTemplateParamsAndPVCName(c, 1)
+
+	// Test backup
+	bp := *newBackupDataBlueprint()
+	out := runAction(c, bp, "backup", tp)
+	c.Assert(out[BackupDataOutputBackupID].(string), Not(Equals), "")
+
+	tp.Secrets["newKey"] = v1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("myNewPassword"),
+		},
+	}
+
+	// Test AddRepositoryKey
+	bp2 := *newRepositoryKeysBlueprint()
+	out2 := runAction(c, bp2, "addKey", tp)
+	keyID := out2[AddRepositoryKeyOutputKeyID].(string)
+	previousKeyID := out2[AddRepositoryKeyOutputPreviousKeyID].(string)
+	c.Assert(keyID, Not(Equals), "")
+	c.Assert(previousKeyID, Not(Equals), "")
+	c.Assert(keyID, Not(Equals), previousKeyID)
+
+	// Test ListRepositoryKeys with the new key
+	out3 := runAction(c, bp2, "listKeys", tp)
+	c.Assert(out3[ListRepositoryKeysOutputCurrentKeyID].(string), Equals, keyID)
+	c.Assert(out3[ListRepositoryKeysOutputKeyCount].(string), Equals, "2")
+
+	// Test RemoveRepositoryKey of the previous key
+	bp2.Actions["removeKey"].Phases[0].Args[RemoveRepositoryKeyKeyIDArg] = previousKeyID
+	out4 := runAction(c, bp2, "removeKey", tp)
+	c.Assert(out4[RemoveRepositoryKeyOutputKeyCount].(string), Equals, "1")
+	c.Assert(out4[FunctionOutputVersion].(string), Equals, kanister.DefaultVersion)
+}
+
 func (s *DataSuite) TestCheckRepositoryWrongPassword(c *C) {
 	tp, _ := s.getTemplateParamsAndPVCName(c, 1)
 
@@ -0,0 +1,98 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+func init() {
+	_ = kanister.Register(&deleteCSISnapshotFunc{})
+}
+
+var (
+	_ kanister.Func = (*deleteCSISnapshotFunc)(nil)
+)
+
+const (
+	// DeleteCSISnapshotFuncName gives the name of the function
+	DeleteCSISnapshotFuncName = "DeleteCSISnapshot"
+	// DeleteCSISnapshotNameArg provides the name of the VolumeSnapshot
+	DeleteCSISnapshotNameArg = "name"
+	// DeleteCSISnapshotNamespaceArg mentions the namespace of the VolumeSnapshot
+	DeleteCSISnapshotNamespaceArg = "namespace"
+)
+
+type deleteCSISnapshotFunc struct{}
+
+func (*deleteCSISnapshotFunc) Name() string {
+	return DeleteCSISnapshotFuncName
+}
+
+func (*deleteCSISnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var name, namespace string
+	if err := Arg(args, DeleteCSISnapshotNameArg, &name); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, DeleteCSISnapshotNamespaceArg, &namespace); err != nil {
+		return nil, err
+	}
+	_, snapshotter, err := newSnapshotter()
+	if err != nil {
+		return nil, err
+	}
+	return deleteCSISnapshot(ctx, snapshotter, name, namespace)
+}
+
+func (*deleteCSISnapshotFunc) RequiredArgs() []string {
+	return []string{
+		DeleteCSISnapshotNameArg,
+		DeleteCSISnapshotNamespaceArg,
+	}
+}
+
+func (*deleteCSISnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		DeleteCSISnapshotNameArg:      kanister.ArgTypeString,
+		DeleteCSISnapshotNamespaceArg: kanister.ArgTypeString,
+	}
+}
+
+func (*deleteCSISnapshotFunc) Outputs() []string {
+	return csiSnapshotOutputs()
+}
+
+// deleteCSISnapshot deletes the VolumeSnapshot. Deleting a VolumeSnapshot that
+// does not exist is not an error, so the restore size is empty in that case.
+func deleteCSISnapshot(ctx context.Context, snapshotter snapshot.Snapshotter, name, namespace string) (map[string]interface{}, error) {
+	out := map[string]interface{}{
+		CSISnapshotOutputNameArg:        name,
+		CSISnapshotOutputNamespaceArg:   namespace,
+		CSISnapshotOutputRestoreSizeArg: "",
+	}
+	if snap, err := snapshotter.Get(ctx, name, namespace); err == nil && snap.Status.RestoreSize != nil {
+		out[CSISnapshotOutputRestoreSizeArg] = snap.Status.RestoreSize.String()
+	}
+	if err := snapshotter.Delete(ctx, name, namespace); err != nil {
+		return nil, errors.Wrapf(err, "Failed to delete VolumeSnapshot %s, Namespace %s", name, namespace)
+	}
+	return out, nil
+}
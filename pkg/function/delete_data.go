@@ -92,8 +92,13 @@ func deleteDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, reclai
 			return nil, err
 		}
 		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
 		for i, deleteTag := range deleteTags {
-			cmd, err := restic.SnapshotsCommandByTag(tp.Profile, targetPaths[i], deleteTag, encryptionKey)
+			cmd, err := restic.SnapshotsCommandByTag(tp.Profile, targetPaths[i], deleteTag, passwordFile)
 			if err != nil {
 				return nil, err
 			}
@@ -111,7 +116,7 @@ func deleteDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, reclai
 		}
 		var spaceFreedTotal int64
 		for i, deleteIdentifier := range deleteIdentifiers {
-			cmd, err := restic.ForgetCommandByID(tp.Profile, targetPaths[i], deleteIdentifier, encryptionKey)
+			cmd, err := restic.ForgetCommandByID(tp.Profile, targetPaths[i], deleteIdentifier, passwordFile)
 			if err != nil {
 				return nil, err
 			}
@@ -122,7 +127,7 @@ func deleteDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, reclai
 				return nil, errors.Wrapf(err, "Failed to forget data")
 			}
 			if reclaimSpace {
-				spaceFreedStr, err := pruneData(cli, tp, pod, namespace, encryptionKey, targetPaths[i])
+				spaceFreedStr, err := pruneData(cli, tp, pod, namespace, passwordFile, targetPaths[i])
 				if err != nil {
 					return nil, errors.Wrapf(err, "Error executing prune command")
 				}
@@ -136,8 +141,8 @@ func deleteDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, reclai
 	}
 }
 
-func pruneData(cli kubernetes.Interface, tp param.TemplateParams, pod *v1.Pod, namespace, encryptionKey, targetPath string) (string, error) {
-	cmd, err := restic.PruneCommand(tp.Profile, targetPath, encryptionKey)
+func pruneData(cli kubernetes.Interface, tp param.TemplateParams, pod *v1.Pod, namespace, passwordFile, targetPath string) (string, error) {
+	cmd, err := restic.PruneCommand(tp.Profile, targetPath, passwordFile)
 	if err != nil {
 		return "", err
 	}
@@ -164,7 +169,7 @@ func (*deleteDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args m
 	if err = OptArg(args, DeleteDataBackupTagArg, &deleteTag, ""); err != nil {
 		return nil, err
 	}
-	if err = OptArg(args, DeleteDataEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, DeleteDataEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
 		return nil, err
 	}
 	if err = OptArg(args, DeleteDataReclaimSpace, &reclaimSpace, false); err != nil {
@@ -191,3 +196,21 @@ func (*deleteDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args m
 func (*deleteDataFunc) RequiredArgs() []string {
 	return []string{DeleteDataNamespaceArg, DeleteDataBackupArtifactPrefixArg}
 }
+
+func (*deleteDataFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		DeleteDataNamespaceArg:            kanister.ArgTypeString,
+		DeleteDataBackupArtifactPrefixArg: kanister.ArgTypeString,
+		DeleteDataBackupIdentifierArg:     kanister.ArgTypeString,
+		DeleteDataBackupTagArg:            kanister.ArgTypeString,
+		DeleteDataEncryptionKeyArg:        kanister.ArgTypeString,
+		DeleteDataReclaimSpace:            kanister.ArgTypeBool,
+		DeleteDataPodOverrideArg:          kanister.ArgTypeMap,
+	}
+}
+
+func (*deleteDataFunc) Outputs() []string {
+	return []string{
+		DeleteDataOutputSpaceFreed,
+	}
+}
@@ -70,7 +70,7 @@ func (*deleteDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, arg
 	if err = Arg(args, DeleteDataAllBackupInfo, &backupInfo); err != nil {
 		return nil, err
 	}
-	if err = OptArg(args, DeleteDataAllEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, DeleteDataAllEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
 		return nil, err
 	}
 	if err = OptArg(args, DeleteDataAllReclaimSpace, &reclaimSpace, false); err != nil {
@@ -106,3 +106,20 @@ func (*deleteDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, arg
 func (*deleteDataAllFunc) RequiredArgs() []string {
 	return []string{DeleteDataAllNamespaceArg, DeleteDataAllBackupArtifactPrefixArg, DeleteDataAllBackupInfo}
 }
+
+func (*deleteDataAllFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		DeleteDataAllNamespaceArg:            kanister.ArgTypeString,
+		DeleteDataAllBackupArtifactPrefixArg: kanister.ArgTypeString,
+		DeleteDataAllBackupInfo:              kanister.ArgTypeString,
+		DeleteDataAllEncryptionKeyArg:        kanister.ArgTypeString,
+		DeleteDataAllReclaimSpace:            kanister.ArgTypeBool,
+		DeleteDataAllPodOverrideArg:          kanister.ArgTypeMap,
+	}
+}
+
+func (*deleteDataAllFunc) Outputs() []string {
+	return []string{
+		DeleteDataOutputSpaceFreed,
+	}
+}
@@ -88,3 +88,13 @@ func (crs *deleteRDSSnapshotFunc) Exec(ctx context.Context, tp param.TemplatePar
 func (*deleteRDSSnapshotFunc) RequiredArgs() []string {
 	return []string{DeleteRDSSnapshotSnapshotIDArg}
 }
+
+func (*deleteRDSSnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		DeleteRDSSnapshotSnapshotIDArg: kanister.ArgTypeString,
+	}
+}
+
+func (*deleteRDSSnapshotFunc) Outputs() []string {
+	return []string{}
+}
@@ -111,3 +111,14 @@ func (kef *deleteVolumeSnapshotFunc) Exec(ctx context.Context, tp param.Template
 func (*deleteVolumeSnapshotFunc) RequiredArgs() []string {
 	return []string{DeleteVolumeSnapshotNamespaceArg, DeleteVolumeSnapshotManifestArg}
 }
+
+func (*deleteVolumeSnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		DeleteVolumeSnapshotNamespaceArg: kanister.ArgTypeString,
+		DeleteVolumeSnapshotManifestArg:  kanister.ArgTypeString,
+	}
+}
+
+func (*deleteVolumeSnapshotFunc) Outputs() []string {
+	return []string{}
+}
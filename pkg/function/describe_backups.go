@@ -37,6 +37,10 @@ const (
 	DescribeBackupsArtifactPrefixArg = "backupArtifactPrefix"
 	// DescribeBackupsEncryptionKeyArg provides the encryption key to be used for deletes
 	DescribeBackupsEncryptionKeyArg = "encryptionKey"
+	// DescribeBackupsTagsArg limits the description to snapshots with all of these tags
+	DescribeBackupsTagsArg = "tags"
+	// DescribeBackupsHostnameArg limits the description to snapshots taken with this hostname
+	DescribeBackupsHostnameArg = "hostname"
 	// DescribeBackupsPodOverrideArg contains pod specs to override default pod specs
 	DescribeBackupsPodOverrideArg    = "podOverride"
 	DescribeBackupsJobPrefix         = "describe-backups-"
@@ -59,7 +63,7 @@ func (*DescribeBackupsFunc) Name() string {
 	return DescribeBackupsFuncName
 }
 
-func describeBackups(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, targetPaths, jobPrefix string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+func describeBackups(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, targetPaths, jobPrefix string, filter restic.SnapshotFilter, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
 	namespace, err := kube.GetControllerNamespace()
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to get controller namespace")
@@ -72,11 +76,11 @@ func describeBackups(ctx context.Context, cli kubernetes.Interface, tp param.Tem
 		PodOverride:  podOverride,
 	}
 	pr := kube.NewPodRunner(cli, options)
-	podFunc := describeBackupsPodFunc(cli, tp, namespace, encryptionKey, targetPaths)
+	podFunc := describeBackupsPodFunc(cli, tp, namespace, encryptionKey, targetPaths, filter)
 	return pr.Run(ctx, podFunc)
 }
 
-func describeBackupsPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, targetPath string) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+func describeBackupsPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, targetPath string, filter restic.SnapshotFilter) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
 	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
 		// Wait for pod to reach running state
 		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
@@ -87,7 +91,12 @@ func describeBackupsPodFunc(cli kubernetes.Interface, tp param.TemplateParams, n
 			return nil, err
 		}
 		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
-		err = restic.CheckIfRepoIsReachable(tp.Profile, targetPath, encryptionKey, cli, namespace, pod.Name, pod.Spec.Containers[0].Name)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		err = restic.CheckIfRepoIsReachable(tp.Profile, targetPath, passwordFile, cli, namespace, pod.Name, pod.Spec.Containers[0].Name)
 		switch {
 		case err == nil:
 			break
@@ -114,7 +123,25 @@ func describeBackupsPodFunc(cli kubernetes.Interface, tp param.TemplateParams, n
 			return nil, err
 
 		}
-		cmd, err := restic.StatsCommandByID(tp.Profile, targetPath, "" /* get all snapshot stats */, RawDataStatsMode, encryptionKey)
+		var ids []string
+		if len(filter.Tags) != 0 || filter.Hostname != "" {
+			// restic stats can't filter by tag or host itself, so the
+			// matching snapshots are listed first.
+			if ids, err = filteredSnapshotIDs(cli, tp, namespace, pod, passwordFile, targetPath, filter); err != nil {
+				return nil, err
+			}
+			if len(ids) == 0 {
+				return map[string]interface{}{
+						DescribeBackupsFileCount:         "0",
+						DescribeBackupsSize:              "0 B",
+						DescribeBackupsPasswordIncorrect: "false",
+						DescribeBackupsRepoDoesNotExist:  "false",
+						FunctionOutputVersion:            kanister.DefaultVersion,
+					},
+					nil
+			}
+		}
+		cmd, err := restic.StatsCommandByIDs(tp.Profile, targetPath, ids, RawDataStatsMode, passwordFile)
 		if err != nil {
 			return nil, err
 		}
@@ -146,7 +173,14 @@ func (*DescribeBackupsFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err = Arg(args, DescribeBackupsArtifactPrefixArg, &describeBackupsArtifactPrefix); err != nil {
 		return nil, err
 	}
-	if err = OptArg(args, DescribeBackupsEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, DescribeBackupsEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	var filter restic.SnapshotFilter
+	if err = OptArg(args, DescribeBackupsTagsArg, &filter.Tags, nil); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, DescribeBackupsHostnameArg, &filter.Hostname, ""); err != nil {
 		return nil, err
 	}
 	podOverride, err := GetPodSpecOverride(tp, args, DescribeBackupsPodOverrideArg)
@@ -164,9 +198,47 @@ func (*DescribeBackupsFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
 	}
-	return describeBackups(ctx, cli, tp, encryptionKey, describeBackupsArtifactPrefix, DescribeBackupsJobPrefix, podOverride)
+	return describeBackups(ctx, cli, tp, encryptionKey, describeBackupsArtifactPrefix, DescribeBackupsJobPrefix, filter, podOverride)
 }
 
 func (*DescribeBackupsFunc) RequiredArgs() []string {
 	return []string{DescribeBackupsArtifactPrefixArg}
 }
+
+func (*DescribeBackupsFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		DescribeBackupsArtifactPrefixArg: kanister.ArgTypeString,
+		DescribeBackupsEncryptionKeyArg:  kanister.ArgTypeString,
+		DescribeBackupsPodOverrideArg:    kanister.ArgTypeMap,
+		DescribeBackupsTagsArg:           kanister.ArgTypeStringSlice,
+		DescribeBackupsHostnameArg:       kanister.ArgTypeString,
+	}
+}
+
+func (*DescribeBackupsFunc) Outputs() []string {
+	return []string{
+		DescribeBackupsFileCount,
+		DescribeBackupsSize,
+		DescribeBackupsPasswordIncorrect,
+		DescribeBackupsRepoDoesNotExist,
+		FunctionOutputVersion,
+	}
+}
+
+// filteredSnapshotIDs returns the IDs of the snapshots that match filter.
+func filteredSnapshotIDs(cli kubernetes.Interface, tp param.TemplateParams, namespace string, pod *v1.Pod, passwordFile, targetPath string, filter restic.SnapshotFilter) ([]string, error) {
+	cmd, err := restic.SnapshotsCommandByFilter(tp.Profile, targetPath, passwordFile, filter)
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr, err := kube.Exec(cli, namespace, pod.Name, pod.Spec.Containers[0].Name, cmd, nil)
+	format.Log(pod.Name, pod.Spec.Containers[0].Name, stderr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list snapshots")
+	}
+	count, err := restic.SnapshotCountFromSnapshotCommand(stdout)
+	if err != nil || count == 0 {
+		return nil, err
+	}
+	return restic.SnapshotIDsFromSnapshotCommand(stdout)
+}
@@ -168,6 +168,18 @@ func (*exportRDSSnapshotToLocationFunc) RequiredArgs() []string {
 	return []string{ExportRDSSnapshotToLocNamespaceArg, ExportRDSSnapshotToLocInstanceIDArg, ExportRDSSnapshotToLocSnapshotIDArg, ExportRDSSnapshotToLocDBEngineArg}
 }
 
+func (*exportRDSSnapshotToLocationFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		ExportRDSSnapshotToLocNamespaceArg:       kanister.ArgTypeString,
+		ExportRDSSnapshotToLocInstanceIDArg:      kanister.ArgTypeString,
+		ExportRDSSnapshotToLocSnapshotIDArg:      kanister.ArgTypeString,
+		ExportRDSSnapshotToLocDBEngineArg:        kanister.ArgTypeString,
+		ExportRDSSnapshotToLocDBUsernameArg:      kanister.ArgTypeString,
+		ExportRDSSnapshotToLocDBPasswordArg:      kanister.ArgTypeString,
+		ExportRDSSnapshotToLocBackupArtPrefixArg: kanister.ArgTypeString,
+	}
+}
+
 func extractAndPushDump(ctx context.Context, dbEngine RDSDBEngine, namespace, instanceID, dbEndpoint, username, password, backupPrefix string, profile *param.Profile) (map[string]interface{}, error) {
 	// Create unique backupID
 	randomID, err := shortid.Generate()
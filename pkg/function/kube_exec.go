@@ -109,3 +109,12 @@ func (kef *kubeExecFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 func (*kubeExecFunc) RequiredArgs() []string {
 	return []string{KubeExecNamespaceArg, KubeExecPodNameArg, KubeExecCommandArg}
 }
+
+func (*kubeExecFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		KubeExecNamespaceArg:     kanister.ArgTypeString,
+		KubeExecPodNameArg:       kanister.ArgTypeString,
+		KubeExecContainerNameArg: kanister.ArgTypeString,
+		KubeExecCommandArg:       kanister.ArgTypeStringSlice,
+	}
+}
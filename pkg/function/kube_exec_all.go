@@ -80,6 +80,15 @@ func (*kubeExecAllFunc) RequiredArgs() []string {
 	return []string{KubeExecAllNamespaceArg, KubeExecAllPodsNameArg, KubeExecAllContainersNameArg, KubeExecAllCommandArg}
 }
 
+func (*kubeExecAllFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		KubeExecAllNamespaceArg:      kanister.ArgTypeString,
+		KubeExecAllPodsNameArg:       kanister.ArgTypeString,
+		KubeExecAllContainersNameArg: kanister.ArgTypeString,
+		KubeExecAllCommandArg:        kanister.ArgTypeStringSlice,
+	}
+}
+
 func execAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps []string, cs []string, cmd []string) (map[string]interface{}, error) {
 	numContainers := len(ps) * len(cs)
 	errChan := make(chan error, numContainers)
@@ -130,3 +130,12 @@ func (ktf *kubeTaskFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 func (*kubeTaskFunc) RequiredArgs() []string {
 	return []string{KubeTaskImageArg, KubeTaskCommandArg}
 }
+
+func (*kubeTaskFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		KubeTaskNamespaceArg:   kanister.ArgTypeString,
+		KubeTaskImageArg:       kanister.ArgTypeString,
+		KubeTaskCommandArg:     kanister.ArgTypeStringSlice,
+		KubeTaskPodOverrideArg: kanister.ArgTypeMap,
+	}
+}
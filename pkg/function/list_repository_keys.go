@@ -0,0 +1,194 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	// ListRepositoryKeysFuncName gives the name of the function
+	ListRepositoryKeysFuncName = "ListRepositoryKeys"
+	// ListRepositoryKeysArtifactPrefixArg provides the path of the repository
+	ListRepositoryKeysArtifactPrefixArg = "backupArtifactPrefix"
+	// ListRepositoryKeysEncryptionKeyArg provides the encryption key of the repository
+	ListRepositoryKeysEncryptionKeyArg = "encryptionKey"
+	// ListRepositoryKeysPodOverrideArg contains pod specs to override default pod specs
+	ListRepositoryKeysPodOverrideArg = "podOverride"
+	// ListRepositoryKeysOutputKeys is a JSON list of the keys of the repository
+	ListRepositoryKeysOutputKeys = "keys"
+	// ListRepositoryKeysOutputCurrentKeyID is the ID of the key that opened the repository
+	ListRepositoryKeysOutputCurrentKeyID = "currentKeyID"
+	// ListRepositoryKeysOutputKeyCount is the number of keys of the repository
+	ListRepositoryKeysOutputKeyCount = "keyCount"
+	listRepositoryKeysJobPrefix      = "list-repository-keys-"
+)
+
+func init() {
+	_ = kanister.Register(&listRepositoryKeysFunc{})
+}
+
+var _ kanister.Func = (*listRepositoryKeysFunc)(nil)
+
+type listRepositoryKeysFunc struct{}
+
+func (*listRepositoryKeysFunc) Name() string {
+	return ListRepositoryKeysFuncName
+}
+
+func listRepositoryKeys(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, targetPath, jobPrefix string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+	namespace, err := kube.GetControllerNamespace()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get controller namespace")
+	}
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: jobPrefix,
+		Image:        kanisterToolsImage,
+		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:  podOverride,
+	}
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := listRepositoryKeysPodFunc(cli, tp, namespace, encryptionKey, targetPath)
+	return pr.Run(ctx, podFunc)
+}
+
+func listRepositoryKeysPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, targetPath string) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+		// Wait for pod to reach running state
+		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pod.Name)
+		}
+		container := pod.Spec.Containers[0].Name
+		pw, err := GetPodWriter(cli, ctx, pod.Namespace, pod.Name, container, tp.Profile)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, container)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, container, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, container)
+		keys, err := keyList(cli, tp, namespace, pod.Name, container, passwordFile, targetPath)
+		if err != nil {
+			return nil, err
+		}
+		keysJSON, err := json.Marshal(keys)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to encode JSON data")
+		}
+		return map[string]interface{}{
+			ListRepositoryKeysOutputKeys:         string(keysJSON),
+			ListRepositoryKeysOutputCurrentKeyID: currentKeyID(keys),
+			ListRepositoryKeysOutputKeyCount:     strconv.Itoa(len(keys)),
+			FunctionOutputVersion:                kanister.DefaultVersion,
+		}, nil
+	}
+}
+
+// keyList returns the keys of the repository at targetPath by running restic
+// in the given container
+func keyList(cli kubernetes.Interface, tp param.TemplateParams, namespace, pod, container, passwordFile, targetPath string) ([]restic.Key, error) {
+	cmd, err := restic.KeyListCommand(tp.Profile, targetPath, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	return execKeyList(cli, namespace, pod, container, cmd)
+}
+
+// execKeyList runs the restic key list command cmd and parses its output
+func execKeyList(cli kubernetes.Interface, namespace, pod, container string, cmd []string) ([]restic.Key, error) {
+	stdout, stderr, err := kube.Exec(cli, namespace, pod, container, cmd, nil)
+	format.Log(pod, container, stdout)
+	format.Log(pod, container, stderr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list repository keys")
+	}
+	keys := restic.KeysFromKeyListLog(stdout)
+	if len(keys) == 0 {
+		return nil, errors.New("Failed to parse repository keys from logs")
+	}
+	return keys, nil
+}
+
+func currentKeyID(keys []restic.Key) string {
+	for _, k := range keys {
+		if k.Current {
+			return k.ID
+		}
+	}
+	return ""
+}
+
+func (*listRepositoryKeysFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var artifactPrefix, encryptionKey string
+	if err := Arg(args, ListRepositoryKeysArtifactPrefixArg, &artifactPrefix); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, ListRepositoryKeysEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, ListRepositoryKeysPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateProfile(tp.Profile); err != nil {
+		return nil, err
+	}
+
+	artifactPrefix = ResolveArtifactPrefix(artifactPrefix, tp.Profile)
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	return listRepositoryKeys(ctx, cli, tp, encryptionKey, artifactPrefix, listRepositoryKeysJobPrefix, podOverride)
+}
+
+func (*listRepositoryKeysFunc) RequiredArgs() []string {
+	return []string{ListRepositoryKeysArtifactPrefixArg}
+}
+
+func (*listRepositoryKeysFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		ListRepositoryKeysArtifactPrefixArg: kanister.ArgTypeString,
+		ListRepositoryKeysEncryptionKeyArg:  kanister.ArgTypeString,
+		ListRepositoryKeysPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*listRepositoryKeysFunc) Outputs() []string {
+	return []string{
+		ListRepositoryKeysOutputKeys,
+		ListRepositoryKeysOutputCurrentKeyID,
+		ListRepositoryKeysOutputKeyCount,
+		FunctionOutputVersion,
+	}
+}
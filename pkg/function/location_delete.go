@@ -59,3 +59,13 @@ func (*locationDeleteFunc) Exec(ctx context.Context, tp param.TemplateParams, ar
 func (*locationDeleteFunc) RequiredArgs() []string {
 	return []string{LocationDeleteArtifactArg}
 }
+
+func (*locationDeleteFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		LocationDeleteArtifactArg: kanister.ArgTypeString,
+	}
+}
+
+func (*locationDeleteFunc) Outputs() []string {
+	return []string{}
+}
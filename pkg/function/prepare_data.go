@@ -155,3 +155,14 @@ func (*prepareDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 func (*prepareDataFunc) RequiredArgs() []string {
 	return []string{PrepareDataNamespaceArg, PrepareDataImageArg, PrepareDataCommandArg}
 }
+
+func (*prepareDataFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		PrepareDataNamespaceArg:   kanister.ArgTypeString,
+		PrepareDataImageArg:       kanister.ArgTypeString,
+		PrepareDataCommandArg:     kanister.ArgTypeStringSlice,
+		PrepareDataVolumes:        kanister.ArgTypeMap,
+		PrepareDataServiceAccount: kanister.ArgTypeString,
+		PrepareDataPodOverrideArg: kanister.ArgTypeMap,
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	// RemoveRepositoryKeyFuncName gives the name of the function
+	RemoveRepositoryKeyFuncName = "RemoveRepositoryKey"
+	// RemoveRepositoryKeyArtifactPrefixArg provides the path of the repository
+	RemoveRepositoryKeyArtifactPrefixArg = "backupArtifactPrefix"
+	// RemoveRepositoryKeyEncryptionKeyArg provides an encryption key of the repository other than the one removed
+	RemoveRepositoryKeyEncryptionKeyArg = "encryptionKey"
+	// RemoveRepositoryKeyKeyIDArg provides the ID of the key to remove
+	RemoveRepositoryKeyKeyIDArg = "keyID"
+	// RemoveRepositoryKeyPodOverrideArg contains pod specs to override default pod specs
+	RemoveRepositoryKeyPodOverrideArg = "podOverride"
+	// RemoveRepositoryKeyOutputKeyCount is the number of keys left in the repository
+	RemoveRepositoryKeyOutputKeyCount = "keyCount"
+	removeRepositoryKeyJobPrefix      = "remove-repository-key-"
+)
+
+func init() {
+	_ = kanister.Register(&removeRepositoryKeyFunc{})
+}
+
+var _ kanister.Func = (*removeRepositoryKeyFunc)(nil)
+
+type removeRepositoryKeyFunc struct{}
+
+func (*removeRepositoryKeyFunc) Name() string {
+	return RemoveRepositoryKeyFuncName
+}
+
+func removeRepositoryKey(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, keyID, targetPath, jobPrefix string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+	namespace, err := kube.GetControllerNamespace()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get controller namespace")
+	}
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: jobPrefix,
+		Image:        kanisterToolsImage,
+		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:  podOverride,
+	}
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := removeRepositoryKeyPodFunc(cli, tp, namespace, encryptionKey, keyID, targetPath)
+	return pr.Run(ctx, podFunc)
+}
+
+func removeRepositoryKeyPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, keyID, targetPath string) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+		// Wait for pod to reach running state
+		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pod.Name)
+		}
+		container := pod.Spec.Containers[0].Name
+		pw, err := GetPodWriter(cli, ctx, pod.Namespace, pod.Name, container, tp.Profile)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, container)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, container, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, container)
+		cmd, err := restic.KeyRemoveCommand(tp.Profile, targetPath, passwordFile, keyID)
+		if err != nil {
+			return nil, err
+		}
+		stdout, stderr, err := kube.Exec(cli, namespace, pod.Name, container, cmd, nil)
+		format.Log(pod.Name, container, stdout)
+		format.Log(pod.Name, container, stderr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to remove repository key %s", keyID)
+		}
+		keys, err := keyList(cli, tp, namespace, pod.Name, container, passwordFile, targetPath)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			RemoveRepositoryKeyOutputKeyCount: strconv.Itoa(len(keys)),
+			FunctionOutputVersion:             kanister.DefaultVersion,
+		}, nil
+	}
+}
+
+func (*removeRepositoryKeyFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var artifactPrefix, encryptionKey, keyID string
+	if err := Arg(args, RemoveRepositoryKeyArtifactPrefixArg, &artifactPrefix); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, RemoveRepositoryKeyEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, RemoveRepositoryKeyKeyIDArg, &keyID); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, RemoveRepositoryKeyPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateProfile(tp.Profile); err != nil {
+		return nil, err
+	}
+
+	artifactPrefix = ResolveArtifactPrefix(artifactPrefix, tp.Profile)
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	return removeRepositoryKey(ctx, cli, tp, encryptionKey, keyID, artifactPrefix, removeRepositoryKeyJobPrefix, podOverride)
+}
+
+func (*removeRepositoryKeyFunc) RequiredArgs() []string {
+	return []string{RemoveRepositoryKeyArtifactPrefixArg, RemoveRepositoryKeyKeyIDArg}
+}
+
+func (*removeRepositoryKeyFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		RemoveRepositoryKeyArtifactPrefixArg: kanister.ArgTypeString,
+		RemoveRepositoryKeyEncryptionKeyArg:  kanister.ArgTypeString,
+		RemoveRepositoryKeyKeyIDArg:          kanister.ArgTypeString,
+		RemoveRepositoryKeyPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*removeRepositoryKeyFunc) Outputs() []string {
+	return []string{
+		RemoveRepositoryKeyOutputKeyCount,
+		FunctionOutputVersion,
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	// RepositoryStatsFuncName gives the name of the function
+	RepositoryStatsFuncName = "RepositoryStats"
+	// RepositoryStatsArtifactPrefixArg provides the path of the repository
+	RepositoryStatsArtifactPrefixArg = "backupArtifactPrefix"
+	// RepositoryStatsEncryptionKeyArg provides the encryption key of the repository
+	RepositoryStatsEncryptionKeyArg = "encryptionKey"
+	// RepositoryStatsModeArg provides the restic stats mode. Defaults to raw-data
+	RepositoryStatsModeArg = "statsMode"
+	// RepositoryStatsPodOverrideArg contains pod specs to override default pod specs
+	RepositoryStatsPodOverrideArg      = "podOverride"
+	RepositoryStatsOutputMode          = "mode"
+	RepositoryStatsOutputFileCount     = "fileCount"
+	RepositoryStatsOutputSize          = "size"
+	RepositoryStatsOutputSizeBytes     = "sizeBytes"
+	RepositoryStatsOutputSnapshotCount = "snapshotCount"
+	repositoryStatsJobPrefix           = "repository-stats-"
+)
+
+func init() {
+	_ = kanister.Register(&repositoryStatsFunc{})
+}
+
+var _ kanister.Func = (*repositoryStatsFunc)(nil)
+
+type repositoryStatsFunc struct{}
+
+func (*repositoryStatsFunc) Name() string {
+	return RepositoryStatsFuncName
+}
+
+func repositoryStats(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, targetPath, mode, jobPrefix string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+	namespace, err := kube.GetControllerNamespace()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get controller namespace")
+	}
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: jobPrefix,
+		Image:        kanisterToolsImage,
+		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:  podOverride,
+	}
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := repositoryStatsPodFunc(cli, tp, namespace, encryptionKey, targetPath, mode)
+	return pr.Run(ctx, podFunc)
+}
+
+func repositoryStatsPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, targetPath, mode string) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+		// Wait for pod to reach running state
+		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pod.Name)
+		}
+		container := pod.Spec.Containers[0].Name
+		pw, err := GetPodWriter(cli, ctx, pod.Namespace, pod.Name, container, tp.Profile)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, container)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, container, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, container)
+		cmd, err := restic.StatsCommandByIDs(tp.Profile, targetPath, nil, mode, passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		stdout, stderr, err := kube.Exec(cli, namespace, pod.Name, container, cmd, nil)
+		format.Log(pod.Name, container, stdout)
+		format.Log(pod.Name, container, stderr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get repository stats")
+		}
+		// Get File Count and Size from Stats
+		statsMode, fc, size := restic.SnapshotStatsFromStatsLog(stdout)
+		if fc == "" || size == "" {
+			return nil, errors.New("Failed to parse repository stats from logs")
+		}
+		cmd, err = restic.SnapshotsCommand(tp.Profile, targetPath, passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		stdout, stderr, err = kube.Exec(cli, namespace, pod.Name, container, cmd, nil)
+		format.Log(pod.Name, container, stderr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list snapshots")
+		}
+		snapshotCount, err := restic.SnapshotCountFromSnapshotCommand(stdout)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			RepositoryStatsOutputMode:          statsMode,
+			RepositoryStatsOutputFileCount:     fc,
+			RepositoryStatsOutputSize:          size,
+			RepositoryStatsOutputSizeBytes:     strconv.FormatInt(restic.ParseResticSizeStringBytes(size), 10),
+			RepositoryStatsOutputSnapshotCount: strconv.Itoa(snapshotCount),
+			FunctionOutputVersion:              kanister.DefaultVersion,
+		}, nil
+	}
+}
+
+func (*repositoryStatsFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var artifactPrefix, encryptionKey, mode string
+	if err := Arg(args, RepositoryStatsArtifactPrefixArg, &artifactPrefix); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, RepositoryStatsEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, RepositoryStatsModeArg, &mode, RawDataStatsMode); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, RepositoryStatsPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateProfile(tp.Profile); err != nil {
+		return nil, err
+	}
+
+	artifactPrefix = ResolveArtifactPrefix(artifactPrefix, tp.Profile)
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	return repositoryStats(ctx, cli, tp, encryptionKey, artifactPrefix, mode, repositoryStatsJobPrefix, podOverride)
+}
+
+func (*repositoryStatsFunc) RequiredArgs() []string {
+	return []string{RepositoryStatsArtifactPrefixArg}
+}
+
+func (*repositoryStatsFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		RepositoryStatsArtifactPrefixArg: kanister.ArgTypeString,
+		RepositoryStatsEncryptionKeyArg:  kanister.ArgTypeString,
+		RepositoryStatsModeArg:           kanister.ArgTypeString,
+		RepositoryStatsPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*repositoryStatsFunc) Outputs() []string {
+	return []string{
+		RepositoryStatsOutputMode,
+		RepositoryStatsOutputFileCount,
+		RepositoryStatsOutputSize,
+		RepositoryStatsOutputSizeBytes,
+		RepositoryStatsOutputSnapshotCount,
+		FunctionOutputVersion,
+	}
+}
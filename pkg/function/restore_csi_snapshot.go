@@ -0,0 +1,137 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+	"github.com/kanisterio/kanister/pkg/kube/volume"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+func init() {
+	_ = kanister.Register(&restoreCSISnapshotFunc{})
+}
+
+var (
+	_ kanister.Func = (*restoreCSISnapshotFunc)(nil)
+)
+
+const (
+	// RestoreCSISnapshotFuncName gives the name of the function
+	RestoreCSISnapshotFuncName = "RestoreCSISnapshot"
+	// RestoreCSISnapshotNameArg provides the name of the VolumeSnapshot
+	RestoreCSISnapshotNameArg = "name"
+	// RestoreCSISnapshotNamespaceArg mentions the namespace of the VolumeSnapshot. The PVC is restored in the same namespace.
+	RestoreCSISnapshotNamespaceArg = "namespace"
+	// RestoreCSISnapshotPVCNameArg provides the name of the PVC to be restored
+	RestoreCSISnapshotPVCNameArg = "pvc"
+	// RestoreCSISnapshotStorageClassArg specifies the name of the StorageClass of the restored PVC
+	RestoreCSISnapshotStorageClassArg = "storageClass"
+	// RestoreCSISnapshotRestoreSizeArg overrides the restore size of the VolumeSnapshot, in GiB
+	RestoreCSISnapshotRestoreSizeArg = "restoreSize"
+
+	// RestoreCSISnapshotOutputPVCNameArg gives the name of the restored PVC
+	RestoreCSISnapshotOutputPVCNameArg = "pvc"
+	// RestoreCSISnapshotOutputNamespaceArg gives the namespace of the restored PVC
+	RestoreCSISnapshotOutputNamespaceArg = "namespace"
+	// RestoreCSISnapshotOutputRestoreSizeArg gives the size requested by the restored PVC
+	RestoreCSISnapshotOutputRestoreSizeArg = "restoreSize"
+)
+
+type restoreCSISnapshotFunc struct{}
+
+func (*restoreCSISnapshotFunc) Name() string {
+	return RestoreCSISnapshotFuncName
+}
+
+func (*restoreCSISnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var name, namespace, pvc, storageClass string
+	var restoreSize int
+	if err := Arg(args, RestoreCSISnapshotNameArg, &name); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, RestoreCSISnapshotNamespaceArg, &namespace); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, RestoreCSISnapshotPVCNameArg, &pvc); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, RestoreCSISnapshotStorageClassArg, &storageClass, ""); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, RestoreCSISnapshotRestoreSizeArg, &restoreSize, 0); err != nil {
+		return nil, err
+	}
+	var size *int
+	if restoreSize > 0 {
+		size = &restoreSize
+	}
+	cli, snapshotter, err := newSnapshotter()
+	if err != nil {
+		return nil, err
+	}
+	return restoreCSISnapshot(ctx, cli, snapshotter, name, namespace, pvc, storageClass, size)
+}
+
+func (*restoreCSISnapshotFunc) RequiredArgs() []string {
+	return []string{
+		RestoreCSISnapshotNameArg,
+		RestoreCSISnapshotNamespaceArg,
+		RestoreCSISnapshotPVCNameArg,
+	}
+}
+
+func (*restoreCSISnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		RestoreCSISnapshotNameArg:         kanister.ArgTypeString,
+		RestoreCSISnapshotNamespaceArg:    kanister.ArgTypeString,
+		RestoreCSISnapshotPVCNameArg:      kanister.ArgTypeString,
+		RestoreCSISnapshotStorageClassArg: kanister.ArgTypeString,
+		RestoreCSISnapshotRestoreSizeArg:  kanister.ArgTypeInt,
+	}
+}
+
+func (*restoreCSISnapshotFunc) Outputs() []string {
+	return []string{
+		RestoreCSISnapshotOutputPVCNameArg,
+		RestoreCSISnapshotOutputNamespaceArg,
+		RestoreCSISnapshotOutputRestoreSizeArg,
+	}
+}
+
+func restoreCSISnapshot(ctx context.Context, cli kubernetes.Interface, snapshotter snapshot.Snapshotter, name, namespace, pvc, storageClass string, restoreSize *int) (map[string]interface{}, error) {
+	pvcName, err := volume.CreatePVCFromSnapshot(ctx, cli, snapshotter, namespace, pvc, storageClass, name, restoreSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to restore PVC %s from VolumeSnapshot %s, Namespace %s", pvc, name, namespace)
+	}
+	claim, err := cli.CoreV1().PersistentVolumeClaims(namespace).Get(pvcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get PVC %s, Namespace %s", pvcName, namespace)
+	}
+	size := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	return map[string]interface{}{
+		RestoreCSISnapshotOutputPVCNameArg:     pvcName,
+		RestoreCSISnapshotOutputNamespaceArg:   namespace,
+		RestoreCSISnapshotOutputRestoreSizeArg: size.String(),
+	}, nil
+}
@@ -54,6 +54,15 @@ const (
 	RestoreDataBackupTagArg = "backupTag"
 	// RestoreDataPodOverrideArg contains pod specs which overrides default pod specs
 	RestoreDataPodOverrideArg = "podOverride"
+	// RestoreDataIncludeArg provides path patterns that limit the restore to matching files
+	RestoreDataIncludeArg = "include"
+	// RestoreDataExcludeArg provides path patterns for files to skip during restore. It
+	// cannot be combined with RestoreDataIncludeArg
+	RestoreDataExcludeArg = "exclude"
+	// RestoreDataTargetSubdirArg provides a directory below restorePath to restore into
+	RestoreDataTargetSubdirArg = "targetSubdir"
+	// RestoreDataOverwriteArg provides the policy for files that already exist: always, if-newer or never
+	RestoreDataOverwriteArg = "overwrite"
 )
 
 func init() {
@@ -77,7 +86,7 @@ func validateAndGetOptArgs(args map[string]interface{}, tp param.TemplateParams)
 	if err = OptArg(args, RestoreDataRestorePathArg, &restorePath, "/"); err != nil {
 		return restorePath, encryptionKey, pod, vols, tag, id, podOverride, err
 	}
-	if err = OptArg(args, RestoreDataEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, RestoreDataEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
 		return restorePath, encryptionKey, pod, vols, tag, id, podOverride, err
 	}
 	if err = OptArg(args, RestoreDataPodArg, &pod, ""); err != nil {
@@ -108,8 +117,29 @@ func validateAndGetOptArgs(args map[string]interface{}, tp param.TemplateParams)
 	return restorePath, encryptionKey, pod, vols, tag, id, podOverride, nil
 }
 
+// restoreOptionsFromArgs reads the optional file selection and overwrite
+// arguments shared by RestoreData and RestoreDataAll
+func restoreOptionsFromArgs(args map[string]interface{}, includeArg, excludeArg, targetSubdirArg, overwriteArg string) (restic.RestoreOptions, error) {
+	var opts restic.RestoreOptions
+	var overwrite string
+	if err := OptArg(args, includeArg, &opts.Include, nil); err != nil {
+		return opts, err
+	}
+	if err := OptArg(args, excludeArg, &opts.Exclude, nil); err != nil {
+		return opts, err
+	}
+	if err := OptArg(args, targetSubdirArg, &opts.TargetSubdir, ""); err != nil {
+		return opts, err
+	}
+	if err := OptArg(args, overwriteArg, &overwrite, ""); err != nil {
+		return opts, err
+	}
+	opts.Overwrite = restic.OverwritePolicy(overwrite)
+	return opts, opts.Validate()
+}
+
 func restoreData(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID, jobPrefix, image string,
-	vols map[string]string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+	vols map[string]string, podOverride crv1alpha1.JSONMap, opts restic.RestoreOptions) (map[string]interface{}, error) {
 	// Validate volumes
 	for pvc := range vols {
 		if _, err := cli.CoreV1().PersistentVolumeClaims(namespace).Get(pvc, metav1.GetOptions{}); err != nil {
@@ -125,11 +155,11 @@ func restoreData(ctx context.Context, cli kubernetes.Interface, tp param.Templat
 		PodOverride:  podOverride,
 	}
 	pr := kube.NewPodRunner(cli, options)
-	podFunc := restoreDataPodFunc(cli, tp, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID)
+	podFunc := restoreDataPodFunc(cli, tp, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID, opts)
 	return pr.Run(ctx, podFunc)
 }
 
-func restoreDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID string) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+func restoreDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID string, opts restic.RestoreOptions) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
 	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
 		// Wait for pod to reach running state
 		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
@@ -140,12 +170,17 @@ func restoreDataPodFunc(cli kubernetes.Interface, tp param.TemplateParams, names
 			return nil, err
 		}
 		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
 		var cmd []string
 		// Generate restore command based on the identifier passed
 		if backupTag != "" {
-			cmd, err = restic.RestoreCommandByTag(tp.Profile, backupArtifactPrefix, backupTag, restorePath, encryptionKey)
+			cmd, err = restic.RestoreCommandByTag(tp.Profile, backupArtifactPrefix, backupTag, restorePath, passwordFile, opts)
 		} else if backupID != "" {
-			cmd, err = restic.RestoreCommandByID(tp.Profile, backupArtifactPrefix, backupID, restorePath, encryptionKey)
+			cmd, err = restic.RestoreCommandByID(tp.Profile, backupArtifactPrefix, backupID, restorePath, passwordFile, opts)
 		}
 		if err != nil {
 			return nil, err
@@ -183,6 +218,10 @@ func (*restoreDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 	if podOverride == nil {
 		podOverride = tp.PodOverride
 	}
+	opts, err := restoreOptionsFromArgs(args, RestoreDataIncludeArg, RestoreDataExcludeArg, RestoreDataTargetSubdirArg, RestoreDataOverwriteArg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if PodOverride specs are passed through actionset
 	// If yes, override podOverride specs
@@ -210,10 +249,29 @@ func (*restoreDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
 	}
-	return restoreData(ctx, cli, tp, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID, restoreDataJobPrefix, image, vols, podOverride)
+	return restoreData(ctx, cli, tp, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID, restoreDataJobPrefix, image, vols, podOverride, opts)
 }
 
 func (*restoreDataFunc) RequiredArgs() []string {
 	return []string{RestoreDataNamespaceArg, RestoreDataImageArg,
 		RestoreDataBackupArtifactPrefixArg}
 }
+
+func (*restoreDataFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		RestoreDataNamespaceArg:            kanister.ArgTypeString,
+		RestoreDataImageArg:                kanister.ArgTypeString,
+		RestoreDataBackupArtifactPrefixArg: kanister.ArgTypeString,
+		RestoreDataRestorePathArg:          kanister.ArgTypeString,
+		RestoreDataBackupIdentifierArg:     kanister.ArgTypeString,
+		RestoreDataPodArg:                  kanister.ArgTypeString,
+		RestoreDataVolsArg:                 kanister.ArgTypeMap,
+		RestoreDataEncryptionKeyArg:        kanister.ArgTypeString,
+		RestoreDataBackupTagArg:            kanister.ArgTypeString,
+		RestoreDataPodOverrideArg:          kanister.ArgTypeMap,
+		RestoreDataIncludeArg:              kanister.ArgTypeStringSlice,
+		RestoreDataExcludeArg:              kanister.ArgTypeStringSlice,
+		RestoreDataTargetSubdirArg:         kanister.ArgTypeString,
+		RestoreDataOverwriteArg:            kanister.ArgTypeString,
+	}
+}
@@ -49,6 +49,14 @@ const (
 	RestoreDataAllBackupInfo = "backupInfo"
 	// RestoreDataPodOverrideArg contains pod specs which overrides default pod specs
 	RestoreDataAllPodOverrideArg = "podOverride"
+	// RestoreDataAllIncludeArg provides path patterns that limit the restore to matching files
+	RestoreDataAllIncludeArg = "include"
+	// RestoreDataAllExcludeArg provides path patterns for files to skip during restore
+	RestoreDataAllExcludeArg = "exclude"
+	// RestoreDataAllTargetSubdirArg provides a directory below restorePath to restore into
+	RestoreDataAllTargetSubdirArg = "targetSubdir"
+	// RestoreDataAllOverwriteArg provides the policy for files that already exist: always, if-newer or never
+	RestoreDataAllOverwriteArg = "overwrite"
 )
 
 func init() {
@@ -72,7 +80,7 @@ func validateAndGetRestoreAllOptArgs(args map[string]interface{}, tp param.Templ
 	if err = OptArg(args, RestoreDataAllRestorePathArg, &restorePath, "/"); err != nil {
 		return restorePath, encryptionKey, ps, podOverride, err
 	}
-	if err = OptArg(args, RestoreDataAllEncryptionKeyArg, &encryptionKey, restic.GeneratePassword()); err != nil {
+	if err = OptArg(args, RestoreDataAllEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
 		return restorePath, encryptionKey, ps, podOverride, err
 	}
 	if err = OptArg(args, RestoreDataAllPodsArg, &pods, ""); err != nil {
@@ -120,6 +128,10 @@ func (*restoreDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, ar
 	if err != nil {
 		return nil, err
 	}
+	opts, err := restoreOptionsFromArgs(args, RestoreDataAllIncludeArg, RestoreDataAllExcludeArg, RestoreDataAllTargetSubdirArg, RestoreDataAllOverwriteArg)
+	if err != nil {
+		return nil, err
+	}
 
 	if err = ValidateProfile(tp.Profile); err != nil {
 		return nil, err
@@ -146,7 +158,7 @@ func (*restoreDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, ar
 				outputChan <- out
 				return
 			}
-			out, err = restoreData(ctx, cli, tp, namespace, encryptionKey, fmt.Sprintf("%s/%s", backupArtifactPrefix, pod), restorePath, "", input[pod].BackupID, restoreDataAllJobPrefix, image, vols, podOverride)
+			out, err = restoreData(ctx, cli, tp, namespace, encryptionKey, fmt.Sprintf("%s/%s", backupArtifactPrefix, pod), restorePath, "", input[pod].BackupID, restoreDataAllJobPrefix, image, vols, podOverride, opts)
 			errChan <- errors.Wrapf(err, "Failed to restore data for pod %s", pod)
 			outputChan <- out
 		}(pod)
@@ -173,3 +185,20 @@ func (*restoreDataAllFunc) RequiredArgs() []string {
 	return []string{RestoreDataAllNamespaceArg, RestoreDataAllImageArg,
 		RestoreDataAllBackupArtifactPrefixArg, RestoreDataAllBackupInfo}
 }
+
+func (*restoreDataAllFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		RestoreDataAllNamespaceArg:            kanister.ArgTypeString,
+		RestoreDataAllImageArg:                kanister.ArgTypeString,
+		RestoreDataAllBackupArtifactPrefixArg: kanister.ArgTypeString,
+		RestoreDataAllRestorePathArg:          kanister.ArgTypeString,
+		RestoreDataAllPodsArg:                 kanister.ArgTypeString,
+		RestoreDataAllEncryptionKeyArg:        kanister.ArgTypeString,
+		RestoreDataAllBackupInfo:              kanister.ArgTypeString,
+		RestoreDataAllPodOverrideArg:          kanister.ArgTypeMap,
+		RestoreDataAllIncludeArg:              kanister.ArgTypeStringSlice,
+		RestoreDataAllExcludeArg:              kanister.ArgTypeStringSlice,
+		RestoreDataAllTargetSubdirArg:         kanister.ArgTypeString,
+		RestoreDataAllOverwriteArg:            kanister.ArgTypeString,
+	}
+}
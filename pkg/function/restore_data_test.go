@@ -19,6 +19,7 @@ import (
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
 )
 
 type RestoreDataTestSuite struct{}
@@ -114,3 +115,67 @@ func (s *RestoreDataTestSuite) TestValidateAndGetOptArgs(c *C) {
 		c.Check(err, tc.errChecker, Commentf("Case %s failed", tc.name))
 	}
 }
+
+func (s *RestoreDataTestSuite) TestRestoreOptionsFromArgs(c *C) {
+	for _, tc := range []struct {
+		name       string
+		args       map[string]interface{}
+		expected   restic.RestoreOptions
+		errChecker Checker
+	}{
+		{
+			name:       "No options",
+			args:       map[string]interface{}{},
+			expected:   restic.RestoreOptions{},
+			errChecker: IsNil,
+		},
+		{
+			name: "All options",
+			args: map[string]interface{}{
+				RestoreDataIncludeArg:      []interface{}{"/data/db/orders.ibd"},
+				RestoreDataTargetSubdirArg: "recovered",
+				RestoreDataOverwriteArg:    "never",
+			},
+			expected: restic.RestoreOptions{
+				Include:      []string{"/data/db/orders.ibd"},
+				TargetSubdir: "recovered",
+				Overwrite:    restic.OverwriteNever,
+			},
+			errChecker: IsNil,
+		},
+		{
+			name:       "Unknown overwrite policy",
+			args:       map[string]interface{}{RestoreDataOverwriteArg: "sometimes"},
+			errChecker: NotNil,
+		},
+		{
+			name: "Exclude",
+			args: map[string]interface{}{
+				RestoreDataExcludeArg: []interface{}{"*.log", "*.tmp"},
+			},
+			expected: restic.RestoreOptions{
+				Exclude: []string{"*.log", "*.tmp"},
+			},
+			errChecker: IsNil,
+		},
+		{
+			name: "Include and exclude",
+			args: map[string]interface{}{
+				RestoreDataIncludeArg: []interface{}{"/data/db/orders.ibd"},
+				RestoreDataExcludeArg: []interface{}{"*.log"},
+			},
+			errChecker: NotNil,
+		},
+		{
+			name:       "Target subdirectory outside restore path",
+			args:       map[string]interface{}{RestoreDataTargetSubdirArg: "../other"},
+			errChecker: NotNil,
+		},
+	} {
+		opts, err := restoreOptionsFromArgs(tc.args, RestoreDataIncludeArg, RestoreDataExcludeArg, RestoreDataTargetSubdirArg, RestoreDataOverwriteArg)
+		c.Check(err, tc.errChecker, Commentf("Case %s failed", tc.name))
+		if err == nil {
+			c.Check(opts, DeepEquals, tc.expected, Commentf("Case %s failed", tc.name))
+		}
+	}
+}
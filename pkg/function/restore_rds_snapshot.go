@@ -78,6 +78,19 @@ func (*restoreRDSSnapshotFunc) RequiredArgs() []string {
 	return []string{RestoreRDSSnapshotNamespace, RestoreRDSSnapshotInstanceID, RestoreRDSSnapshotDBEngine}
 }
 
+func (*restoreRDSSnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		RestoreRDSSnapshotNamespace:            kanister.ArgTypeString,
+		RestoreRDSSnapshotInstanceID:           kanister.ArgTypeString,
+		RestoreRDSSnapshotSnapshotID:           kanister.ArgTypeString,
+		RestoreRDSSnapshotBackupArtifactPrefix: kanister.ArgTypeString,
+		RestoreRDSSnapshotBackupID:             kanister.ArgTypeString,
+		RestoreRDSSnapshotUsername:             kanister.ArgTypeString,
+		RestoreRDSSnapshotPassword:             kanister.ArgTypeString,
+		RestoreRDSSnapshotDBEngine:             kanister.ArgTypeString,
+	}
+}
+
 func (*restoreRDSSnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
 	var namespace, instanceID, snapshotID, backupArtifactPrefix, backupID, username, password string
 	var dbEngine RDSDBEngine
@@ -75,6 +75,19 @@ func (*scaleWorkloadFunc) RequiredArgs() []string {
 	return []string{ScaleWorkloadReplicas}
 }
 
+func (*scaleWorkloadFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		ScaleWorkloadNamespaceArg: kanister.ArgTypeString,
+		ScaleWorkloadNameArg:      kanister.ArgTypeString,
+		ScaleWorkloadKindArg:      kanister.ArgTypeString,
+		ScaleWorkloadReplicas:     kanister.ArgTypeInt,
+	}
+}
+
+func (*scaleWorkloadFunc) Outputs() []string {
+	return []string{}
+}
+
 func getArgs(tp param.TemplateParams, args map[string]interface{}) (namespace, kind, name string, replicas int32, err error) {
 	var rep interface{}
 	err = Arg(args, ScaleWorkloadReplicas, &rep)
@@ -0,0 +1,197 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	// UnlockRepositoryFuncName gives the name of the function
+	UnlockRepositoryFuncName = "UnlockRepository"
+	// UnlockRepositoryArtifactPrefixArg provides the path of the repository
+	UnlockRepositoryArtifactPrefixArg = "backupArtifactPrefix"
+	// UnlockRepositoryEncryptionKeyArg provides the encryption key of the repository
+	UnlockRepositoryEncryptionKeyArg = "encryptionKey"
+	// UnlockRepositoryRemoveAllArg removes all locks instead of only stale ones
+	UnlockRepositoryRemoveAllArg = "removeAll"
+	// UnlockRepositoryPodOverrideArg contains pod specs to override default pod specs
+	UnlockRepositoryPodOverrideArg = "podOverride"
+	// UnlockRepositoryOutputLocksRemoved is the number of locks that were removed
+	UnlockRepositoryOutputLocksRemoved = "locksRemoved"
+	// UnlockRepositoryOutputLocksRemaining is the number of locks left in the repository
+	UnlockRepositoryOutputLocksRemaining = "locksRemaining"
+	unlockRepositoryJobPrefix            = "unlock-repository-"
+)
+
+func init() {
+	_ = kanister.Register(&unlockRepositoryFunc{})
+}
+
+var _ kanister.Func = (*unlockRepositoryFunc)(nil)
+
+type unlockRepositoryFunc struct{}
+
+func (*unlockRepositoryFunc) Name() string {
+	return UnlockRepositoryFuncName
+}
+
+func unlockRepository(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, targetPath string, removeAll bool, jobPrefix string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+	namespace, err := kube.GetControllerNamespace()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get controller namespace")
+	}
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: jobPrefix,
+		Image:        kanisterToolsImage,
+		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:  podOverride,
+	}
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := unlockRepositoryPodFunc(cli, tp, namespace, encryptionKey, targetPath, removeAll)
+	return pr.Run(ctx, podFunc)
+}
+
+func unlockRepositoryPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, targetPath string, removeAll bool) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+		// Wait for pod to reach running state
+		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pod.Name)
+		}
+		container := pod.Spec.Containers[0].Name
+		pw, err := GetPodWriter(cli, ctx, pod.Namespace, pod.Name, container, tp.Profile)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, container)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, container, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, container)
+		// restic unlock does not report how many locks it removed, so
+		// compare the locks before and after
+		before, err := listLocks(cli, tp, namespace, pod.Name, container, passwordFile, targetPath)
+		if err != nil {
+			return nil, err
+		}
+		cmd, err := restic.UnlockCommand(tp.Profile, targetPath, passwordFile, removeAll)
+		if err != nil {
+			return nil, err
+		}
+		stdout, stderr, err := kube.Exec(cli, namespace, pod.Name, container, cmd, nil)
+		format.Log(pod.Name, container, stdout)
+		format.Log(pod.Name, container, stderr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to unlock repository")
+		}
+		after, err := listLocks(cli, tp, namespace, pod.Name, container, passwordFile, targetPath)
+		if err != nil {
+			return nil, err
+		}
+		remaining := make(map[string]bool, len(after))
+		for _, id := range after {
+			remaining[id] = true
+		}
+		removed := 0
+		for _, id := range before {
+			if !remaining[id] {
+				removed++
+			}
+		}
+		return map[string]interface{}{
+			UnlockRepositoryOutputLocksRemoved:   strconv.Itoa(removed),
+			UnlockRepositoryOutputLocksRemaining: strconv.Itoa(len(after)),
+			FunctionOutputVersion:                kanister.DefaultVersion,
+		}, nil
+	}
+}
+
+func listLocks(cli kubernetes.Interface, tp param.TemplateParams, namespace, pod, container, passwordFile, targetPath string) ([]string, error) {
+	cmd, err := restic.ListLocksCommand(tp.Profile, targetPath, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr, err := kube.Exec(cli, namespace, pod, container, cmd, nil)
+	format.Log(pod, container, stderr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list repository locks")
+	}
+	return restic.LockIDsFromListLocksLog(stdout), nil
+}
+
+func (*unlockRepositoryFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var artifactPrefix, encryptionKey string
+	var removeAll bool
+	if err := Arg(args, UnlockRepositoryArtifactPrefixArg, &artifactPrefix); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, UnlockRepositoryEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, UnlockRepositoryRemoveAllArg, &removeAll, false); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, UnlockRepositoryPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateProfile(tp.Profile); err != nil {
+		return nil, err
+	}
+
+	artifactPrefix = ResolveArtifactPrefix(artifactPrefix, tp.Profile)
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	return unlockRepository(ctx, cli, tp, encryptionKey, artifactPrefix, removeAll, unlockRepositoryJobPrefix, podOverride)
+}
+
+func (*unlockRepositoryFunc) RequiredArgs() []string {
+	return []string{UnlockRepositoryArtifactPrefixArg}
+}
+
+func (*unlockRepositoryFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		UnlockRepositoryArtifactPrefixArg: kanister.ArgTypeString,
+		UnlockRepositoryEncryptionKeyArg:  kanister.ArgTypeString,
+		UnlockRepositoryRemoveAllArg:      kanister.ArgTypeBool,
+		UnlockRepositoryPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*unlockRepositoryFunc) Outputs() []string {
+	return []string{
+		UnlockRepositoryOutputLocksRemoved,
+		UnlockRepositoryOutputLocksRemaining,
+		FunctionOutputVersion,
+	}
+}
@@ -3,11 +3,13 @@ package function
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"path"
 	"strings"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/kubernetes"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
@@ -15,6 +17,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/aws/rds"
 	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
 	"github.com/kanisterio/kanister/pkg/log"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/secrets"
@@ -53,17 +56,19 @@ func ValidateProfile(profile *param.Profile) error {
 	if profile == nil {
 		return errors.New("Profile must be non-nil")
 	}
-	if err := ValidateCredentials(&profile.Credential); err != nil {
-		return err
-	}
 	switch profile.Location.Type {
 	case crv1alpha1.LocationTypeS3Compliant:
 	case crv1alpha1.LocationTypeGCS:
 	case crv1alpha1.LocationTypeAzure:
+	case crv1alpha1.LocationTypeFilesystem:
+		// Credentials are optional for local directories
+		if profile.Credential.Type == "" {
+			return nil
+		}
 	default:
 		return errors.New("Location type not supported")
 	}
-	return nil
+	return ValidateCredentials(&profile.Credential)
 }
 
 // GetPodWriter creates a file with Google credentials if the given profile points to a GCS location
@@ -78,6 +83,19 @@ func GetPodWriter(cli kubernetes.Interface, ctx context.Context, namespace, podN
 	return nil, nil
 }
 
+// WriteResticPasswordFile writes the restic repository password to a new file
+// in the container, so that it is not part of the restic commands run there.
+// It returns the path of the file and the PodWriter that removes it.
+func WriteResticPasswordFile(cli kubernetes.Interface, ctx context.Context, namespace, podName, containerName, password string) (string, *kube.PodWriter, error) {
+	// Functions may run in the same container concurrently
+	path := fmt.Sprintf("%s-%s", consts.ResticPasswordFilePath, rand.String(8))
+	pw := kube.NewPodWriter(cli, path, bytes.NewBufferString(password))
+	if err := pw.Write(ctx, namespace, podName, containerName); err != nil {
+		return "", nil, errors.Wrap(err, "Failed to write restic password to pod")
+	}
+	return path, pw, nil
+}
+
 // CleanUpCredsFile is used to remove the file created by the given PodWriter
 func CleanUpCredsFile(ctx context.Context, pw *kube.PodWriter, namespace, podName, containerName string) {
 	if pw != nil {
@@ -87,6 +105,28 @@ func CleanUpCredsFile(ctx context.Context, pw *kube.PodWriter, namespace, podNam
 	}
 }
 
+// newSnapshotter returns a Kubernetes client and the Snapshotter for the
+// version of the CSI VolumeSnapshot API served by the cluster.
+func newSnapshotter() (kubernetes.Interface, snapshot.Snapshotter, error) {
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create Kubernetes client")
+	}
+	snapCli, err := kube.NewSnapshotClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create VolumeSnapshot client")
+	}
+	dynCli, err := kube.NewDynamicClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create dynamic client")
+	}
+	snapshotter, err := snapshot.NewSnapshotter(cli, snapCli, dynCli)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cli, snapshotter, nil
+}
+
 // FetchPodVolumes returns a map of PVCName->MountPath for a given pod
 func FetchPodVolumes(pod string, tp param.TemplateParams) (map[string]string, error) {
 	switch {
@@ -38,6 +38,7 @@ func (s *UtilsTestSuite) TestValidateProfile(c *C) {
 		{"Valid Profile with Secret Credentials", newValidProfileWithSecretCredentials(), IsNil},
 		{"Invalid Profile", newInvalidProfile(), NotNil},
 		{"Invalid Profile with Secret Credentials", newInvalidProfileWithSecretCredentials(), NotNil},
+		{"Valid Filesystem Profile", newValidFilesystemProfile(), IsNil},
 		{"Nil Profile", nil, NotNil},
 	}
 	for _, tc := range testCases {
@@ -146,6 +147,16 @@ func newValidProfileWithSecretCredentials() *param.Profile {
 	}
 }
 
+func newValidFilesystemProfile() *param.Profile {
+	return &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Bucket:   "test-bucket",
+			Endpoint: "/mnt/backups",
+		},
+	}
+}
+
 func newInvalidProfile() *param.Profile {
 	return &param.Profile{
 		Location: crv1alpha1.Location{
@@ -0,0 +1,164 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	// VerifyRepositoryFuncName gives the name of the function
+	VerifyRepositoryFuncName = "VerifyRepository"
+	// VerifyRepositoryArtifactPrefixArg provides the path of the repository
+	VerifyRepositoryArtifactPrefixArg = "backupArtifactPrefix"
+	// VerifyRepositoryEncryptionKeyArg provides the encryption key of the repository
+	VerifyRepositoryEncryptionKeyArg = "encryptionKey"
+	// VerifyRepositoryReadDataSubsetArg selects a part of the data to download and verify, e.g. "1/5"
+	VerifyRepositoryReadDataSubsetArg = "readDataSubset"
+	// VerifyRepositoryPodOverrideArg contains pod specs to override default pod specs
+	VerifyRepositoryPodOverrideArg = "podOverride"
+	// VerifyRepositoryOutputErrorsFound is "true" if the check found errors in the repository
+	VerifyRepositoryOutputErrorsFound = "errorsFound"
+	verifyRepositoryJobPrefix         = "verify-repository-"
+)
+
+func init() {
+	_ = kanister.Register(&verifyRepositoryFunc{})
+}
+
+var _ kanister.Func = (*verifyRepositoryFunc)(nil)
+
+type verifyRepositoryFunc struct{}
+
+func (*verifyRepositoryFunc) Name() string {
+	return VerifyRepositoryFuncName
+}
+
+func verifyRepository(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, encryptionKey, targetPath, readDataSubset, jobPrefix string, podOverride crv1alpha1.JSONMap) (map[string]interface{}, error) {
+	namespace, err := kube.GetControllerNamespace()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get controller namespace")
+	}
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: jobPrefix,
+		Image:        kanisterToolsImage,
+		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:  podOverride,
+	}
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := verifyRepositoryPodFunc(cli, tp, namespace, encryptionKey, targetPath, readDataSubset)
+	return pr.Run(ctx, podFunc)
+}
+
+func verifyRepositoryPodFunc(cli kubernetes.Interface, tp param.TemplateParams, namespace, encryptionKey, targetPath, readDataSubset string) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+		// Wait for pod to reach running state
+		if err := kube.WaitForPodReady(ctx, cli, pod.Namespace, pod.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pod.Name)
+		}
+		pw, err := GetPodWriter(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, tp.Profile)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, pw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		passwordFile, kw, err := WriteResticPasswordFile(cli, ctx, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		defer CleanUpCredsFile(ctx, kw, pod.Namespace, pod.Name, pod.Spec.Containers[0].Name)
+		cmd, err := restic.CheckCommand(tp.Profile, targetPath, readDataSubset, passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		stdout, stderr, err := kube.Exec(cli, namespace, pod.Name, pod.Spec.Containers[0].Name, cmd, nil)
+		format.Log(pod.Name, pod.Spec.Containers[0].Name, stdout)
+		format.Log(pod.Name, pod.Spec.Containers[0].Name, stderr)
+		errorsFound := false
+		if err != nil {
+			// A corrupt repository is reported in the output rather than
+			// failing the phase, so that Blueprints can act on it
+			if !restic.CheckErrorsFound(stderr) {
+				return nil, errors.Wrapf(err, "Failed to check repository")
+			}
+			errorsFound = true
+		}
+		return map[string]interface{}{
+			VerifyRepositoryOutputErrorsFound: strconv.FormatBool(errorsFound),
+			FunctionOutputVersion:             kanister.DefaultVersion,
+		}, nil
+	}
+}
+
+func (*verifyRepositoryFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var artifactPrefix, encryptionKey, readDataSubset string
+	if err := Arg(args, VerifyRepositoryArtifactPrefixArg, &artifactPrefix); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, VerifyRepositoryEncryptionKeyArg, &encryptionKey, restic.PasswordFromProfile(tp.Profile)); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, VerifyRepositoryReadDataSubsetArg, &readDataSubset, ""); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, VerifyRepositoryPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateProfile(tp.Profile); err != nil {
+		return nil, err
+	}
+
+	artifactPrefix = ResolveArtifactPrefix(artifactPrefix, tp.Profile)
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	return verifyRepository(ctx, cli, tp, encryptionKey, artifactPrefix, readDataSubset, verifyRepositoryJobPrefix, podOverride)
+}
+
+func (*verifyRepositoryFunc) RequiredArgs() []string {
+	return []string{VerifyRepositoryArtifactPrefixArg}
+}
+
+func (*verifyRepositoryFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		VerifyRepositoryArtifactPrefixArg: kanister.ArgTypeString,
+		VerifyRepositoryEncryptionKeyArg:  kanister.ArgTypeString,
+		VerifyRepositoryReadDataSubsetArg: kanister.ArgTypeString,
+		VerifyRepositoryPodOverrideArg:    kanister.ArgTypeMap,
+	}
+}
+
+func (*verifyRepositoryFunc) Outputs() []string {
+	return []string{
+		VerifyRepositoryOutputErrorsFound,
+		FunctionOutputVersion,
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+func init() {
+	_ = kanister.Register(&waitForCSISnapshotFunc{})
+}
+
+var (
+	_ kanister.Func = (*waitForCSISnapshotFunc)(nil)
+)
+
+const (
+	// WaitForCSISnapshotFuncName gives the name of the function
+	WaitForCSISnapshotFuncName = "WaitForCSISnapshot"
+	// WaitForCSISnapshotNameArg provides the name of the VolumeSnapshot
+	WaitForCSISnapshotNameArg = "name"
+	// WaitForCSISnapshotNamespaceArg mentions the namespace of the VolumeSnapshot
+	WaitForCSISnapshotNamespaceArg = "namespace"
+)
+
+type waitForCSISnapshotFunc struct{}
+
+func (*waitForCSISnapshotFunc) Name() string {
+	return WaitForCSISnapshotFuncName
+}
+
+func (*waitForCSISnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	var name, namespace string
+	if err := Arg(args, WaitForCSISnapshotNameArg, &name); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, WaitForCSISnapshotNamespaceArg, &namespace); err != nil {
+		return nil, err
+	}
+	_, snapshotter, err := newSnapshotter()
+	if err != nil {
+		return nil, err
+	}
+	return waitForCSISnapshot(ctx, snapshotter, name, namespace)
+}
+
+func (*waitForCSISnapshotFunc) RequiredArgs() []string {
+	return []string{
+		WaitForCSISnapshotNameArg,
+		WaitForCSISnapshotNamespaceArg,
+	}
+}
+
+func (*waitForCSISnapshotFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		WaitForCSISnapshotNameArg:      kanister.ArgTypeString,
+		WaitForCSISnapshotNamespaceArg: kanister.ArgTypeString,
+	}
+}
+
+func (*waitForCSISnapshotFunc) Outputs() []string {
+	return csiSnapshotOutputs()
+}
+
+func waitForCSISnapshot(ctx context.Context, snapshotter snapshot.Snapshotter, name, namespace string) (map[string]interface{}, error) {
+	if err := snapshotter.WaitOnReadyToUse(ctx, name, namespace); err != nil {
+		return nil, errors.Wrapf(err, "Failed while waiting for VolumeSnapshot %s, Namespace %s to be ready to use", name, namespace)
+	}
+	return csiSnapshotOutput(ctx, snapshotter, name, namespace)
+}
@@ -51,6 +51,16 @@ func (*waitForSnapshotCompletionFunc) RequiredArgs() []string {
 	return []string{WaitForSnapshotCompletionSnapshotsArg}
 }
 
+func (*waitForSnapshotCompletionFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		WaitForSnapshotCompletionSnapshotsArg: kanister.ArgTypeString,
+	}
+}
+
+func (*waitForSnapshotCompletionFunc) Outputs() []string {
+	return []string{}
+}
+
 func (kef *waitForSnapshotCompletionFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
 	var snapshotinfo string
 	if err := Arg(args, WaitForSnapshotCompletionSnapshotsArg, &snapshotinfo); err != nil {
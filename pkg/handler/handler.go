@@ -19,12 +19,22 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/kanisterio/kanister/pkg/metrics"
+	"github.com/kanisterio/kanister/pkg/validatingwebhook"
 	"github.com/kanisterio/kanister/pkg/version"
 )
 
 const (
 	healthCheckPath = "/v0/healthz"
 	healthCheckAddr = ":8000"
+	metricsPath     = "/metrics"
+	webhookAddr     = ":8443"
+	// BlueprintWebhookPath is the path the Blueprint validating admission
+	// webhook is served at.
+	BlueprintWebhookPath = "/validate/v1alpha1/blueprint"
+	// WebhookCertDir is the directory the serving certificate `tls.crt` and
+	// key `tls.key` of the webhook server are mounted at.
+	WebhookCertDir = "/var/run/webhook/serving-cert"
 )
 
 // Info provides information about kanister controller
@@ -55,9 +65,20 @@ func (*healthCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	_, _ = io.WriteString(w, string(js))
 }
 
-// NewServer returns a pointer to the http Server
+// NewServer returns a pointer to the http Server that serves the health check
+// and the Prometheus metrics of the controller.
 func NewServer() *http.Server {
 	m := &http.ServeMux{}
 	m.Handle(healthCheckPath, &healthCheckHandler{})
+	m.Handle(metricsPath, metrics.Handler())
 	return &http.Server{Addr: healthCheckAddr, Handler: m}
 }
+
+// NewWebhookServer returns a pointer to the http Server that serves the
+// validating admission webhooks of the controller. It must be started with
+// ListenAndServeTLS since the API server only calls webhooks over TLS.
+func NewWebhookServer() *http.Server {
+	m := &http.ServeMux{}
+	m.Handle(BlueprintWebhookPath, validatingwebhook.NewBlueprintHandler())
+	return &http.Server{Addr: webhookAddr, Handler: m}
+}
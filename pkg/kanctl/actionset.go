@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/kanisterio/kanister/pkg/actionset"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 	"github.com/kanisterio/kanister/pkg/kube"
@@ -51,6 +52,7 @@ const (
 	selectorNamespaceFlag    = "selector-namespace"
 	namespaceTargetsFlagName = "namespacetargets"
 	objectsFlagName          = "objects"
+	renderOnlyFlagName       = "render-only"
 )
 
 type PerformParams struct {
@@ -59,6 +61,7 @@ type PerformParams struct {
 	ParentName string
 	Blueprint  string
 	DryRun     bool
+	RenderOnly bool
 	Objects    []crv1alpha1.ObjectReference
 	Options    map[string]string
 	Profile    *crv1alpha1.ObjectReference
@@ -91,6 +94,7 @@ func newActionSetCmd() *cobra.Command {
 	cmd.Flags().String(selectorNamespaceFlag, "", "namespace to apply selector on. Used along with the selector specified using --selector/-l")
 	cmd.Flags().StringSliceP(namespaceTargetsFlagName, "T", []string{}, "namespaces for the action set, comma separated list of namespaces (eg: --namespacetargets namespace1,namespace2)")
 	cmd.Flags().StringSliceP(objectsFlagName, "O", []string{}, "objects for the action set, comma separated list of object references (eg: --objects group/version/resource/namespace1/name1,group/version/resource/namespace2/name2)")
+	cmd.Flags().Bool(renderOnlyFlagName, false, "if set, the action set is created in render-only mode: the args of its phases are rendered and reported in its status but the phases are not executed")
 	return cmd
 }
 
@@ -134,6 +138,9 @@ func perform(ctx context.Context, crCli versioned.Interface, params *PerformPara
 	if err != nil {
 		return err
 	}
+	if params.RenderOnly {
+		as.Spec.DryRun = true
+	}
 	if params.DryRun {
 		return printActionSet(as)
 	}
@@ -170,63 +177,18 @@ func newActionSet(params *PerformParams) (*crv1alpha1.ActionSet, error) {
 	}, nil
 }
 
+// ChildActionSet returns an ActionSet that runs the actions of parent with
+// the overrides in params.
 func ChildActionSet(parent *crv1alpha1.ActionSet, params *PerformParams) (*crv1alpha1.ActionSet, error) {
-	if parent.Status == nil || parent.Status.State != crv1alpha1.StateComplete {
-		return nil, errors.Errorf("Request parent ActionSet %s has not been executed", parent.GetName())
-	}
-
-	actions := make([]crv1alpha1.ActionSpec, 0, len(parent.Status.Actions)*max(1, len(params.Objects)))
-	for aidx, pa := range parent.Status.Actions {
-		as := crv1alpha1.ActionSpec{
-			Name:       parent.Spec.Actions[aidx].Name,
-			Blueprint:  pa.Blueprint,
-			Object:     pa.Object,
-			Artifacts:  pa.Artifacts,
-			Secrets:    parent.Spec.Actions[aidx].Secrets,
-			ConfigMaps: parent.Spec.Actions[aidx].ConfigMaps,
-			Profile:    parent.Spec.Actions[aidx].Profile,
-			Options:    mergeOptions(params.Options, parent.Spec.Actions[aidx].Options),
-		}
-		// Apply overrides
-		if params.ActionName != "" {
-			as.Name = params.ActionName
-		}
-		if params.Blueprint != "" {
-			as.Blueprint = params.Blueprint
-		}
-		if len(params.Secrets) > 0 {
-			as.Secrets = params.Secrets
-		}
-		if len(params.ConfigMaps) > 0 {
-			as.ConfigMaps = params.ConfigMaps
-		}
-		if params.Profile != nil {
-			as.Profile = params.Profile
-		}
-		if len(params.Objects) > 0 {
-			for _, obj := range params.Objects {
-				asCopy := as.DeepCopy()
-				asCopy.Object = obj
-
-				actions = append(actions, *asCopy)
-			}
-		} else {
-			actions = append(actions, as)
-		}
-	}
-	return &crv1alpha1.ActionSet{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: func() string {
-				if params.ActionName != "" {
-					return fmt.Sprintf("%s-%s-", params.ActionName, parent.GetName())
-				}
-				return fmt.Sprintf("%s-", parent.GetName())
-			}(),
-		},
-		Spec: &crv1alpha1.ActionSetSpec{
-			Actions: actions,
-		},
-	}, nil
+	return actionset.Child(parent, actionset.ChildParams{
+		ActionName: params.ActionName,
+		Blueprint:  params.Blueprint,
+		Objects:    params.Objects,
+		Options:    params.Options,
+		Profile:    params.Profile,
+		Secrets:    params.Secrets,
+		ConfigMaps: params.ConfigMaps,
+	})
 }
 
 func createActionSet(ctx context.Context, crCli versioned.Interface, namespace string, as *crv1alpha1.ActionSet) error {
@@ -262,6 +224,7 @@ func extractPerformParams(cmd *cobra.Command, args []string, cli kubernetes.Inte
 	parentName, _ := cmd.Flags().GetString(sourceFlagName)
 	blueprint, _ := cmd.Flags().GetString(blueprintFlagName)
 	dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+	renderOnly, _ := cmd.Flags().GetBool(renderOnlyFlagName)
 	profile, err := parseProfile(cmd, ns)
 	if err != nil {
 		return nil, err
@@ -288,6 +251,7 @@ func extractPerformParams(cmd *cobra.Command, args []string, cli kubernetes.Inte
 		ParentName: parentName,
 		Blueprint:  blueprint,
 		DryRun:     dryRun,
+		RenderOnly: renderOnly,
 		Objects:    objects,
 		Options:    options,
 		Secrets:    secrets,
@@ -513,18 +477,6 @@ func parseOptions(cmd *cobra.Command) (map[string]string, error) {
 	return options, nil
 }
 
-func mergeOptions(src map[string]string, dst map[string]string) map[string]string {
-	final := make(map[string]string, len(src)+len(dst))
-	for k, v := range dst {
-		final[k] = v
-	}
-	// Override default options and set additional ones
-	for k, v := range src {
-		final[k] = v
-	}
-	return final
-}
-
 func parseReferences(references []string) (map[string]crv1alpha1.ObjectReference, error) {
 	m := make(map[string]crv1alpha1.ObjectReference)
 	parsed := make(map[string]bool)
@@ -661,10 +613,3 @@ func verifyParams(ctx context.Context, p *PerformParams, cli kubernetes.Interfac
 	}
 	return nil
 }
-
-func max(x, y int) int {
-	if x > y {
-		return x
-	}
-	return y
-}
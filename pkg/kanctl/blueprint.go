@@ -0,0 +1,80 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanctl
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sYAML "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	// Register the Kanister functions that Blueprints are validated against.
+	_ "github.com/kanisterio/kanister/pkg/function"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+const blueprintValidation = "Validate Blueprint"
+
+// performBlueprintValidation validates a Blueprint with the same checks as the
+// validating webhook of the controller. A Blueprint read from a file is
+// validated without connecting to a cluster.
+func performBlueprintValidation(p *validateParams) error {
+	bp, err := getBlueprintFromCmd(p)
+	if err != nil {
+		return err
+	}
+	if err := validate.Blueprint(bp); err != nil {
+		printStage(blueprintValidation, fail)
+		return err
+	}
+	printStage(blueprintValidation, pass)
+	printStage(fmt.Sprintf("All checks passed.. %s\n", pass), "")
+	return nil
+}
+
+func getBlueprintFromCmd(p *validateParams) (*v1alpha1.Blueprint, error) {
+	if p.filename != "" {
+		return getBlueprintFromFile(p.filename)
+	}
+	_, crCli, err := initializeClients()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not initialize clients for validation")
+	}
+	return crCli.CrV1alpha1().Blueprints(p.namespace).Get(p.name, metav1.GetOptions{})
+}
+
+func getBlueprintFromFile(filename string) (*v1alpha1.Blueprint, error) {
+	var f *os.File
+	var err error
+
+	if filename == "-" {
+		f = os.Stdin
+	} else {
+		f, err = os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+	d := k8sYAML.NewYAMLOrJSONDecoder(f, 4096)
+	bp := &v1alpha1.Blueprint{}
+	if err = d.Decode(bp); err != nil {
+		return nil, err
+	}
+	return bp, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+)
+
+func newCancelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel <actionset>",
+		Short: "Cancel a running ActionSet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return cancelActionSet(c, args)
+		},
+	}
+	return cmd
+}
+
+func cancelActionSet(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return newArgsLengthError("expected 1 argument. got %#v", args)
+	}
+	name := args[0]
+	ns, err := resolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	_, crCli, err := initializeClients()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	err = reconcile.ActionSet(ctx, crCli.CrV1alpha1(), ns, name, func(as *crv1alpha1.ActionSet) error {
+		as.Spec.Cancel = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("actionset %s cancel requested\n", name)
+	return nil
+}
@@ -62,6 +62,7 @@ func newRootCommand() *cobra.Command {
 	rootCmd.PersistentFlags().BoolVar(&Verbose, verboseFlagName, false, "Display verbose output")
 	rootCmd.AddCommand(newValidateCommand())
 	rootCmd.AddCommand(newCreateCommand())
+	rootCmd.AddCommand(newCancelCommand())
 	return rootCmd
 }
 
@@ -69,8 +69,10 @@ func performValidation(cmd *cobra.Command, args []string) error {
 	switch p.resourceKind {
 	case "profile":
 		return performProfileValidation(p)
+	case "blueprint":
+		return performBlueprintValidation(p)
 	default:
-		return errors.Errorf("expected profile.. got %s. Not supported", p.resourceKind)
+		return errors.Errorf("expected profile or blueprint.. got %s. Not supported", p.resourceKind)
 	}
 }
 
@@ -52,6 +52,7 @@ func newRootCommand() *cobra.Command {
 	rootCmd.AddCommand(newLocationCommand())
 	rootCmd.AddCommand(newOutputCommand())
 	rootCmd.AddCommand(newChronicleCommand())
+	rootCmd.AddCommand(newResticCommand())
 	return rootCmd
 }
 
@@ -31,11 +31,12 @@ const (
 func newLocationCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "location <command>",
-		Short: "Push, pull and delete from object storage",
+		Short: "Push, pull, delete and verify artifacts in object storage",
 	}
 	cmd.AddCommand(newLocationPushCommand())
 	cmd.AddCommand(newLocationPullCommand())
 	cmd.AddCommand(newLocationDeleteCommand())
+	cmd.AddCommand(newLocationVerifyCommand())
 	cmd.PersistentFlags().StringP(pathFlagName, "s", "", "Specify a path suffix (optional)")
 	cmd.PersistentFlags().StringP(profileFlagName, "p", "", "Pass a Profile as a JSON string (required)")
 	_ = cmd.MarkFlagRequired(profileFlagName)
@@ -18,14 +18,28 @@ import (
 	"context"
 	"io"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/kanisterio/kanister/pkg/field"
 	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
 )
 
+const (
+	partSizeFlagName    = "part-size"
+	concurrencyFlagName = "concurrency"
+	retriesFlagName     = "retries"
+	compressionFlagName = "compression"
+
+	progressLogInterval = 10 * time.Second
+)
+
 func newLocationPushCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "push <source>",
@@ -36,6 +50,10 @@ func newLocationPushCommand() *cobra.Command {
 			return runLocationPush(c, args)
 		},
 	}
+	cmd.Flags().Int64(partSizeFlagName, objectstore.DefaultUploadPartSize/(1024*1024), "Size of the first upload parts in MiB")
+	cmd.Flags().Int(concurrencyFlagName, objectstore.DefaultUploadConcurrency, "Number of parts uploaded in parallel")
+	cmd.Flags().Int(retriesFlagName, objectstore.DefaultUploadRetries, "Number of times a failed part is retried")
+	cmd.Flags().String(compressionFlagName, "", "Compress the data before upload (gzip or zstd)")
 	return cmd
 
 }
@@ -50,8 +68,39 @@ func runLocationPush(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	s := pathFlag(cmd)
+	opts, err := writeOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
 	ctx := context.Background()
-	return locationPush(ctx, p, s, source)
+	return locationPush(ctx, p, s, source, opts)
+}
+
+func writeOptionsFromFlags(cmd *cobra.Command) (location.WriteOptions, error) {
+	partSize, err := cmd.Flags().GetInt64(partSizeFlagName)
+	if err != nil {
+		return location.WriteOptions{}, err
+	}
+	concurrency, err := cmd.Flags().GetInt(concurrencyFlagName)
+	if err != nil {
+		return location.WriteOptions{}, err
+	}
+	retries, err := cmd.Flags().GetInt(retriesFlagName)
+	if err != nil {
+		return location.WriteOptions{}, err
+	}
+	compression, err := cmd.Flags().GetString(compressionFlagName)
+	if err != nil {
+		return location.WriteOptions{}, err
+	}
+	return location.WriteOptions{
+		Upload: objectstore.UploadOptions{
+			PartSize:    partSize * 1024 * 1024,
+			Concurrency: concurrency,
+			Retries:     retries,
+		},
+		Compression: location.Compression(compression),
+	}, nil
 }
 
 const usePipeParam = `-`
@@ -70,6 +119,31 @@ func sourceReader(source string) (io.Reader, error) {
 	return os.Stdin, nil
 }
 
-func locationPush(ctx context.Context, p *param.Profile, path string, source io.Reader) error {
-	return location.Write(ctx, source, *p, path)
+func locationPush(ctx context.Context, p *param.Profile, path string, source io.Reader, opts location.WriteOptions) error {
+	pl := &progressLogger{path: path}
+	opts.Upload.Progress = pl.log
+	if err := location.WriteWithOptions(ctx, source, *p, path, opts); err != nil {
+		return err
+	}
+	log.Print("Upload complete", field.M{"path": path, "bytes": pl.uploaded})
+	return nil
+}
+
+// progressLogger logs upload progress at most once every progressLogInterval.
+type progressLogger struct {
+	mu       sync.Mutex
+	path     string
+	uploaded int64
+	last     time.Time
+}
+
+func (p *progressLogger) log(uploaded int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uploaded = uploaded
+	if time.Since(p.last) < progressLogInterval {
+		return
+	}
+	p.last = time.Now()
+	log.Print("Upload in progress", field.M{"path": p.path, "bytes": uploaded})
 }
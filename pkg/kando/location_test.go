@@ -17,12 +17,16 @@ package kando
 import (
 	"bytes"
 	"context"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	. "gopkg.in/check.v1"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/location"
 	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/testutil"
 )
 
@@ -33,17 +37,17 @@ var _ = Suite(&LocationSuite{})
 const testContent = "test-content"
 
 func (s *LocationSuite) TestLocationObjectStore(c *C) {
-	location := crv1alpha1.Location{
+	loc := crv1alpha1.Location{
 		Type:   crv1alpha1.LocationTypeS3Compliant,
 		Bucket: testutil.TestS3BucketName,
 	}
-	p := testutil.ObjectStoreProfileOrSkip(c, objectstore.ProviderTypeS3, location)
+	p := testutil.ObjectStoreProfileOrSkip(c, objectstore.ProviderTypeS3, loc)
 	ctx := context.Background()
 	dir := c.MkDir()
 	path := filepath.Join(dir, "test-object1.txt")
 
 	source := bytes.NewBufferString(testContent)
-	err := locationPush(ctx, p, path, source)
+	err := locationPush(ctx, p, path, source, location.WriteOptions{})
 	c.Assert(err, IsNil)
 
 	target := bytes.NewBuffer(nil)
@@ -57,16 +61,94 @@ func (s *LocationSuite) TestLocationObjectStore(c *C) {
 
 	//test deleting dir with multiple artifacts
 	source = bytes.NewBufferString(testContent)
-	err = locationPush(ctx, p, path, source)
+	err = locationPush(ctx, p, path, source, location.WriteOptions{})
 	c.Assert(err, IsNil)
 
 	path = filepath.Join(dir, "test-object2.txt")
 
 	source = bytes.NewBufferString(testContent)
-	err = locationPush(ctx, p, path, source)
+	err = locationPush(ctx, p, path, source, location.WriteOptions{})
 	c.Assert(err, IsNil)
 
 	err = locationDelete(ctx, p, dir)
 	c.Assert(err, IsNil)
 
 }
+
+func (s *LocationSuite) TestLocationFilesystemMultipart(c *C) {
+	root := c.MkDir()
+	const bucket = "kando-tests"
+	err := os.Mkdir(filepath.Join(root, bucket), 0755)
+	c.Assert(err, IsNil)
+	p := &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Bucket:   bucket,
+			Endpoint: root,
+		},
+	}
+	ctx := context.Background()
+	path := "dir/test-object.txt"
+
+	// Larger than a single part
+	data := bytes.Repeat([]byte(testContent), 1024*1024)
+	err = locationPush(ctx, p, path, bytes.NewReader(data), location.WriteOptions{Upload: objectstore.UploadOptions{PartSize: 5 * 1024 * 1024, Concurrency: 2}})
+	c.Assert(err, IsNil)
+
+	target := bytes.NewBuffer(nil)
+	err = locationPull(ctx, p, path, target)
+	c.Assert(err, IsNil)
+	c.Assert(target.Bytes(), DeepEquals, data)
+
+	err = locationPush(ctx, p, path, bytes.NewReader(data), location.WriteOptions{Upload: objectstore.UploadOptions{PartSize: 1024}})
+	c.Assert(err, NotNil)
+
+	err = locationDelete(ctx, p, "dir")
+	c.Assert(err, IsNil)
+}
+
+func (s *LocationSuite) TestLocationFilesystemEncoded(c *C) {
+	root := c.MkDir()
+	const bucket = "kando-tests"
+	err := os.Mkdir(filepath.Join(root, bucket), 0755)
+	c.Assert(err, IsNil)
+	p := &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Bucket:   bucket,
+			Endpoint: root,
+		},
+		EncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+	}
+	ctx := context.Background()
+	path := "dir/test-object.txt"
+
+	data := bytes.Repeat([]byte(testContent), 1024)
+	err = locationPush(ctx, p, path, bytes.NewReader(data), location.WriteOptions{Compression: location.CompressionZstd})
+	c.Assert(err, IsNil)
+
+	// The stored object is neither plain text nor larger than the input
+	raw, err := ioutil.ReadFile(filepath.Join(root, bucket, path))
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Contains(raw, []byte(testContent)), Equals, false)
+	c.Assert(len(raw) < len(data), Equals, true)
+
+	target := bytes.NewBuffer(nil)
+	err = locationPull(ctx, p, path, target)
+	c.Assert(err, IsNil)
+	c.Assert(target.Bytes(), DeepEquals, data)
+
+	// Verification does not need the encryption key
+	err = locationVerify(ctx, &param.Profile{Location: p.Location}, path)
+	c.Assert(err, IsNil)
+	err = ioutil.WriteFile(filepath.Join(root, bucket, path), raw[1:], 0644)
+	c.Assert(err, IsNil)
+	err = locationVerify(ctx, p, path)
+	c.Assert(err, NotNil)
+
+	err = locationPush(ctx, p, path, bytes.NewReader(data), location.WriteOptions{Compression: "lz4"})
+	c.Assert(err, NotNil)
+
+	err = locationDelete(ctx, p, "dir")
+	c.Assert(err, IsNil)
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kando
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+func newLocationVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the checksum of an artifact in object storage",
+		// TODO: Example invocations
+		RunE: func(c *cobra.Command, args []string) error {
+			return runLocationVerify(c)
+		},
+	}
+	return cmd
+}
+
+func runLocationVerify(cmd *cobra.Command) error {
+	p, err := unmarshalProfileFlag(cmd)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	s := pathFlag(cmd)
+	ctx := context.Background()
+	return locationVerify(ctx, p, s)
+}
+
+func locationVerify(ctx context.Context, p *param.Profile, path string) error {
+	if err := location.Verify(ctx, *p, path); err != nil {
+		return err
+	}
+	log.Print("Checksum verified", field.M{"path": path})
+	return nil
+}
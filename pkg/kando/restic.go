@@ -0,0 +1,32 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kando
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newResticCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restic <command>",
+		Short: "Work with restic repositories created by Kanister functions",
+	}
+	cmd.AddCommand(newResticRestoreCommand())
+	cmd.PersistentFlags().StringP(pathFlagName, "s", "", "Specify the repository path, including the bucket (required)")
+	cmd.PersistentFlags().StringP(profileFlagName, "p", "", "Pass a Profile as a JSON string (required)")
+	_ = cmd.MarkPersistentFlagRequired(pathFlagName)
+	_ = cmd.MarkPersistentFlagRequired(profileFlagName)
+	return cmd
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kando
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	restoreTagFlagName           = "tag"
+	restoreTargetFlagName        = "target"
+	restoreIncludeFlagName       = "include"
+	restoreExcludeFlagName       = "exclude"
+	restoreTargetSubdirFlagName  = "target-subdir"
+	restoreOverwriteFlagName     = "overwrite"
+	restoreEncryptionKeyFlagName = "encryption-key"
+)
+
+func newResticRestoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [<snapshot-id>]",
+		Short: "Restore all or part of a restic snapshot to the local filesystem",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runResticRestore(c, args)
+		},
+	}
+	cmd.Flags().String(restoreTagFlagName, "", "Restore the latest snapshot with this tag instead of a snapshot ID")
+	cmd.Flags().String(restoreTargetFlagName, "/", "Directory to restore into")
+	cmd.Flags().StringSlice(restoreIncludeFlagName, nil, "Only restore paths matching this pattern (repeatable)")
+	cmd.Flags().StringSlice(restoreExcludeFlagName, nil, "Skip paths matching this pattern (repeatable, cannot be combined with --include)")
+	cmd.Flags().String(restoreTargetSubdirFlagName, "", "Relative directory below the target to restore into")
+	cmd.Flags().String(restoreOverwriteFlagName, "", "Policy for existing files: always, if-newer or never")
+	cmd.Flags().String(restoreEncryptionKeyFlagName, "", "Repository password (defaults to the Profile's repository password)")
+	return cmd
+}
+
+func runResticRestore(cmd *cobra.Command, args []string) error {
+	var id string
+	if len(args) == 1 {
+		id = args[0]
+	}
+	tag := cmd.Flag(restoreTagFlagName).Value.String()
+	if (id == "") == (tag == "") {
+		return errors.Errorf("Require one of a snapshot ID or --%s", restoreTagFlagName)
+	}
+	opts, err := restoreOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	p, err := unmarshalProfileFlag(cmd)
+	if err != nil {
+		return err
+	}
+	key := cmd.Flag(restoreEncryptionKeyFlagName).Value.String()
+	if key == "" {
+		key = restic.PasswordFromProfile(p)
+	}
+	target := cmd.Flag(restoreTargetFlagName).Value.String()
+	ctx := context.Background()
+	return resticRestore(ctx, p, pathFlag(cmd), id, tag, target, key, opts)
+}
+
+func restoreOptionsFromFlags(cmd *cobra.Command) (restic.RestoreOptions, error) {
+	include, err := cmd.Flags().GetStringSlice(restoreIncludeFlagName)
+	if err != nil {
+		return restic.RestoreOptions{}, err
+	}
+	exclude, err := cmd.Flags().GetStringSlice(restoreExcludeFlagName)
+	if err != nil {
+		return restic.RestoreOptions{}, err
+	}
+	opts := restic.RestoreOptions{
+		Include:      include,
+		Exclude:      exclude,
+		TargetSubdir: cmd.Flag(restoreTargetSubdirFlagName).Value.String(),
+		Overwrite:    restic.OverwritePolicy(cmd.Flag(restoreOverwriteFlagName).Value.String()),
+	}
+	return opts, opts.Validate()
+}
+
+func resticRestore(ctx context.Context, p *param.Profile, repository, id, tag, target, key string, opts restic.RestoreOptions) error {
+	passwordFile, err := writePasswordFile(key)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(passwordFile) // nolint: errcheck
+	var cmd []string
+	if tag != "" {
+		cmd, err = restic.RestoreCommandByTag(p, repository, tag, target, passwordFile, opts)
+	} else {
+		cmd, err = restic.RestoreCommandByID(p, repository, id, target, passwordFile, opts)
+	}
+	if err != nil {
+		return err
+	}
+	// The restic GCS backend reads credentials from the same file that
+	// Kanister functions write into their pods
+	if p.Location.Type == crv1alpha1.LocationTypeGCS {
+		if err := ioutil.WriteFile(consts.GoogleCloudCredsFilePath, []byte(p.Credential.KeyPair.Secret), 0600); err != nil {
+			return errors.Wrap(err, "Failed to write GCS credentials")
+		}
+		defer os.Remove(consts.GoogleCloudCredsFilePath) // nolint: errcheck
+	}
+	c := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return errors.Wrap(c.Run(), "Failed to restore backup")
+}
+
+// writePasswordFile writes the repository password to a temporary file that
+// only the current user can read, for restic's --password-file.
+func writePasswordFile(key string) (string, error) {
+	f, err := ioutil.TempFile("", "restic-password-")
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create password file")
+	}
+	if _, err = f.WriteString(key); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", errors.Wrap(err, "Failed to write password file")
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", errors.Wrap(err, "Failed to write password file")
+	}
+	return f.Name(), nil
+}
@@ -16,6 +16,7 @@ package kanister
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/Masterminds/semver"
@@ -40,6 +41,96 @@ type Func interface {
 	Exec(context.Context, param.TemplateParams, map[string]interface{}) (map[string]interface{}, error)
 }
 
+// FuncOutputs is implemented by Funcs that declare the keys of their output.
+type FuncOutputs interface {
+	Outputs() []string
+}
+
+// ArgType is the type of the value of a Func argument.
+type ArgType string
+
+const (
+	ArgTypeString      ArgType = "string"
+	ArgTypeInt         ArgType = "int"
+	ArgTypeBool        ArgType = "bool"
+	ArgTypeStringSlice ArgType = "[]string"
+	ArgTypeMap         ArgType = "map"
+)
+
+// FuncArgTypes is implemented by Funcs that declare the types of their
+// arguments.
+type FuncArgTypes interface {
+	ArgTypes() map[string]ArgType
+}
+
+// FuncSchema describes the arguments and output of a registered Func.
+type FuncSchema struct {
+	Name         string
+	Version      string
+	RequiredArgs []string
+	// ArgTypes maps arguments to their types. It is nil if the Func does not
+	// declare them.
+	ArgTypes map[string]ArgType
+	// Outputs are the keys of the output of the Func. It is nil if the Func
+	// does not declare them.
+	Outputs []string
+}
+
+// GetFuncSchema returns the schema of the Func registered with the name and
+// version. Like GetPhases, it falls back to the default version if the Func is
+// not registered with the requested version.
+func GetFuncSchema(name, version string) (*FuncSchema, error) {
+	defaultVersion, funcVersion, err := getFunctionVersion(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get function version")
+	}
+	funcMu.RLock()
+	defer funcMu.RUnlock()
+	if _, ok := funcs[name]; !ok {
+		return nil, errors.Errorf("Requested function {%s} has not been registered", name)
+	}
+	f, ok := funcs[name][*funcVersion]
+	if !ok {
+		if funcVersion.Equal(defaultVersion) {
+			return nil, errors.Errorf("Requested function {%s} has not been registered with version {%s}", name, DefaultVersion)
+		}
+		if f, ok = funcs[name][*defaultVersion]; !ok {
+			return nil, errors.Errorf("Requested function {%s} has not been registered with versions {%s} or {%s}", name, version, DefaultVersion)
+		}
+		funcVersion = defaultVersion
+	}
+	fs := &FuncSchema{
+		Name:         name,
+		Version:      funcVersion.Original(),
+		RequiredArgs: f.RequiredArgs(),
+	}
+	if fat, ok := f.(FuncArgTypes); ok {
+		fs.ArgTypes = fat.ArgTypes()
+	}
+	if fo, ok := f.(FuncOutputs); ok {
+		fs.Outputs = append([]string{}, fo.Outputs()...)
+	}
+	return fs, nil
+}
+
+// FuncVersions returns the versions the Func with the name is registered
+// with, in increasing order.
+func FuncVersions(name string) []string {
+	funcMu.RLock()
+	defer funcMu.RUnlock()
+	vs := make([]*semver.Version, 0, len(funcs[name]))
+	for v := range funcs[name] {
+		v := v
+		vs = append(vs, &v)
+	}
+	sort.Sort(semver.Collection(vs))
+	versions := make([]string, 0, len(vs))
+	for _, v := range vs {
+		versions = append(versions, v.Original())
+	}
+	return versions
+}
+
 // Register allows Funcs to be referenced by User Defined YAMLs
 func Register(f Func) error {
 	version := *semver.MustParse(DefaultVersion)
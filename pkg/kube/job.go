@@ -72,7 +72,7 @@ func NewJob(clientset kubernetes.Interface, jobName string, namespace string, se
 // Create creates the Job in Kubernetes.
 func (job *Job) Create() error {
 	falseVal := false
-	volumeMounts, podVolumes := createVolumeSpecs(job.vols)
+	volumeMounts, podVolumes := createVolumeSpecs(job.vols, false)
 	k8sJob := &batch.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: job.name,
@@ -121,11 +121,14 @@ func (job *Job) Create() error {
 	return nil
 }
 
-func createVolumeSpecs(vols map[string]string) (volumeMounts []v1.VolumeMount, podVolumes []v1.Volume) {
+// createVolumeSpecs mounts each PVC in vols. readOnly only applies to the
+// mounts: on a read-only claim, some volume plugins attach the volume
+// read-only, which conflicts with the application pod's read-write attachment.
+func createVolumeSpecs(vols map[string]string, readOnly bool) (volumeMounts []v1.VolumeMount, podVolumes []v1.Volume) {
 	// Build volume specs
 	for pvc, mountPath := range vols {
 		podVolName := fmt.Sprintf("vol-%s", pvc)
-		volumeMounts = append(volumeMounts, v1.VolumeMount{Name: podVolName, MountPath: mountPath})
+		volumeMounts = append(volumeMounts, v1.VolumeMount{Name: podVolName, MountPath: mountPath, ReadOnly: readOnly})
 		podVolumes = append(podVolumes,
 			v1.Volume{
 				Name: podVolName,
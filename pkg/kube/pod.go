@@ -38,13 +38,15 @@ type PodOptions struct {
 	Image              string
 	Command            []string
 	Volumes            map[string]string
+	ReadOnlyVolumes    bool // Mount all Volumes read-only
 	ServiceAccountName string
 	PodOverride        crv1alpha1.JSONMap
+	Labels             map[string]string
 }
 
 // CreatePod creates a pod with a single container based on the specified image
 func CreatePod(ctx context.Context, cli kubernetes.Interface, opts *PodOptions) (*v1.Pod, error) {
-	volumeMounts, podVolumes := createVolumeSpecs(opts.Volumes)
+	volumeMounts, podVolumes := createVolumeSpecs(opts.Volumes, opts.ReadOnlyVolumes)
 	defaultSpecs := v1.PodSpec{
 		Containers: []v1.Container{
 			{
@@ -73,6 +75,7 @@ func CreatePod(ctx context.Context, cli kubernetes.Interface, opts *PodOptions)
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: opts.GenerateName,
 			Namespace:    opts.Namespace,
+			Labels:       opts.Labels,
 		},
 		Spec: patchedSpecs,
 	}
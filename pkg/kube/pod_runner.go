@@ -40,6 +40,28 @@ func NewPodRunner(cli kubernetes.Interface, options *PodOptions) *PodRunner {
 	}
 }
 
+type podLabelsKey struct{}
+
+// ContextWithPodLabels returns a context that carries labels which are added
+// to every pod created by a PodRunner using that context.
+func ContextWithPodLabels(ctx context.Context, labels map[string]string) context.Context {
+	l := make(map[string]string, len(labels))
+	for k, v := range podLabelsFromContext(ctx) {
+		l[k] = v
+	}
+	for k, v := range labels {
+		l[k] = v
+	}
+	return context.WithValue(ctx, podLabelsKey{}, l)
+}
+
+func podLabelsFromContext(ctx context.Context) map[string]string {
+	if l, ok := ctx.Value(podLabelsKey{}).(map[string]string); ok {
+		return l
+	}
+	return nil
+}
+
 // Run will create a new Pod based on PodRunner contents and execute the given function
 func (p *PodRunner) Run(ctx context.Context, fn func(context.Context, *v1.Pod) (map[string]interface{}, error)) (map[string]interface{}, error) {
 	ctx, cancel := context.WithCancel(ctx)
@@ -47,7 +69,17 @@ func (p *PodRunner) Run(ctx context.Context, fn func(context.Context, *v1.Pod) (
 	if p.cli == nil || p.podOptions == nil {
 		return nil, errors.New("Pod Runner not initialized")
 	}
-	pod, err := CreatePod(ctx, p.cli, p.podOptions)
+	opts := *p.podOptions
+	if l := podLabelsFromContext(ctx); len(l) != 0 {
+		opts.Labels = make(map[string]string, len(l)+len(p.podOptions.Labels))
+		for k, v := range p.podOptions.Labels {
+			opts.Labels[k] = v
+		}
+		for k, v := range l {
+			opts.Labels[k] = v
+		}
+	}
+	pod, err := CreatePod(ctx, p.cli, &opts)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create pod")
 	}
@@ -60,6 +60,21 @@ func (s *PodRunnerTestSuite) TestPodRunnerContextCanceled(c *C) {
 	<-returned
 }
 
+func (s *PodRunnerTestSuite) TestPodRunnerContextLabels(c *C) {
+	ctx := ContextWithPodLabels(context.Background(), map[string]string{"a": "1"})
+	ctx = ContextWithPodLabels(ctx, map[string]string{"b": "2"})
+	cli := fake.NewSimpleClientset()
+	pr := NewPodRunner(cli, &PodOptions{
+		Namespace: "ns",
+		Labels:    map[string]string{"c": "3"},
+	})
+	_, err := pr.Run(ctx, func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
+		c.Assert(pod.GetLabels(), DeepEquals, map[string]string{"a": "1", "b": "2", "c": "3"})
+		return nil, nil
+	})
+	c.Assert(err, IsNil)
+}
+
 func makePodRunnerTestFunc(deleted chan struct{}) func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
 	return func(ctx context.Context, pod *v1.Pod) (map[string]interface{}, error) {
 		<-deleted
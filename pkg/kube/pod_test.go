@@ -102,6 +102,19 @@ func (s *PodSuite) TestPodWithVolumes(c *C) {
 	c.Assert(pod.Spec.Volumes, HasLen, 1)
 	c.Assert(pod.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ClaimName, Equals, "pvc-test")
 	c.Assert(pod.Spec.Containers[0].VolumeMounts[0].MountPath, Equals, "/mnt/data1")
+	c.Assert(pod.Spec.Containers[0].VolumeMounts[0].ReadOnly, Equals, false)
+
+	pod, err = CreatePod(ctx, cli, &PodOptions{
+		Namespace:       s.namespace,
+		GenerateName:    "test-",
+		Image:           "kanisterio/kanister-tools:0.23.0",
+		Command:         []string{"sh", "-c", "tail -f /dev/null"},
+		Volumes:         vols,
+		ReadOnlyVolumes: true,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(pod.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ReadOnly, Equals, false)
+	c.Assert(pod.Spec.Containers[0].VolumeMounts[0].ReadOnly, Equals, true)
 }
 
 func (s *PodSuite) TestGetPodLogs(c *C) {
@@ -0,0 +1,202 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+
+	snapshot "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	snapshotclient "github.com/kubernetes-csi/external-snapshotter/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+var _ Snapshotter = (*snapshotAlpha)(nil)
+
+type snapshotAlpha struct {
+	kubeCli kubernetes.Interface
+	snapCli snapshotclient.Interface
+}
+
+// NewSnapshotAlpha returns a Snapshotter for the v1alpha1 VolumeSnapshot API.
+func NewSnapshotAlpha(kubeCli kubernetes.Interface, snapCli snapshotclient.Interface) Snapshotter {
+	return &snapshotAlpha{kubeCli: kubeCli, snapCli: snapCli}
+}
+
+// Create creates a VolumeSnapshot and returns it or any error happened meanwhile.
+func (sna *snapshotAlpha) Create(ctx context.Context, name, namespace, volumeName string, snapshotClass *string, waitForReady bool) error {
+	if _, err := sna.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Get(volumeName, metav1.GetOptions{}); err != nil {
+		if k8errors.IsNotFound(err) {
+			return errors.Errorf("Failed to find PVC %s, Namespace %s", volumeName, namespace)
+		}
+		return errors.Errorf("Failed to query PVC %s, Namespace %s: %v", volumeName, namespace, err)
+	}
+
+	snap := &snapshot.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: snapshot.VolumeSnapshotSpec{
+			Source: &corev1.TypedLocalObjectReference{
+				Kind: pvcKind,
+				Name: volumeName,
+			},
+			VolumeSnapshotClassName: snapshotClass,
+		},
+	}
+
+	_, err := sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshots(namespace).Create(snap)
+	if err != nil {
+		return err
+	}
+
+	if !waitForReady {
+		return nil
+	}
+
+	err = sna.WaitOnReadyToUse(ctx, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	_, err = sna.Get(ctx, name, namespace)
+	return err
+}
+
+// Get will return the VolumeSnapshot in the namespace 'namespace' with given 'name'.
+func (sna *snapshotAlpha) Get(ctx context.Context, name, namespace string) (*snapshot.VolumeSnapshot, error) {
+	return sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshots(namespace).Get(name, metav1.GetOptions{})
+}
+
+// Delete will delete the VolumeSnapshot and returns any error as a result.
+func (sna *snapshotAlpha) Delete(ctx context.Context, name, namespace string) error {
+	if err := sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshots(namespace).Delete(name, &metav1.DeleteOptions{}); !apierrors.IsNotFound(err) {
+		return err
+	}
+	// If the Snapshot does not exist, that's an acceptable error and we ignore it
+	return nil
+}
+
+// Clone will clone the VolumeSnapshot to namespace 'cloneNamespace'.
+func (sna *snapshotAlpha) Clone(ctx context.Context, name, namespace, cloneName, cloneNamespace string, waitForReady bool) error {
+	return clone(ctx, sna, name, namespace, cloneName, cloneNamespace, waitForReady)
+}
+
+// GetSource will return the CSI source that backs the volume snapshot.
+func (sna *snapshotAlpha) GetSource(ctx context.Context, snapshotName, namespace string) (*Source, error) {
+	snap, err := sna.Get(ctx, snapshotName, namespace)
+	if err != nil {
+		return nil, errors.Errorf("Failed to get snapshot, VolumeSnapshot: %s, Error: %v", snapshotName, err)
+	}
+	cont, err := sna.getContent(ctx, snap.Spec.SnapshotContentName)
+	if err != nil {
+		return nil, errors.Errorf("Failed to get snapshot content, VolumeSnapshot: %s, VolumeSnapshotContent: %s, Error: %v", snapshotName, snap.Spec.SnapshotContentName, err)
+	}
+	src := &Source{
+		Handle:                  cont.Spec.CSI.SnapshotHandle,
+		Driver:                  cont.Spec.CSI.Driver,
+		RestoreSize:             cont.Spec.CSI.RestoreSize,
+		VolumeSnapshotClassName: cont.Spec.VolumeSnapshotClassName,
+	}
+	return src, nil
+}
+
+// CreateFromSource will create a 'Volumesnapshot' and 'VolumesnaphotContent' pair for the underlying snapshot source.
+func (sna *snapshotAlpha) CreateFromSource(ctx context.Context, source *Source, snapshotName, namespace string, waitForReady bool) error {
+	deletionPolicy, err := sna.getDeletionPolicyFromClass(*source.VolumeSnapshotClassName)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get DeletionPolicy from VolumeSnapshotClass")
+	}
+	contentName := snapshotName + "-content-" + string(uuid.NewUUID())
+	content := &snapshot.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: contentName,
+		},
+		Spec: snapshot.VolumeSnapshotContentSpec{
+			VolumeSnapshotSource: snapshot.VolumeSnapshotSource{
+				CSI: &snapshot.CSIVolumeSnapshotSource{
+					Driver:         source.Driver,
+					SnapshotHandle: source.Handle,
+				},
+			},
+			VolumeSnapshotRef: &corev1.ObjectReference{
+				Kind:      snapshotKind,
+				Namespace: namespace,
+				Name:      snapshotName,
+			},
+			VolumeSnapshotClassName: source.VolumeSnapshotClassName,
+			DeletionPolicy:          deletionPolicy,
+		},
+	}
+	snap := &snapshot.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: snapshotName,
+		},
+		Spec: snapshot.VolumeSnapshotSpec{
+			SnapshotContentName:     content.Name,
+			VolumeSnapshotClassName: content.Spec.VolumeSnapshotClassName,
+		},
+	}
+
+	content, err = sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshotContents().Create(content)
+	if err != nil {
+		return errors.Errorf("Failed to create content, VolumesnapshotContent: %s, Error: %v", contentName, err)
+	}
+	snap, err = sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshots(namespace).Create(snap)
+	if err != nil {
+		return errors.Errorf("Failed to create content, Volumesnapshot: %s, Error: %v", snapshotName, err)
+	}
+	if !waitForReady {
+		return nil
+	}
+
+	return sna.WaitOnReadyToUse(ctx, snap.Name, snap.Namespace)
+}
+
+// WaitOnReadyToUse will block until the Volumesnapshot in namespace 'namespace' with name 'snapshotName'
+// has status 'ReadyToUse' or 'ctx.Done()' is signalled.
+func (sna *snapshotAlpha) WaitOnReadyToUse(ctx context.Context, snapshotName, namespace string) error {
+	return poll.Wait(ctx, func(context.Context) (bool, error) {
+		snap, err := sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshots(namespace).Get(snapshotName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		// Error can be set while waiting for creation
+		if snap.Status.Error != nil {
+			return false, errors.New(snap.Status.Error.Message)
+		}
+		return (snap.Status.ReadyToUse && snap.Status.CreationTime != nil), nil
+	})
+}
+
+func (sna *snapshotAlpha) getContent(ctx context.Context, contentName string) (*snapshot.VolumeSnapshotContent, error) {
+	return sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshotContents().Get(contentName, metav1.GetOptions{})
+}
+
+func (sna *snapshotAlpha) getDeletionPolicyFromClass(snapClassName string) (*snapshot.DeletionPolicy, error) {
+	vsc, err := sna.snapCli.VolumesnapshotV1alpha1().VolumeSnapshotClasses().Get(snapClassName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to find VolumeSnapshotClass: %s", snapClassName)
+	}
+	return vsc.DeletionPolicy, nil
+}
@@ -0,0 +1,353 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+
+	snapshot "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+// The external-snapshotter version we depend on only provides a client for the
+// v1alpha1 API, so the v1beta1 and v1 resources are managed with the dynamic
+// client. The types below hold the fields of the resources that are used.
+// They are the same in v1beta1 and v1.
+
+type volumeSnapshotBeta struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              volumeSnapshotSpecBeta    `json:"spec"`
+	Status            *volumeSnapshotStatusBeta `json:"status,omitempty"`
+}
+
+type volumeSnapshotSpecBeta struct {
+	Source                  volumeSnapshotSourceBeta `json:"source"`
+	VolumeSnapshotClassName *string                  `json:"volumeSnapshotClassName,omitempty"`
+}
+
+type volumeSnapshotSourceBeta struct {
+	PersistentVolumeClaimName *string `json:"persistentVolumeClaimName,omitempty"`
+	VolumeSnapshotContentName *string `json:"volumeSnapshotContentName,omitempty"`
+}
+
+type volumeSnapshotStatusBeta struct {
+	BoundVolumeSnapshotContentName *string                  `json:"boundVolumeSnapshotContentName,omitempty"`
+	CreationTime                   *metav1.Time             `json:"creationTime,omitempty"`
+	ReadyToUse                     *bool                    `json:"readyToUse,omitempty"`
+	RestoreSize                    *resource.Quantity       `json:"restoreSize,omitempty"`
+	Error                          *volumeSnapshotErrorBeta `json:"error,omitempty"`
+}
+
+type volumeSnapshotErrorBeta struct {
+	Time    *metav1.Time `json:"time,omitempty"`
+	Message *string      `json:"message,omitempty"`
+}
+
+type volumeSnapshotContentBeta struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              volumeSnapshotContentSpecBeta    `json:"spec"`
+	Status            *volumeSnapshotContentStatusBeta `json:"status,omitempty"`
+}
+
+type volumeSnapshotContentSpecBeta struct {
+	VolumeSnapshotRef       corev1.ObjectReference          `json:"volumeSnapshotRef"`
+	DeletionPolicy          snapshot.DeletionPolicy         `json:"deletionPolicy"`
+	Driver                  string                          `json:"driver"`
+	VolumeSnapshotClassName *string                         `json:"volumeSnapshotClassName,omitempty"`
+	Source                  volumeSnapshotContentSourceBeta `json:"source"`
+}
+
+type volumeSnapshotContentSourceBeta struct {
+	VolumeHandle   *string `json:"volumeHandle,omitempty"`
+	SnapshotHandle *string `json:"snapshotHandle,omitempty"`
+}
+
+type volumeSnapshotContentStatusBeta struct {
+	SnapshotHandle *string `json:"snapshotHandle,omitempty"`
+	RestoreSize    *int64  `json:"restoreSize,omitempty"`
+}
+
+type volumeSnapshotClassBeta struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Driver            string                  `json:"driver"`
+	DeletionPolicy    snapshot.DeletionPolicy `json:"deletionPolicy"`
+}
+
+var _ Snapshotter = (*snapshotBeta)(nil)
+
+type snapshotBeta struct {
+	kubeCli kubernetes.Interface
+	dynCli  dynamic.Interface
+	version string
+}
+
+// NewSnapshotBeta returns a Snapshotter for the v1beta1 VolumeSnapshot API.
+func NewSnapshotBeta(kubeCli kubernetes.Interface, dynCli dynamic.Interface) Snapshotter {
+	return &snapshotBeta{kubeCli: kubeCli, dynCli: dynCli, version: VersionBeta}
+}
+
+// Create creates a VolumeSnapshot and returns it or any error happened meanwhile.
+func (snb *snapshotBeta) Create(ctx context.Context, name, namespace, volumeName string, snapshotClass *string, waitForReady bool) error {
+	if _, err := snb.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Get(volumeName, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return errors.Errorf("Failed to find PVC %s, Namespace %s", volumeName, namespace)
+		}
+		return errors.Errorf("Failed to query PVC %s, Namespace %s: %v", volumeName, namespace, err)
+	}
+
+	snap := &volumeSnapshotBeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: volumeSnapshotSpecBeta{
+			Source: volumeSnapshotSourceBeta{
+				PersistentVolumeClaimName: &volumeName,
+			},
+			VolumeSnapshotClassName: snapshotClass,
+		},
+	}
+	if err := snb.create(snb.resource("volumesnapshots"), namespace, snapshotKind, snap); err != nil {
+		return err
+	}
+
+	if !waitForReady {
+		return nil
+	}
+
+	if err := snb.WaitOnReadyToUse(ctx, name, namespace); err != nil {
+		return err
+	}
+
+	_, err := snb.Get(ctx, name, namespace)
+	return err
+}
+
+// Get will return the VolumeSnapshot in the namespace 'namespace' with given 'name'.
+func (snb *snapshotBeta) Get(ctx context.Context, name, namespace string) (*snapshot.VolumeSnapshot, error) {
+	snap, err := snb.getSnapshot(name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return toAlphaSnapshot(snap), nil
+}
+
+// Delete will delete the VolumeSnapshot and returns any error as a result.
+func (snb *snapshotBeta) Delete(ctx context.Context, name, namespace string) error {
+	if err := snb.dynCli.Resource(snb.resource("volumesnapshots")).Namespace(namespace).Delete(name, &metav1.DeleteOptions{}); !apierrors.IsNotFound(err) {
+		return err
+	}
+	// If the Snapshot does not exist, that's an acceptable error and we ignore it
+	return nil
+}
+
+// Clone will clone the VolumeSnapshot to namespace 'cloneNamespace'.
+func (snb *snapshotBeta) Clone(ctx context.Context, name, namespace, cloneName, cloneNamespace string, waitForReady bool) error {
+	return clone(ctx, snb, name, namespace, cloneName, cloneNamespace, waitForReady)
+}
+
+// GetSource will return the CSI source that backs the volume snapshot.
+func (snb *snapshotBeta) GetSource(ctx context.Context, snapshotName, namespace string) (*Source, error) {
+	snap, err := snb.Get(ctx, snapshotName, namespace)
+	if err != nil {
+		return nil, errors.Errorf("Failed to get snapshot, VolumeSnapshot: %s, Error: %v", snapshotName, err)
+	}
+	cont := &volumeSnapshotContentBeta{}
+	if err := snb.get(snb.resource("volumesnapshotcontents"), "", snap.Spec.SnapshotContentName, cont); err != nil {
+		return nil, errors.Errorf("Failed to get snapshot content, VolumeSnapshot: %s, VolumeSnapshotContent: %s, Error: %v", snapshotName, snap.Spec.SnapshotContentName, err)
+	}
+	src := &Source{
+		Driver:                  cont.Spec.Driver,
+		VolumeSnapshotClassName: cont.Spec.VolumeSnapshotClassName,
+	}
+	// Dynamically provisioned contents only record the handle in their
+	// status, pre-provisioned ones in their source.
+	switch {
+	case cont.Status != nil && cont.Status.SnapshotHandle != nil:
+		src.Handle = *cont.Status.SnapshotHandle
+	case cont.Spec.Source.SnapshotHandle != nil:
+		src.Handle = *cont.Spec.Source.SnapshotHandle
+	}
+	if cont.Status != nil {
+		src.RestoreSize = cont.Status.RestoreSize
+	}
+	return src, nil
+}
+
+// CreateFromSource will create a 'Volumesnapshot' and 'VolumesnaphotContent' pair for the underlying snapshot source.
+func (snb *snapshotBeta) CreateFromSource(ctx context.Context, source *Source, snapshotName, namespace string, waitForReady bool) error {
+	vsc := &volumeSnapshotClassBeta{}
+	if err := snb.get(snb.resource("volumesnapshotclasses"), "", *source.VolumeSnapshotClassName, vsc); err != nil {
+		return errors.Wrapf(err, "Failed to find VolumeSnapshotClass: %s", *source.VolumeSnapshotClassName)
+	}
+	contentName := snapshotName + "-content-" + string(uuid.NewUUID())
+	content := &volumeSnapshotContentBeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: contentName,
+		},
+		Spec: volumeSnapshotContentSpecBeta{
+			VolumeSnapshotRef: corev1.ObjectReference{
+				Kind:      snapshotKind,
+				Namespace: namespace,
+				Name:      snapshotName,
+			},
+			DeletionPolicy:          vsc.DeletionPolicy,
+			Driver:                  source.Driver,
+			VolumeSnapshotClassName: source.VolumeSnapshotClassName,
+			Source: volumeSnapshotContentSourceBeta{
+				SnapshotHandle: &source.Handle,
+			},
+		},
+	}
+	snap := &volumeSnapshotBeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: namespace,
+		},
+		Spec: volumeSnapshotSpecBeta{
+			Source: volumeSnapshotSourceBeta{
+				VolumeSnapshotContentName: &contentName,
+			},
+			VolumeSnapshotClassName: source.VolumeSnapshotClassName,
+		},
+	}
+
+	if err := snb.create(snb.resource("volumesnapshotcontents"), "", "VolumeSnapshotContent", content); err != nil {
+		return errors.Errorf("Failed to create content, VolumesnapshotContent: %s, Error: %v", contentName, err)
+	}
+	if err := snb.create(snb.resource("volumesnapshots"), namespace, snapshotKind, snap); err != nil {
+		return errors.Errorf("Failed to create content, Volumesnapshot: %s, Error: %v", snapshotName, err)
+	}
+	if !waitForReady {
+		return nil
+	}
+
+	return snb.WaitOnReadyToUse(ctx, snapshotName, namespace)
+}
+
+// WaitOnReadyToUse will block until the Volumesnapshot in namespace 'namespace' with name 'snapshotName'
+// has status 'ReadyToUse' or 'ctx.Done()' is signalled.
+func (snb *snapshotBeta) WaitOnReadyToUse(ctx context.Context, snapshotName, namespace string) error {
+	return poll.Wait(ctx, func(context.Context) (bool, error) {
+		snap, err := snb.getSnapshot(snapshotName, namespace)
+		if err != nil {
+			return false, err
+		}
+		if snap.Status == nil {
+			return false, nil
+		}
+		// Error can be set while waiting for creation
+		if snap.Status.Error != nil && snap.Status.Error.Message != nil {
+			return false, errors.New(*snap.Status.Error.Message)
+		}
+		return (snap.Status.ReadyToUse != nil && *snap.Status.ReadyToUse && snap.Status.CreationTime != nil), nil
+	})
+}
+
+func (snb *snapshotBeta) resource(name string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: GroupName, Version: snb.version, Resource: name}
+}
+
+func (snb *snapshotBeta) getSnapshot(name, namespace string) (*volumeSnapshotBeta, error) {
+	snap := &volumeSnapshotBeta{}
+	if err := snb.get(snb.resource("volumesnapshots"), namespace, name, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// get fetches the resource with the dynamic client and converts it into 'out'.
+// API errors are returned unwrapped so that they can be checked with the
+// apierrors functions.
+func (snb *snapshotBeta) get(gvr schema.GroupVersionResource, namespace, name string, out interface{}) error {
+	u, err := snb.dynCli.Resource(gvr).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), out); err != nil {
+		return errors.Wrapf(err, "Failed to convert %s %s", gvr.Resource, name)
+	}
+	return nil
+}
+
+// create converts the object into its unstructured form and creates it with
+// the dynamic client.
+func (snb *snapshotBeta) create(gvr schema.GroupVersionResource, namespace, kind string, obj interface{}) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to convert %s", kind)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(schema.GroupVersion{Group: GroupName, Version: snb.version}.String())
+	u.SetKind(kind)
+	_, err = snb.dynCli.Resource(gvr).Namespace(namespace).Create(u, metav1.CreateOptions{})
+	return err
+}
+
+// toAlphaSnapshot converts a v1beta1 or v1 VolumeSnapshot into the v1alpha1
+// representation returned by Snapshotter.Get.
+func toAlphaSnapshot(snap *volumeSnapshotBeta) *snapshot.VolumeSnapshot {
+	out := &snapshot.VolumeSnapshot{
+		TypeMeta:   snap.TypeMeta,
+		ObjectMeta: snap.ObjectMeta,
+		Spec: snapshot.VolumeSnapshotSpec{
+			VolumeSnapshotClassName: snap.Spec.VolumeSnapshotClassName,
+		},
+	}
+	if pvc := snap.Spec.Source.PersistentVolumeClaimName; pvc != nil {
+		out.Spec.Source = &corev1.TypedLocalObjectReference{
+			Kind: pvcKind,
+			Name: *pvc,
+		}
+	}
+	if content := snap.Spec.Source.VolumeSnapshotContentName; content != nil {
+		out.Spec.SnapshotContentName = *content
+	}
+	if snap.Status == nil {
+		return out
+	}
+	if content := snap.Status.BoundVolumeSnapshotContentName; content != nil {
+		out.Spec.SnapshotContentName = *content
+	}
+	out.Status.CreationTime = snap.Status.CreationTime
+	out.Status.RestoreSize = snap.Status.RestoreSize
+	out.Status.ReadyToUse = snap.Status.ReadyToUse != nil && *snap.Status.ReadyToUse
+	if e := snap.Status.Error; e != nil {
+		out.Status.Error = &storage.VolumeError{}
+		if e.Time != nil {
+			out.Status.Error.Time = *e.Time
+		}
+		if e.Message != nil {
+			out.Status.Error.Message = *e.Message
+		}
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot_test
+
+import (
+	"context"
+	"strings"
+
+	snapshotfake "github.com/kubernetes-csi/external-snapshotter/pkg/client/clientset/versioned/fake"
+	. "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+)
+
+type SnapshotBetaTestSuite struct{}
+
+var _ = Suite(&SnapshotBetaTestSuite{})
+
+func gvr(version, resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: snapshot.GroupName, Version: version, Resource: resource}
+}
+
+func unstructuredObj(version, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: fields}
+	u.SetAPIVersion(snapshot.GroupName + "/" + version)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func (s *SnapshotBetaTestSuite) TestNewSnapshotter(c *C) {
+	for _, tc := range []struct {
+		versions []string
+		expected string
+	}{
+		{versions: []string{snapshot.VersionAlpha}, expected: snapshot.VersionAlpha},
+		{versions: []string{snapshot.VersionAlpha, snapshot.VersionBeta}, expected: snapshot.VersionBeta},
+		{versions: []string{snapshot.VersionBeta, snapshot.VersionStable}, expected: snapshot.VersionStable},
+		{versions: nil},
+	} {
+		cli := fake.NewSimpleClientset(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: defaultNamespace},
+		})
+		for _, v := range tc.versions {
+			cli.Resources = append(cli.Resources, &metav1.APIResourceList{GroupVersion: snapshot.GroupName + "/" + v})
+		}
+		snapCli := snapshotfake.NewSimpleClientset()
+		dynCli := dynfake.NewSimpleDynamicClient(runtime.NewScheme())
+		ss, err := snapshot.NewSnapshotter(cli, snapCli, dynCli)
+		if tc.expected == "" {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		err = ss.Create(context.Background(), "snap", defaultNamespace, "pvc", &fakeClass, false)
+		c.Assert(err, IsNil)
+		if tc.expected == snapshot.VersionAlpha {
+			_, err = snapCli.VolumesnapshotV1alpha1().VolumeSnapshots(defaultNamespace).Get("snap", metav1.GetOptions{})
+		} else {
+			_, err = dynCli.Resource(gvr(tc.expected, "volumesnapshots")).Namespace(defaultNamespace).Get("snap", metav1.GetOptions{})
+		}
+		c.Assert(err, IsNil, Commentf("%v", tc.versions))
+	}
+}
+
+func (s *SnapshotBetaTestSuite) TestVolumeSnapshotFake(c *C) {
+	for _, tc := range []struct {
+		version     string
+		snapshotter func(*fake.Clientset, *dynfake.FakeDynamicClient) snapshot.Snapshotter
+	}{
+		{
+			version: snapshot.VersionBeta,
+			snapshotter: func(cli *fake.Clientset, dynCli *dynfake.FakeDynamicClient) snapshot.Snapshotter {
+				return snapshot.NewSnapshotBeta(cli, dynCli)
+			},
+		},
+		{
+			version: snapshot.VersionStable,
+			snapshotter: func(cli *fake.Clientset, dynCli *dynfake.FakeDynamicClient) snapshot.Snapshotter {
+				return snapshot.NewSnapshotStable(cli, dynCli)
+			},
+		},
+	} {
+		ctx := context.Background()
+		cli := fake.NewSimpleClientset(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1-fake", Namespace: defaultNamespace},
+		})
+		dynCli := dynfake.NewSimpleDynamicClient(runtime.NewScheme())
+		ss := tc.snapshotter(cli, dynCli)
+
+		err := ss.Create(ctx, "snap-1-fake", defaultNamespace, "pvc-1-fake", &fakeClass, false)
+		c.Assert(err, IsNil)
+		snap, err := ss.Get(ctx, "snap-1-fake", defaultNamespace)
+		c.Assert(err, IsNil)
+		c.Assert(snap.Name, Equals, "snap-1-fake")
+		c.Assert(snap.Spec.Source.Name, Equals, "pvc-1-fake")
+		c.Assert(*snap.Spec.VolumeSnapshotClassName, Equals, fakeClass)
+		c.Assert(snap.Status.ReadyToUse, Equals, false)
+		err = ss.Create(ctx, "snap-1-fake", defaultNamespace, "pvc-1-fake", &fakeClass, false)
+		c.Assert(err, NotNil)
+		err = ss.Create(ctx, "snap-2-fake", defaultNamespace, "pvc-2-fake", &fakeClass, false)
+		c.Assert(err, NotNil)
+		c.Assert(ss.Delete(ctx, snap.Name, snap.Namespace), IsNil)
+		c.Assert(ss.Delete(ctx, snap.Name, snap.Namespace), IsNil)
+
+		// Clone a snapshot bound to a dynamically provisioned content.
+		objs := []struct {
+			resource string
+			obj      *unstructured.Unstructured
+		}{
+			{
+				resource: "volumesnapshotclasses",
+				obj: unstructuredObj(tc.version, "VolumeSnapshotClass", "", fakeClass, map[string]interface{}{
+					"driver":         fakeDriver,
+					"deletionPolicy": "Retain",
+				}),
+			},
+			{
+				resource: "volumesnapshotcontents",
+				obj: unstructuredObj(tc.version, "VolumeSnapshotContent", "", "snapcontent-1-fake", map[string]interface{}{
+					"spec": map[string]interface{}{
+						"driver":                  fakeDriver,
+						"deletionPolicy":          "Delete",
+						"volumeSnapshotClassName": fakeClass,
+						"source":                  map[string]interface{}{"volumeHandle": "vol-1"},
+						"volumeSnapshotRef":       map[string]interface{}{"name": "snap-3-fake", "namespace": defaultNamespace},
+					},
+					"status": map[string]interface{}{
+						"snapshotHandle": fakeSnapshotHandle,
+						"restoreSize":    int64(1 << 30),
+					},
+				}),
+			},
+			{
+				resource: "volumesnapshots",
+				obj: unstructuredObj(tc.version, "VolumeSnapshot", defaultNamespace, "snap-3-fake", map[string]interface{}{
+					"spec": map[string]interface{}{
+						"source":                  map[string]interface{}{"persistentVolumeClaimName": "pvc-1-fake"},
+						"volumeSnapshotClassName": fakeClass,
+					},
+					"status": map[string]interface{}{
+						"boundVolumeSnapshotContentName": "snapcontent-1-fake",
+						"creationTime":                   metav1.Now().UTC().Format("2006-01-02T15:04:05Z"),
+						"readyToUse":                     true,
+						"restoreSize":                    "1Gi",
+					},
+				}),
+			},
+		}
+		for _, o := range objs {
+			_, err = dynCli.Resource(gvr(tc.version, o.resource)).Namespace(o.obj.GetNamespace()).Create(o.obj, metav1.CreateOptions{})
+			c.Assert(err, IsNil)
+		}
+
+		snap, err = ss.Get(ctx, "snap-3-fake", defaultNamespace)
+		c.Assert(err, IsNil)
+		c.Assert(snap.Spec.SnapshotContentName, Equals, "snapcontent-1-fake")
+		c.Assert(snap.Status.ReadyToUse, Equals, true)
+		c.Assert(snap.Status.RestoreSize.String(), Equals, "1Gi")
+		c.Assert(ss.WaitOnReadyToUse(ctx, "snap-3-fake", defaultNamespace), IsNil)
+
+		src, err := ss.GetSource(ctx, "snap-3-fake", defaultNamespace)
+		c.Assert(err, IsNil)
+		c.Assert(src.Handle, Equals, fakeSnapshotHandle)
+		c.Assert(src.Driver, Equals, fakeDriver)
+		c.Assert(*src.RestoreSize, Equals, int64(1<<30))
+
+		err = ss.Clone(ctx, "snap-3-fake", defaultNamespace, "clone-1", "new-ns", false)
+		c.Assert(err, IsNil)
+		err = ss.Clone(ctx, "snap-3-fake", defaultNamespace, "clone-1", "new-ns", false)
+		c.Assert(err, NotNil)
+		clone, err := ss.Get(ctx, "clone-1", "new-ns")
+		c.Assert(err, IsNil)
+		c.Assert(strings.HasPrefix(clone.Spec.SnapshotContentName, "clone-1"), Equals, true)
+		content, err := dynCli.Resource(gvr(tc.version, "volumesnapshotcontents")).Get(clone.Spec.SnapshotContentName, metav1.GetOptions{})
+		c.Assert(err, IsNil)
+		policy, _, _ := unstructured.NestedString(content.Object, "spec", "deletionPolicy")
+		c.Assert(policy, Equals, "Retain")
+		handle, _, _ := unstructured.NestedString(content.Object, "spec", "source", "snapshotHandle")
+		c.Assert(handle, Equals, fakeSnapshotHandle)
+	}
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package snapshot
+package snapshot_test
 
 import (
 	"context"
@@ -21,7 +21,7 @@ import (
 	"testing"
 	"time"
 
-	snapshot "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
 	snapshotclient "github.com/kubernetes-csi/external-snapshotter/pkg/client/clientset/versioned"
 	snapshotfake "github.com/kubernetes-csi/external-snapshotter/pkg/client/clientset/versioned/fake"
 	. "gopkg.in/check.v1"
@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
 	"github.com/kanisterio/kanister/pkg/kube/volume"
 	"github.com/kanisterio/kanister/pkg/poll"
 )
@@ -44,6 +45,7 @@ type SnapshotTestSuite struct {
 	targetNamespace string
 	cli             kubernetes.Interface
 	snapCli         snapshotclient.Interface
+	snapshotter     snapshot.Snapshotter
 	snapshotClass   *string
 	storageClassCSI *string
 }
@@ -75,6 +77,11 @@ func (s *SnapshotTestSuite) SetUpSuite(c *C) {
 	c.Assert(err, IsNil)
 	s.snapCli = sc
 
+	dynCli, err := kube.NewDynamicClient()
+	c.Assert(err, IsNil)
+	s.snapshotter, err = snapshot.NewSnapshotter(cli, sc, dynCli)
+	c.Assert(err, IsNil)
+
 	vscs, err := sc.VolumesnapshotV1alpha1().VolumeSnapshotClasses().List(metav1.ListOptions{})
 	if err != nil && !k8errors.IsNotFound(err) {
 		c.Logf("Failed to query VolumeSnapshotClass, skipping test. Error: %v", err)
@@ -117,6 +124,7 @@ func (s *SnapshotTestSuite) TestVolumeSnapshotFake(c *C) {
 	volName := "pvc-1-fake"
 	fakeCli := fake.NewSimpleClientset()
 	fakeSnapCli := snapshotfake.NewSimpleClientset()
+	fakeSs := snapshot.NewSnapshotAlpha(fakeCli, fakeSnapCli)
 
 	size, err := resource.ParseQuantity("1Gi")
 	c.Assert(err, IsNil)
@@ -135,17 +143,17 @@ func (s *SnapshotTestSuite) TestVolumeSnapshotFake(c *C) {
 	_, err = fakeCli.CoreV1().PersistentVolumeClaims(defaultNamespace).Create(pvc)
 	c.Assert(err, IsNil)
 
-	err = Create(context.Background(), fakeCli, fakeSnapCli, snapshotName, defaultNamespace, volName, &fakeClass, false)
+	err = fakeSs.Create(context.Background(), snapshotName, defaultNamespace, volName, &fakeClass, false)
 	c.Assert(err, IsNil)
-	snap, err := Get(context.Background(), fakeSnapCli, snapshotName, defaultNamespace)
+	snap, err := fakeSs.Get(context.Background(), snapshotName, defaultNamespace)
 	c.Assert(err, IsNil)
 	c.Assert(snap.Name, Equals, snapshotName)
 
-	err = Create(context.Background(), fakeCli, fakeSnapCli, snapshotName, defaultNamespace, volName, &fakeClass, false)
+	err = fakeSs.Create(context.Background(), snapshotName, defaultNamespace, volName, &fakeClass, false)
 	c.Assert(err, NotNil)
-	err = Delete(context.Background(), fakeSnapCli, snap.Name, snap.Namespace)
+	err = fakeSs.Delete(context.Background(), snap.Name, snap.Namespace)
 	c.Assert(err, IsNil)
-	err = Delete(context.Background(), fakeSnapCli, snap.Name, snap.Namespace)
+	err = fakeSs.Delete(context.Background(), snap.Name, snap.Namespace)
 	c.Assert(err, IsNil)
 }
 
@@ -153,8 +161,8 @@ func (s *SnapshotTestSuite) TestVolumeSnapshotCloneFake(c *C) {
 	fakeSnapshotName := "snap-1-fake"
 	fakeContentName := "snapcontent-1-fake"
 
-	dp := snapshot.VolumeSnapshotContentDelete
-	vsc := &snapshot.VolumeSnapshotClass{
+	dp := v1alpha1.VolumeSnapshotContentDelete
+	vsc := &v1alpha1.VolumeSnapshotClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fakeClass,
 		},
@@ -162,13 +170,13 @@ func (s *SnapshotTestSuite) TestVolumeSnapshotCloneFake(c *C) {
 		DeletionPolicy: &dp,
 	}
 
-	content := &snapshot.VolumeSnapshotContent{
+	content := &v1alpha1.VolumeSnapshotContent{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fakeContentName,
 		},
-		Spec: snapshot.VolumeSnapshotContentSpec{
-			VolumeSnapshotSource: snapshot.VolumeSnapshotSource{
-				CSI: &snapshot.CSIVolumeSnapshotSource{
+		Spec: v1alpha1.VolumeSnapshotContentSpec{
+			VolumeSnapshotSource: v1alpha1.VolumeSnapshotSource{
+				CSI: &v1alpha1.CSIVolumeSnapshotSource{
 					Driver:         fakeDriver,
 					SnapshotHandle: fakeSnapshotHandle,
 				},
@@ -181,22 +189,23 @@ func (s *SnapshotTestSuite) TestVolumeSnapshotCloneFake(c *C) {
 		},
 	}
 	ctime := metav1.Now()
-	snap := &snapshot.VolumeSnapshot{
+	snap := &v1alpha1.VolumeSnapshot{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fakeSnapshotName,
 			Namespace: defaultNamespace,
 		},
-		Spec: snapshot.VolumeSnapshotSpec{
+		Spec: v1alpha1.VolumeSnapshotSpec{
 			SnapshotContentName:     fakeContentName,
 			VolumeSnapshotClassName: &fakeClass,
 		},
-		Status: snapshot.VolumeSnapshotStatus{
+		Status: v1alpha1.VolumeSnapshotStatus{
 			ReadyToUse:   true,
 			CreationTime: &ctime,
 		},
 	}
 
 	snapCli := snapshotfake.NewSimpleClientset()
+	fakeSs := snapshot.NewSnapshotAlpha(fake.NewSimpleClientset(), snapCli)
 	fakeTargetNamespace := "new-ns"
 	fakeClone := "clone-1"
 
@@ -207,13 +216,13 @@ func (s *SnapshotTestSuite) TestVolumeSnapshotCloneFake(c *C) {
 	_, err = snapCli.VolumesnapshotV1alpha1().VolumeSnapshotContents().Create(content)
 	c.Assert(err, IsNil)
 
-	_, err = Get(context.Background(), snapCli, fakeSnapshotName, defaultNamespace)
+	_, err = fakeSs.Get(context.Background(), fakeSnapshotName, defaultNamespace)
 	c.Assert(err, IsNil)
 
-	err = Clone(context.Background(), snapCli, fakeSnapshotName, defaultNamespace, fakeClone, fakeTargetNamespace, false)
+	err = fakeSs.Clone(context.Background(), fakeSnapshotName, defaultNamespace, fakeClone, fakeTargetNamespace, false)
 	c.Assert(err, IsNil)
 
-	clone, err := Get(context.Background(), snapCli, fakeClone, fakeTargetNamespace)
+	clone, err := fakeSs.Get(context.Background(), fakeClone, fakeTargetNamespace)
 	c.Assert(err, IsNil)
 
 	cloneContent, err := snapCli.VolumesnapshotV1alpha1().VolumeSnapshotContents().Get(clone.Spec.SnapshotContentName, metav1.GetOptions{})
@@ -263,23 +272,23 @@ func (s *SnapshotTestSuite) TestVolumeSnapshot(c *C) {
 
 	snapshotName := snapshotNamePrefix + strconv.Itoa(int(time.Now().UnixNano()))
 	wait := true
-	err = Create(ctx, s.cli, s.snapCli, snapshotName, s.sourceNamespace, pvc.Name, s.snapshotClass, wait)
+	err = s.snapshotter.Create(ctx, snapshotName, s.sourceNamespace, pvc.Name, s.snapshotClass, wait)
 	c.Assert(err, IsNil)
 
-	snap, err := Get(ctx, s.snapCli, snapshotName, s.sourceNamespace)
+	snap, err := s.snapshotter.Get(ctx, snapshotName, s.sourceNamespace)
 	c.Assert(err, IsNil)
 	c.Assert(snap.Name, Equals, snapshotName)
 	c.Assert(snap.Status.ReadyToUse, Equals, true)
 
-	err = Create(ctx, s.cli, s.snapCli, snapshotName, s.sourceNamespace, pvc.Name, s.snapshotClass, wait)
+	err = s.snapshotter.Create(ctx, snapshotName, s.sourceNamespace, pvc.Name, s.snapshotClass, wait)
 	c.Assert(err, NotNil)
 
 	snapshotCloneName := snapshotName + "-clone"
 	volumeCloneName := pvc.Name + "-clone"
-	err = Clone(ctx, s.snapCli, snapshotName, s.sourceNamespace, snapshotCloneName, s.targetNamespace, wait)
+	err = s.snapshotter.Clone(ctx, snapshotName, s.sourceNamespace, snapshotCloneName, s.targetNamespace, wait)
 	c.Assert(err, IsNil)
 
-	_, err = volume.CreatePVCFromSnapshot(ctx, s.cli, s.snapCli, s.targetNamespace, volumeCloneName, "", snapshotCloneName, nil)
+	_, err = volume.CreatePVCFromSnapshot(ctx, s.cli, s.snapshotter, s.targetNamespace, volumeCloneName, "", snapshotCloneName, nil)
 	c.Assert(err, IsNil)
 	_ = poll.Wait(ctx, func(ctx context.Context) (bool, error) {
 		pvc, err = s.cli.CoreV1().PersistentVolumeClaims(s.targetNamespace).Get(volumeCloneName, metav1.GetOptions{})
@@ -292,7 +301,7 @@ func (s *SnapshotTestSuite) TestVolumeSnapshot(c *C) {
 	// Try with a greater restore size.
 	sizeNew := 2
 	volumeCloneName += "-2"
-	_, err = volume.CreatePVCFromSnapshot(ctx, s.cli, s.snapCli, s.targetNamespace, volumeCloneName, "", snapshotCloneName, &sizeNew)
+	_, err = volume.CreatePVCFromSnapshot(ctx, s.cli, s.snapshotter, s.targetNamespace, volumeCloneName, "", snapshotCloneName, &sizeNew)
 	c.Assert(err, IsNil)
 	_ = poll.Wait(ctx, func(ctx context.Context) (bool, error) {
 		pvc, err = s.cli.CoreV1().PersistentVolumeClaims(s.targetNamespace).Get(volumeCloneName, metav1.GetOptions{})
@@ -302,10 +311,10 @@ func (s *SnapshotTestSuite) TestVolumeSnapshot(c *C) {
 		return pvc.Status.Phase == corev1.ClaimBound, nil
 	})
 
-	err = Delete(ctx, s.snapCli, snap.Name, snap.Namespace)
+	err = s.snapshotter.Delete(ctx, snap.Name, snap.Namespace)
 	c.Assert(err, IsNil)
 
-	err = Delete(ctx, s.snapCli, snap.Name, snap.Namespace)
+	err = s.snapshotter.Delete(ctx, snap.Name, snap.Namespace)
 	c.Assert(err, NotNil)
 
 }
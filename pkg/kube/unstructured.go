@@ -58,9 +58,5 @@ func ListUnstructuredObjectWithCli(cli dynamic.Interface, resource schema.GroupV
 }
 
 func client() (dynamic.Interface, error) {
-	config, err := LoadConfig()
-	if err != nil {
-		return nil, err
-	}
-	return dynamic.NewForConfig(config)
+	return NewDynamicClient()
 }
@@ -22,7 +22,6 @@ import (
 	"strings"
 	"time"
 
-	snapshotclient "github.com/kubernetes-csi/external-snapshotter/pkg/client/clientset/versioned"
 	"github.com/pkg/errors"
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,6 +30,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
 	"github.com/kanisterio/kanister/pkg/poll"
 )
 
@@ -44,8 +44,18 @@ const (
 	// NoPVCNameSpecified is used by the caller to indicate that the PVC name
 	// should be auto-generated
 	NoPVCNameSpecified = ""
+	// VSphereCSIDriverName is the name of the vSphere CSI driver which
+	// provisions FCDs
+	VSphereCSIDriverName = "csi.vsphere.vmware.com"
+	// DefaultFSType is the filesystem of FCD volumes whose filesystem isn't
+	// known
+	DefaultFSType = "ext4"
 )
 
+// fsTypeParameters are the StorageClass parameters that CSI drivers read the
+// filesystem type of new volumes from
+var fsTypeParameters = []string{"csi.storage.k8s.io/fstype", "fstype"}
+
 // CreatePVC creates a PersistentVolumeClaim and returns its name
 // An empty 'targetVolID' indicates the caller would like the PV to be dynamically provisioned
 // An empty 'name' indicates the caller would like the name to be auto-generated
@@ -102,8 +112,8 @@ func CreatePVC(ctx context.Context, kubeCli kubernetes.Interface, ns string, nam
 // 'snapshotName' is the name of the VolumeSnapshot that will be used for restoring.
 // 'namespace' is the namespace of the VolumeSnapshot. The PVC will be restored to the same namepsace.
 // 'restoreSize' will override existing restore size from snapshot content if provided.
-func CreatePVCFromSnapshot(ctx context.Context, kubeCli kubernetes.Interface, snapCli snapshotclient.Interface, namespace, volumeName, storageClassName, snapshotName string, restoreSize *int) (string, error) {
-	snap, err := snapCli.VolumesnapshotV1alpha1().VolumeSnapshots(namespace).Get(snapshotName, metav1.GetOptions{})
+func CreatePVCFromSnapshot(ctx context.Context, kubeCli kubernetes.Interface, snapshotter snapshot.Snapshotter, namespace, volumeName, storageClassName, snapshotName string, restoreSize *int) (string, error) {
+	snap, err := snapshotter.Get(ctx, snapshotName, namespace)
 	if err != nil {
 		return "", err
 	}
@@ -117,7 +127,7 @@ func CreatePVCFromSnapshot(ctx context.Context, kubeCli kubernetes.Interface, sn
 	}
 
 	snapshotKind := "VolumeSnapshot"
-	snapshotAPIGroup := "snapshot.storage.k8s.io"
+	snapshotAPIGroup := snapshot.GroupName
 	pvc := &v1.PersistentVolumeClaim{
 		Spec: v1.PersistentVolumeClaimSpec{
 			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
@@ -154,7 +164,8 @@ func CreatePVCFromSnapshot(ctx context.Context, kubeCli kubernetes.Interface, sn
 
 // CreatePV creates a PersistentVolume and returns its name
 // For retry idempotency, checks whether PV associated with volume already exists
-func CreatePV(ctx context.Context, kubeCli kubernetes.Interface, vol *blockstorage.Volume, volType blockstorage.Type, annotations map[string]string) (string, error) {
+// fsType is the filesystem of FCD volumes. It defaults to DefaultFSType.
+func CreatePV(ctx context.Context, kubeCli kubernetes.Interface, vol *blockstorage.Volume, volType blockstorage.Type, annotations map[string]string, fsType string) (string, error) {
 	sizeFmt := fmt.Sprintf("%dGi", vol.Size)
 	size, err := resource.ParseQuantity(sizeFmt)
 	if err != nil {
@@ -197,6 +208,15 @@ func CreatePV(ctx context.Context, kubeCli kubernetes.Interface, vol *blockstora
 		}
 		pv.ObjectMeta.Labels[PVZoneLabelName] = vol.Az
 		pv.ObjectMeta.Labels[PVRegionLabelName] = zoneToRegion(vol.Az)
+	case blockstorage.TypeFCD:
+		if fsType == "" {
+			fsType = DefaultFSType
+		}
+		pv.Spec.PersistentVolumeSource.CSI = &v1.CSIPersistentVolumeSource{
+			Driver:       VSphereCSIDriverName,
+			VolumeHandle: vol.ID,
+			FSType:       fsType,
+		}
 	default:
 		return "", errors.Errorf("Volume type %v(%T) not supported ", volType, volType)
 	}
@@ -208,6 +228,31 @@ func CreatePV(ctx context.Context, kubeCli kubernetes.Interface, vol *blockstora
 	return createdPV.Name, nil
 }
 
+// CSIFSType returns the filesystem of a CSI PersistentVolume, as set in its
+// source or, failing that, in the parameters of its StorageClass. It returns an
+// empty string if neither sets it.
+func CSIFSType(kubeCli kubernetes.Interface, pv *v1.PersistentVolume) (string, error) {
+	if pv.Spec.CSI != nil && pv.Spec.CSI.FSType != "" {
+		return pv.Spec.CSI.FSType, nil
+	}
+	if pv.Spec.StorageClassName == "" {
+		return "", nil
+	}
+	sc, err := kubeCli.StorageV1().StorageClasses().Get(pv.Spec.StorageClassName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get StorageClass %s", pv.Spec.StorageClassName)
+	}
+	for _, p := range fsTypeParameters {
+		if fsType, ok := sc.Parameters[p]; ok {
+			return fsType, nil
+		}
+	}
+	return "", nil
+}
+
 // DeletePVC deletes the given PVC immediately and waits with timeout until it is returned as deleted
 func DeletePVC(cli kubernetes.Interface, namespace, pvcName string) error {
 	var now int64
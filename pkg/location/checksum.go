@@ -0,0 +1,101 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+
+	"github.com/pkg/errors"
+
+	"github.com/kanisterio/kanister/pkg/objectstore"
+)
+
+const (
+	// checksumSuffix names the object that holds the checksum of the data
+	// stored in the object it is appended to
+	checksumSuffix    = ".kanister-checksum"
+	checksumAlgorithm = "sha256"
+)
+
+// checksum describes the data stored in an object, after any compression and
+// encryption were applied. This allows it to be verified without the key.
+type checksum struct {
+	Algorithm string `json:"algorithm"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// checksumWriter computes the checksum of the data written to it.
+type checksumWriter struct {
+	h hash.Hash
+	n int64
+}
+
+func newChecksumWriter() *checksumWriter {
+	return &checksumWriter{h: sha256.New()}
+}
+
+func (w *checksumWriter) Write(p []byte) (int, error) {
+	n, err := w.h.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *checksumWriter) checksum() checksum {
+	return checksum{
+		Algorithm: checksumAlgorithm,
+		Digest:    hex.EncodeToString(w.h.Sum(nil)),
+		Size:      w.n,
+	}
+}
+
+// verify returns an error if `got` does not match the expected checksum.
+func (c checksum) verify(path string, got checksum) error {
+	if c.Algorithm != checksumAlgorithm {
+		return errors.Errorf("Unsupported checksum algorithm '%s' for '%s'", c.Algorithm, path)
+	}
+	if c.Digest != got.Digest || c.Size != got.Size {
+		return errors.Errorf("Checksum mismatch for '%s': expected %s %s (%d bytes), got %s (%d bytes)", path, c.Algorithm, c.Digest, c.Size, got.Digest, got.Size)
+	}
+	return nil
+}
+
+func writeChecksum(ctx context.Context, bucket objectstore.Bucket, path string, cs checksum) error {
+	b, err := json.Marshal(cs)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return bucket.PutBytes(ctx, path+checksumSuffix, b, nil)
+}
+
+// readChecksum returns nil if no checksum was stored for the object at `path`.
+func readChecksum(ctx context.Context, bucket objectstore.Bucket, path string) (*checksum, error) {
+	b, _, err := bucket.GetBytes(ctx, path+checksumSuffix)
+	if objectstore.IsObjectNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read checksum for '%s'", path)
+	}
+	cs := &checksum{}
+	if err := json.Unmarshal(b, cs); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse checksum for '%s'", path)
+	}
+	return cs, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+
+	"github.com/kanisterio/kanister/pkg/objectstore"
+)
+
+// Compression identifies the algorithm used to compress data written to a
+// location
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+const (
+	// codecTag is the object metadata key that records the codecs applied
+	// to the data, in the order they were applied
+	codecTag = "kanister_codec"
+
+	codecGzip       = string(CompressionGzip)
+	codecZstd       = string(CompressionZstd)
+	codecEncryption = "aes256gcm"
+)
+
+// WriteOptions configures how data is encoded and uploaded to a location
+type WriteOptions struct {
+	// Upload configures the multipart upload
+	Upload objectstore.UploadOptions
+	// Compression applied to the data before it is encrypted and uploaded
+	Compression Compression
+}
+
+// writeCodecs returns the codecs to apply to data written with `opts`.
+// Data is encrypted whenever the profile has an encryption key.
+func writeCodecs(opts WriteOptions, key []byte) ([]string, error) {
+	var codecs []string
+	switch opts.Compression {
+	case CompressionNone:
+	case CompressionGzip, CompressionZstd:
+		codecs = append(codecs, string(opts.Compression))
+	default:
+		return nil, errors.Errorf("Unsupported compression '%s'", opts.Compression)
+	}
+	if len(key) != 0 {
+		codecs = append(codecs, codecEncryption)
+	}
+	return codecs, nil
+}
+
+// readCodecs returns the codecs recorded in the object metadata `tags`.
+// Some object stores change the case of metadata keys.
+func readCodecs(tags map[string]string) []string {
+	for k, v := range tags {
+		if strings.EqualFold(k, codecTag) && v != "" {
+			return strings.Split(v, ",")
+		}
+	}
+	return nil
+}
+
+// encode copies `r` to `w`, applying `codecs` in order.
+func encode(w io.Writer, r io.Reader, codecs []string, key []byte) error {
+	var closers []io.Closer
+	for i := len(codecs) - 1; i >= 0; i-- {
+		wc, err := newEncoder(w, codecs[i], key)
+		if err != nil {
+			return err
+		}
+		closers = append([]io.Closer{wc}, closers...)
+		w = wc
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode copies `r` to `w`, reversing `codecs`.
+func decode(w io.Writer, r io.Reader, codecs []string, key []byte) error {
+	var closers []io.Closer
+	defer func() {
+		for _, c := range closers {
+			_ = c.Close()
+		}
+	}()
+	for i := len(codecs) - 1; i >= 0; i-- {
+		rc, err := newDecoder(r, codecs[i], key)
+		if err != nil {
+			return err
+		}
+		closers = append(closers, rc)
+		r = rc
+	}
+	_, err := io.Copy(w, r)
+	return err
+}
+
+func newEncoder(w io.Writer, codec string, key []byte) (io.WriteCloser, error) {
+	switch codec {
+	case codecGzip:
+		return gzip.NewWriter(w), nil
+	case codecZstd:
+		return zstd.NewWriter(w)
+	case codecEncryption:
+		return newEncryptWriter(w, key)
+	default:
+		return nil, errors.Errorf("Unsupported codec '%s'", codec)
+	}
+}
+
+func newDecoder(r io.Reader, codec string, key []byte) (io.ReadCloser, error) {
+	switch codec {
+	case codecGzip:
+		return gzip.NewReader(r)
+	case codecZstd:
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zstdReadCloser{d}, nil
+	case codecEncryption:
+		if len(key) == 0 {
+			return nil, errors.New("Data is encrypted but the profile has no encryption key")
+		}
+		return newDecryptReader(r, key), nil
+	default:
+		return nil, errors.Errorf("Unsupported codec '%s'", codec)
+	}
+}
+
+// zstdReadCloser adapts zstd.Decoder, whose Close does not return an error.
+type zstdReadCloser struct {
+	*zstd.Decoder
+}
+
+func (z zstdReadCloser) Close() error {
+	z.Decoder.Close()
+	return nil
+}
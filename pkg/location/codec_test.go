@@ -0,0 +1,136 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"crypto/rand"
+
+	. "gopkg.in/check.v1"
+)
+
+type CodecSuite struct{}
+
+var _ = Suite(&CodecSuite{})
+
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+func (s *CodecSuite) TestRoundTrip(c *C) {
+	for _, size := range []int{
+		0,
+		1,
+		encryptionChunkSize - 1,
+		encryptionChunkSize,
+		encryptionChunkSize + 1,
+		3*encryptionChunkSize + 7,
+	} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		c.Assert(err, IsNil)
+		for _, codecs := range [][]string{
+			nil,
+			{codecGzip},
+			{codecZstd},
+			{codecEncryption},
+			{codecGzip, codecEncryption},
+			{codecZstd, codecEncryption},
+		} {
+			enc := bytes.NewBuffer(nil)
+			err := encode(enc, bytes.NewReader(data), codecs, testEncryptionKey)
+			c.Assert(err, IsNil)
+			dec := bytes.NewBuffer(nil)
+			err = decode(dec, enc, codecs, testEncryptionKey)
+			c.Assert(err, IsNil, Commentf("size: %d, codecs: %v", size, codecs))
+			c.Check(dec.Len(), Equals, size)
+			c.Check(bytes.Equal(dec.Bytes(), data), Equals, true)
+		}
+	}
+}
+
+func (s *CodecSuite) TestEncryptionFailures(c *C) {
+	data := make([]byte, 2*encryptionChunkSize+100)
+	_, err := rand.Read(data)
+	c.Assert(err, IsNil)
+	enc := bytes.NewBuffer(nil)
+	err = encode(enc, bytes.NewReader(data), []string{codecEncryption}, testEncryptionKey)
+	c.Assert(err, IsNil)
+	ct := enc.Bytes()
+	chunk := encryptionChunkSize + 16
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+		key  []byte
+	}{
+		{
+			name: "tampered",
+			data: func() []byte {
+				t := append([]byte(nil), ct...)
+				t[len(t)/2] ^= 0xff
+				return t
+			}(),
+			key: testEncryptionKey,
+		},
+		{
+			name: "missing last chunk",
+			data: ct[:len(ct)-(len(ct)-1-encryptionSaltSize)%chunk],
+			key:  testEncryptionKey,
+		},
+		{
+			name: "truncated chunk",
+			data: ct[:len(ct)-10],
+			key:  testEncryptionKey,
+		},
+		{
+			name: "header only",
+			data: ct[:1+encryptionSaltSize],
+			key:  testEncryptionKey,
+		},
+		{
+			name: "wrong key",
+			data: ct,
+			key:  []byte("fedcba9876543210fedcba9876543210"),
+		},
+		{
+			name: "short key",
+			data: ct,
+			key:  []byte("short"),
+		},
+		{
+			name: "no key",
+			data: ct,
+		},
+	} {
+		err := decode(bytes.NewBuffer(nil), bytes.NewReader(tc.data), []string{codecEncryption}, tc.key)
+		c.Check(err, NotNil, Commentf(tc.name))
+	}
+
+	err = encode(bytes.NewBuffer(nil), bytes.NewReader(data), []string{codecEncryption}, []byte("short"))
+	c.Check(err, NotNil)
+}
+
+func (s *CodecSuite) TestCodecs(c *C) {
+	codecs, err := writeCodecs(WriteOptions{}, nil)
+	c.Assert(err, IsNil)
+	c.Check(codecs, HasLen, 0)
+	codecs, err = writeCodecs(WriteOptions{Compression: CompressionZstd}, testEncryptionKey)
+	c.Assert(err, IsNil)
+	c.Check(codecs, DeepEquals, []string{codecZstd, codecEncryption})
+	_, err = writeCodecs(WriteOptions{Compression: "lz4"}, nil)
+	c.Check(err, NotNil)
+
+	c.Check(readCodecs(nil), HasLen, 0)
+	c.Check(readCodecs(map[string]string{"Kanister_codec": "gzip,aes256gcm"}), DeepEquals, []string{codecGzip, codecEncryption})
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Encrypted data starts with a header holding the format version and a
+// random salt. The salt and the profile's key derive a per-object AES-256
+// key. The data follows as a sequence of AES-GCM sealed chunks. Each chunk's
+// nonce holds its sequence number and flags the last chunk, so reordered,
+// dropped or truncated chunks fail authentication.
+const (
+	encryptionVersion   = 1
+	encryptionSaltSize  = 32
+	encryptionChunkSize = 64 * 1024
+	minEncryptionKeyLen = 32
+
+	lastChunkFlag = 1
+)
+
+func deriveKey(key, salt []byte) (cipher.AEAD, error) {
+	if len(key) < minEncryptionKeyLen {
+		return nil, errors.Errorf("Encryption key must be at least %d bytes", minEncryptionKeyLen)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(salt)
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(aead cipher.AEAD, seq uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], seq)
+	if last {
+		nonce[len(nonce)-1] = lastChunkFlag
+	}
+	return nonce
+}
+
+var _ io.WriteCloser = (*encryptWriter)(nil)
+
+// encryptWriter encrypts data written to it. Close must be called to seal
+// the last chunk.
+type encryptWriter struct {
+	w    io.Writer
+	aead cipher.AEAD
+	seq  uint64
+	buf  []byte
+}
+
+func newEncryptWriter(w io.Writer, key []byte) (*encryptWriter, error) {
+	header := make([]byte, 1+encryptionSaltSize)
+	header[0] = encryptionVersion
+	if _, err := io.ReadFull(rand.Reader, header[1:]); err != nil {
+		return nil, errors.Wrap(err, "Failed to generate salt")
+	}
+	aead, err := deriveKey(key, header[1:])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:    w,
+		aead: aead,
+		buf:  make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data arrives, since the last
+		// chunk is sealed differently.
+		if len(e.buf) == encryptionChunkSize {
+			if err := e.seal(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		c := copy(e.buf[len(e.buf):cap(e.buf)], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	ct := e.aead.Seal(nil, chunkNonce(e.aead, e.seq, last), e.buf, nil)
+	if _, err := e.w.Write(ct); err != nil {
+		return err
+	}
+	e.seq++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+var _ io.ReadCloser = (*decryptReader)(nil)
+
+// decryptReader decrypts and authenticates data written by encryptWriter.
+type decryptReader struct {
+	r    *bufio.Reader
+	key  []byte
+	aead cipher.AEAD
+	seq  uint64
+	ct   []byte
+	pt   []byte
+	done bool
+}
+
+func newDecryptReader(r io.Reader, key []byte) *decryptReader {
+	return &decryptReader{
+		r:   bufio.NewReader(r),
+		key: key,
+	}
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.pt) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.pt)
+	d.pt = d.pt[n:]
+	return n, nil
+}
+
+func (d *decryptReader) Close() error {
+	return nil
+}
+
+func (d *decryptReader) readHeader() error {
+	header := make([]byte, 1+encryptionSaltSize)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return errors.Wrap(err, "Failed to read encryption header")
+	}
+	if header[0] != encryptionVersion {
+		return errors.Errorf("Unsupported encryption version %d", header[0])
+	}
+	aead, err := deriveKey(d.key, header[1:])
+	if err != nil {
+		return err
+	}
+	d.aead = aead
+	d.ct = make([]byte, encryptionChunkSize+aead.Overhead())
+	return nil
+}
+
+// open reads and decrypts the next chunk.
+func (d *decryptReader) open() error {
+	if d.aead == nil {
+		if err := d.readHeader(); err != nil {
+			return err
+		}
+	}
+	n, err := io.ReadFull(d.r, d.ct)
+	var last bool
+	switch err {
+	case nil:
+		// A full chunk is the last one if no data follows it
+		if _, err := d.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return errors.New("Encrypted data is truncated")
+	default:
+		return err
+	}
+	pt, err := d.aead.Open(d.ct[:0], chunkNonce(d.aead, d.seq, last), d.ct[:n], nil)
+	if err != nil {
+		return errors.New("Failed to authenticate encrypted data. The data is corrupt or the key is incorrect")
+	}
+	d.seq++
+	d.pt = pt
+	d.done = last
+	return nil
+}
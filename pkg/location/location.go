@@ -17,7 +17,9 @@ package location
 import (
 	"context"
 	"io"
+	"io/ioutil"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -39,6 +41,13 @@ const (
 
 // Write pipes data from `in` into the location specified by `profile` and `suffix`.
 func Write(ctx context.Context, in io.Reader, profile param.Profile, suffix string) error {
+	return WriteWithOptions(ctx, in, profile, suffix, WriteOptions{})
+}
+
+// WriteWithOptions pipes data from `in` into the location specified by `profile`
+// and `suffix`, compressing and uploading it as configured by `opts`. The data
+// is encrypted if the profile has an encryption key.
+func WriteWithOptions(ctx context.Context, in io.Reader, profile param.Profile, suffix string, opts WriteOptions) error {
 	osType, err := getProviderType(profile.Location.Type)
 	if err != nil {
 		return err
@@ -47,10 +56,13 @@ func Write(ctx context.Context, in io.Reader, profile param.Profile, suffix stri
 		profile.Location.Prefix,
 		suffix,
 	)
-	return writeData(ctx, osType, profile, in, path)
+	return writeData(ctx, osType, profile, in, path, opts)
 }
 
-// Read pipes data from `in` into the location specified by `profile` and `suffix`.
+// Read pipes data from the location specified by `profile` and `suffix` into
+// `out`, reversing any compression and encryption applied when it was written.
+// Data that does not match the checksum stored by Write results in an error
+// once it has been read.
 func Read(ctx context.Context, out io.Writer, profile param.Profile, suffix string) error {
 	osType, err := getProviderType(profile.Location.Type)
 	if err != nil {
@@ -63,6 +75,20 @@ func Read(ctx context.Context, out io.Writer, profile param.Profile, suffix stri
 	return readData(ctx, osType, profile, out, path)
 }
 
+// Verify checks the data in the location specified by `profile` and `suffix`
+// against the checksum stored when it was written, without decoding it.
+func Verify(ctx context.Context, profile param.Profile, suffix string) error {
+	osType, err := getProviderType(profile.Location.Type)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(
+		profile.Location.Prefix,
+		suffix,
+	)
+	return verifyData(ctx, osType, profile, path)
+}
+
 //Delete data from location specified by `profile` and `suffix`.
 func Delete(ctx context.Context, profile param.Profile, suffix string) error {
 	osType, err := getProviderType(profile.Location.Type)
@@ -82,23 +108,85 @@ func readData(ctx context.Context, pType objectstore.ProviderType, profile param
 		return err
 	}
 
-	r, _, err := bucket.Get(ctx, path)
+	cs, err := readChecksum(ctx, bucket, path)
 	if err != nil {
 		return err
 	}
-	if _, err := io.Copy(out, r); err != nil {
+	r, tags, err := bucket.Get(ctx, path)
+	if err != nil {
 		return err
 	}
-	return nil
+	defer r.Close()
+	codecs := readCodecs(tags)
+	if len(profile.EncryptionKey) != 0 && (len(codecs) == 0 || codecs[len(codecs)-1] != codecEncryption) {
+		// Never return data that may have been replaced by an unencrypted copy
+		return errors.Errorf("Data in '%s' is not encrypted but the profile has an encryption key", path)
+	}
+	cw := newChecksumWriter()
+	tr := io.TeeReader(r, cw)
+	if err := decode(out, tr, codecs, profile.EncryptionKey); err != nil {
+		return err
+	}
+	if cs == nil {
+		// Written before checksums were recorded
+		return nil
+	}
+	if _, err := io.Copy(ioutil.Discard, tr); err != nil {
+		return err
+	}
+	return cs.verify(path, cw.checksum())
 }
 
-func writeData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, in io.Reader, path string) error {
+func verifyData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, path string) error {
 	bucket, err := getBucket(ctx, pType, profile)
 	if err != nil {
 		return err
 	}
-	if err := bucket.Put(ctx, path, in, 0, nil); err != nil {
-		return errors.Errorf("failed to write contents to bucket '%s'", profile.Location.Bucket)
+	cs, err := readChecksum(ctx, bucket, path)
+	if err != nil {
+		return err
+	}
+	if cs == nil {
+		return errors.Errorf("No checksum found for '%s'", path)
+	}
+	r, _, err := bucket.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	cw := newChecksumWriter()
+	if _, err := io.Copy(cw, r); err != nil {
+		return errors.Wrapf(err, "Failed to read '%s'", path)
+	}
+	return cs.verify(path, cw.checksum())
+}
+
+func writeData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, in io.Reader, path string, opts WriteOptions) error {
+	codecs, err := writeCodecs(opts, profile.EncryptionKey)
+	if err != nil {
+		return err
+	}
+	bucket, err := getBucket(ctx, pType, profile)
+	if err != nil {
+		return err
+	}
+	var tags map[string]string
+	if len(codecs) != 0 {
+		tags = map[string]string{codecTag: strings.Join(codecs, ",")}
+		pr, pw := io.Pipe()
+		go func(r io.Reader) {
+			pw.CloseWithError(encode(pw, r, codecs, profile.EncryptionKey))
+		}(in)
+		// Unblock the encoder if the upload stops reading early
+		defer pr.Close()
+		in = pr
+	}
+	cw := newChecksumWriter()
+	if err := bucket.Upload(ctx, path, io.TeeReader(in, cw), tags, opts.Upload); err != nil {
+		return errors.Wrapf(err, "failed to write contents to bucket '%s'", profile.Location.Bucket)
+	}
+	if err := writeChecksum(ctx, bucket, path, cw.checksum()); err != nil {
+		return errors.Wrapf(err, "failed to write checksum to bucket '%s'", profile.Location.Bucket)
 	}
 	return nil
 }
@@ -119,6 +207,8 @@ func getProviderType(lType crv1alpha1.LocationType) (objectstore.ProviderType, e
 		return objectstore.ProviderTypeGCS, nil
 	case crv1alpha1.LocationTypeAzure:
 		return objectstore.ProviderTypeAzure, nil
+	case crv1alpha1.LocationTypeFilesystem:
+		return objectstore.ProviderTypeFilesystem, nil
 	default:
 		return "", errors.Errorf("Unsupported Location type: %s", lType)
 	}
@@ -158,6 +248,9 @@ func getOSSecret(ctx context.Context, pType objectstore.ProviderType, cred param
 			StorageAccount: cred.KeyPair.ID,
 			StorageKey:     cred.KeyPair.Secret,
 		}
+	case objectstore.ProviderTypeFilesystem:
+		// Local directories do not need credentials
+		return nil, nil
 	default:
 		return nil, errors.Errorf("unknown or unsupported provider type '%s'", pType)
 	}
@@ -52,6 +52,7 @@ const (
 var _ = Suite(&LocationSuite{osType: objectstore.ProviderTypeS3, region: testRegionS3})
 var _ = Suite(&LocationSuite{osType: objectstore.ProviderTypeGCS, region: ""})
 var _ = Suite(&LocationSuite{osType: objectstore.ProviderTypeAzure, region: ""})
+var _ = Suite(&LocationSuite{osType: objectstore.ProviderTypeFilesystem, region: ""})
 
 func (s *LocationSuite) SetUpSuite(c *C) {
 	var location crv1alpha1.Location
@@ -74,16 +75,25 @@ func (s *LocationSuite) SetUpSuite(c *C) {
 		location = crv1alpha1.Location{
 			Type: crv1alpha1.LocationTypeAzure,
 		}
+	case objectstore.ProviderTypeFilesystem:
+		location = crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Endpoint: c.MkDir(),
+		}
 	default:
 		c.Fatalf("Unrecognized objectstore '%s'", s.osType)
 	}
 	location.Bucket = testBucketName
-	s.profile = *testutil.ObjectStoreProfileOrSkip(c, s.osType, location)
+	if s.osType == objectstore.ProviderTypeFilesystem {
+		s.profile = param.Profile{Location: location}
+	} else {
+		s.profile = *testutil.ObjectStoreProfileOrSkip(c, s.osType, location)
+	}
 	var err error
 	ctx := context.Background()
 
 	s.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
-	pc := objectstore.ProviderConfig{Type: s.osType}
+	pc := objectstore.ProviderConfig{Type: s.osType, Endpoint: s.profile.Location.Endpoint}
 	secret, err := getOSSecret(ctx, s.osType, s.profile.Credential)
 	c.Check(err, IsNil)
 	s.provider, err = objectstore.NewProvider(ctx, pc, secret)
@@ -101,7 +111,7 @@ func (s *LocationSuite) TearDownTest(c *C) {
 	if s.testpath != "" {
 		c.Assert(s.root, NotNil)
 		ctx := context.Background()
-		err := s.root.Delete(ctx, s.testpath)
+		err := s.root.DeleteAllWithPrefix(ctx, s.testpath)
 		if err != nil {
 			c.Log("Cannot cleanup test directory: ", s.testpath)
 			return
@@ -112,7 +122,7 @@ func (s *LocationSuite) TearDownTest(c *C) {
 func (s *LocationSuite) TestWriteAndReadData(c *C) {
 	ctx := context.Background()
 	teststring := "test-content"
-	err := writeData(ctx, s.osType, s.profile, bytes.NewBufferString(teststring), s.testpath)
+	err := writeData(ctx, s.osType, s.profile, bytes.NewBufferString(teststring), s.testpath, WriteOptions{})
 	c.Check(err, IsNil)
 	buf := bytes.NewBuffer(nil)
 	err = readData(ctx, s.osType, s.profile, buf, s.testpath)
@@ -120,3 +130,57 @@ func (s *LocationSuite) TestWriteAndReadData(c *C) {
 	c.Check(buf.String(), Equals, teststring)
 
 }
+
+func (s *LocationSuite) TestWriteAndReadEncodedData(c *C) {
+	ctx := context.Background()
+	data := make([]byte, 3*encryptionChunkSize+17)
+	_, err := s.rand.Read(data)
+	c.Assert(err, IsNil)
+	profile := s.profile
+	profile.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	for _, comp := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		err := writeData(ctx, s.osType, profile, bytes.NewReader(data), s.testpath, WriteOptions{Compression: comp})
+		c.Assert(err, IsNil)
+		buf := bytes.NewBuffer(nil)
+		err = readData(ctx, s.osType, profile, buf, s.testpath)
+		c.Assert(err, IsNil)
+		c.Check(buf.Bytes(), DeepEquals, data)
+
+		// Encrypted data cannot be read without the key
+		err = readData(ctx, s.osType, s.profile, bytes.NewBuffer(nil), s.testpath)
+		c.Check(err, NotNil)
+	}
+
+	// Unencrypted data is not read with a key
+	err = writeData(ctx, s.osType, s.profile, bytes.NewReader(data), s.testpath, WriteOptions{Compression: CompressionGzip})
+	c.Assert(err, IsNil)
+	err = readData(ctx, s.osType, profile, bytes.NewBuffer(nil), s.testpath)
+	c.Check(err, ErrorMatches, ".*not encrypted.*")
+}
+
+func (s *LocationSuite) TestChecksum(c *C) {
+	ctx := context.Background()
+	teststring := "test-content"
+	err := writeData(ctx, s.osType, s.profile, bytes.NewBufferString(teststring), s.testpath, WriteOptions{})
+	c.Assert(err, IsNil)
+	err = verifyData(ctx, s.osType, s.profile, s.testpath)
+	c.Assert(err, IsNil)
+
+	// Modify the object without updating its checksum
+	err = s.root.PutBytes(ctx, s.testpath, []byte("test-contenT"), nil)
+	c.Assert(err, IsNil)
+	err = verifyData(ctx, s.osType, s.profile, s.testpath)
+	c.Assert(err, ErrorMatches, "Checksum mismatch.*")
+	err = readData(ctx, s.osType, s.profile, bytes.NewBuffer(nil), s.testpath)
+	c.Assert(err, ErrorMatches, "Checksum mismatch.*")
+
+	// Objects without a checksum can be read, but not verified
+	err = s.root.Delete(ctx, s.testpath+checksumSuffix)
+	c.Assert(err, IsNil)
+	buf := bytes.NewBuffer(nil)
+	err = readData(ctx, s.osType, s.profile, buf, s.testpath)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "test-contenT")
+	err = verifyData(ctx, s.osType, s.profile, s.testpath)
+	c.Assert(err, NotNil)
+}
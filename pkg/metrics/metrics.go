@@ -0,0 +1,77 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics registers the Kanister metrics with the default Prometheus
+// registry and serves them.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The metrics exported by Kanister.
+var (
+	// ActionSets counts the ActionSets that reached each state.
+	ActionSets = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kanister_actionsets_total",
+		Help: "Number of ActionSets that reached a state.",
+	}, []string{"state"})
+	// PhaseDuration observes the duration of each execution of a Kanister
+	// function.
+	PhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kanister_phase_duration_seconds",
+		Help:    "Duration of phase executions by Kanister function.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+	}, []string{"function"})
+	// FunctionErrors counts the executions of a Kanister function that
+	// failed.
+	FunctionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kanister_function_errors_total",
+		Help: "Number of failed Kanister function executions.",
+	}, []string{"function"})
+	// DataBytes counts the bytes that Kanister functions added to a backup
+	// repository.
+	DataBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kanister_data_bytes_total",
+		Help: "Number of bytes added to backup repositories by Kanister function.",
+	}, []string{"function"})
+	// ObjectStoreOperationDuration observes the duration of object store
+	// operations. It is only served by the process that performs them, so
+	// operations that kando runs in pods aren't part of the controller's
+	// metrics.
+	ObjectStoreOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kanister_objectstore_operation_duration_seconds",
+		Help:    "Duration of object store operations.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		ActionSets,
+		PhaseDuration,
+		FunctionErrors,
+		DataBytes,
+		ObjectStoreOperationDuration,
+	)
+}
+
+// Handler returns an http.Handler that serves the metrics of the default
+// registry, which include the Go runtime and process metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
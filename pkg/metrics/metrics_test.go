@@ -0,0 +1,53 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type MetricsSuite struct{}
+
+var _ = Suite(&MetricsSuite{})
+
+func (s *MetricsSuite) TestHandler(c *C) {
+	ActionSets.WithLabelValues("complete").Inc()
+	ActionSets.WithLabelValues("complete").Inc()
+	c.Assert(testutil.ToFloat64(ActionSets.WithLabelValues("complete")), Equals, float64(2))
+	PhaseDuration.WithLabelValues("BackupData").Observe(3)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	c.Assert(rec.Code, Equals, 200)
+	out := rec.Body.String()
+	for _, l := range []string{
+		`kanister_actionsets_total{state="complete"} 2`,
+		`kanister_phase_duration_seconds_bucket{function="BackupData",le="2"} 0`,
+		`kanister_phase_duration_seconds_bucket{function="BackupData",le="4"} 1`,
+		`kanister_phase_duration_seconds_count{function="BackupData"} 1`,
+	} {
+		c.Check(strings.Contains(out, l+"\n"), Equals, true, Commentf(l))
+	}
+	// The default registry also serves the Go runtime metrics.
+	c.Check(strings.Contains(out, "go_goroutines"), Equals, true)
+}
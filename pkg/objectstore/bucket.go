@@ -44,6 +44,8 @@ type bucket struct {
 	container    stow.Container // stow bucket
 	location     stow.Location  // Authenticated stow handle
 	hostEndPoint string         // E.g., https://s3-us-west-2.amazonaws.com/bucket1
+	provider     *provider      // Used to create native clients for uploads
+	region       string         // Bucket region, if known
 }
 
 // CreateBucket creates the bucket. Bucket naming rules are provider dependent.
@@ -64,6 +66,8 @@ func (p *provider) CreateBucket(ctx context.Context, bucketName, region string)
 		container:    c,
 		location:     location,
 		hostEndPoint: path.Join(p.hostEndPoint, c.ID()),
+		provider:     p,
+		region:       region,
 	}
 	dir.bucket = bucket
 	return bucket, nil
@@ -88,6 +92,7 @@ func (p *provider) GetBucket(ctx context.Context, bucketName string) (Bucket, er
 		container:    c,
 		location:     location,
 		hostEndPoint: path.Join(p.hostEndPoint, c.ID()),
+		provider:     p,
 	}
 	dir.bucket = bucket
 	return bucket, nil
@@ -116,6 +121,7 @@ func (p *provider) ListBuckets(ctx context.Context) (map[string]Bucket, error) {
 				container:    c,
 				location:     location,
 				hostEndPoint: path.Join(p.hostEndPoint, c.ID()),
+				provider:     p,
 			}
 			dir.bucket = bucket
 			buckets[c.ID()] = bucket
@@ -178,6 +184,8 @@ func (p *s3Provider) GetBucket(ctx context.Context, bucketName string) (Bucket,
 		container:    c,
 		location:     location,
 		hostEndPoint: path.Join(hostEndPoint, c.ID()),
+		provider:     p.provider,
+		region:       region,
 	}
 	dir.bucket = bucket
 	return bucket, nil
@@ -29,6 +29,10 @@ const (
 	ProviderTypeS3 ProviderType = "S3"
 	// ProviderTypeAzure captures enum value "Azure"
 	ProviderTypeAzure ProviderType = "Azure"
+	// ProviderTypeFilesystem captures enum value "Filesystem"
+	ProviderTypeFilesystem ProviderType = "Filesystem"
+	// ProviderTypeMemory captures enum value "Memory"
+	ProviderTypeMemory ProviderType = "Memory"
 )
 
 // SecretType enum for different providers
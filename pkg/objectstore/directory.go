@@ -24,9 +24,12 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/graymeta/stow"
 	"github.com/pkg/errors"
+
+	"github.com/kanisterio/kanister/pkg/metrics"
 )
 
 var _ Directory = (*directory)(nil)
@@ -74,6 +77,7 @@ func (d *directory) GetDirectory(ctx context.Context, dir string) (Directory, er
 // ListDirectories lists all the directories that have d.path as the prefix.
 // the returned map is indexed by the relative directory name (without trailing '/')
 func (d *directory) ListDirectories(ctx context.Context) (map[string]Directory, error) {
+	defer observeOperation("list_directories", time.Now())
 	if d.path == "" {
 		return nil, errors.New("invalid entry")
 	}
@@ -111,6 +115,7 @@ func (d *directory) ListDirectories(ctx context.Context) (map[string]Directory,
 
 // ListObjects lists all the files that have d.dirname as the prefix.
 func (d *directory) ListObjects(ctx context.Context) ([]string, error) {
+	defer observeOperation("list_objects", time.Now())
 	if d.path == "" {
 		return nil, errors.New("invalid entry")
 	}
@@ -136,6 +141,7 @@ func (d *directory) ListObjects(ctx context.Context) ([]string, error) {
 // DeleteDirectory deletes all objects that have d.path as the prefix
 // <bucket>/<d.path>/<everything> including <bucket>/<d.path>/<some dir>/<objects>
 func (d *directory) DeleteDirectory(ctx context.Context) error {
+	defer observeOperation("delete_directory", time.Now())
 	if d.path == "" {
 		return errors.New("invalid entry")
 	}
@@ -145,25 +151,13 @@ func (d *directory) DeleteDirectory(ctx context.Context) error {
 // DeleteDirectory deletes all objects that have d.path/dir as the prefix
 // <bucket>/<d.path>/dir/<everything> including <bucket>/<d.path>/dir/<some dir>/<objects>
 func (d *directory) DeleteAllWithPrefix(ctx context.Context, prefix string) error {
+	defer observeOperation("delete_prefix", time.Now())
 	p := cloudName(filepath.Join(d.path, prefix))
 	return deleteWithPrefix(ctx, d.bucket.container, p)
 }
 
-func deleteWithPrefix(ctx context.Context, c stow.Container, prefix string) error {
-	err := stow.Walk(c, prefix, 10000,
-		func(item stow.Item, err error) error {
-			if err != nil {
-				return err
-			}
-			return c.RemoveItem(item.Name())
-		})
-	if err != nil {
-		return errors.Wrapf(err, "Failed to delete item %s", prefix)
-	}
-	return nil
-}
-
 func (d *directory) Get(ctx context.Context, name string) (io.ReadCloser, map[string]string, error) {
+	defer observeOperation("get", time.Now())
 	if d.path == "" {
 		return nil, nil, errors.New("invalid entry")
 	}
@@ -185,15 +179,7 @@ func (d *directory) Get(ctx context.Context, name string) (io.ReadCloser, map[st
 		return nil, nil, err
 	}
 
-	// Convert tags:map[string]interface{} into map[string]string
-	tags := make(map[string]string)
-	for key, val := range rTags {
-		if sVal, ok := val.(string); ok {
-			tags[key] = sVal
-		}
-	}
-
-	return r, tags, nil
+	return r, stringTags(rTags), nil
 }
 
 // Get data and tags associated with an object <bucket>/<d.path>/name.
@@ -213,6 +199,7 @@ func (d *directory) GetBytes(ctx context.Context, name string) ([]byte, map[stri
 }
 
 func (d *directory) Put(ctx context.Context, name string, r io.Reader, size int64, tags map[string]string) error {
+	defer observeOperation("put", time.Now())
 	if d.path == "" {
 		return errors.New("invalid entry")
 	}
@@ -232,8 +219,28 @@ func (d *directory) PutBytes(ctx context.Context, name string, data []byte, tags
 	return d.Put(ctx, name, bytes.NewReader(data), int64(len(data)), tags)
 }
 
+// Upload stores a stream of unknown length in d.path/<name>, in parts where
+// the provider supports it.
+func (d *directory) Upload(ctx context.Context, name string, r io.Reader, tags map[string]string, opts UploadOptions) error {
+	defer observeOperation("upload", time.Now())
+	if d.path == "" {
+		return errors.New("invalid entry")
+	}
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+	u, err := newUploader(ctx, d.bucket)
+	if err != nil {
+		return errors.Wrap(err, "failed to create uploader")
+	}
+	objName := d.absPathName(name)
+	return u.upload(ctx, cloudName(objName), r, sanitizeTags(tags), opts)
+}
+
 // Delete removes an object
 func (d *directory) Delete(ctx context.Context, name string) error {
+	defer observeOperation("delete", time.Now())
 	if d.path == "" {
 		return errors.New("invalid entry")
 	}
@@ -286,6 +293,17 @@ func sanitizeTags(tags map[string]string) map[string]interface{} {
 	return cTags
 }
 
+// stringTags converts tags:map[string]interface{} into map[string]string
+func stringTags(rTags map[string]interface{}) map[string]string {
+	tags := make(map[string]string)
+	for key, val := range rTags {
+		if sVal, ok := val.(string); ok {
+			tags[key] = sVal
+		}
+	}
+	return tags
+}
+
 // isDirectoryObject checks if path includes one '/'.
 // If so, returns value until first '/'
 // path is of the form elem1/elem2/, returns elem1
@@ -305,3 +323,9 @@ func isDirectoryObject(path string) (string, bool) {
 	// Not reached
 	return "", false
 }
+
+// observeOperation records the duration of an object store operation that
+// started at start.
+func observeOperation(op string, start time.Time) {
+	metrics.ObjectStoreOperationDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,430 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Object store backed by a local directory, e.g., an NFS mounted PVC,
+// exposed as a Stow location. Buckets are the top level directories under the
+// root and objects are files. Directory markers map to directories.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/pkg/errors"
+)
+
+const (
+	filesystemKind       = "kanister-filesystem"
+	filesystemScheme     = "file"
+	filesystemConfigPath = "path"
+	// Object tags are stored as JSON files in a parallel tree under the root
+	// so that they never show up as objects.
+	filesystemMetadataDir = ".kanister-metadata"
+	filesystemMetadataExt = ".json"
+	// Objects are written to temporary files that are skipped when listing.
+	filesystemTempPrefix = ".kanister-tmp-"
+)
+
+func init() {
+	stow.Register(filesystemKind, dialFilesystem, func(u *url.URL) bool {
+		return u.Scheme == filesystemScheme
+	})
+}
+
+func dialFilesystem(config stow.Config) (stow.Location, error) {
+	root, ok := config.Config(filesystemConfigPath)
+	if !ok || root == "" {
+		return nil, errors.New("root directory for the filesystem store not specified")
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid root directory %s", root)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not access root directory %s", root)
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("root %s is not a directory", root)
+	}
+	return &filesystemLocation{root: root}, nil
+}
+
+var _ stow.Location = (*filesystemLocation)(nil)
+
+type filesystemLocation struct {
+	root string
+}
+
+func (l *filesystemLocation) Close() error {
+	return nil
+}
+
+func (l *filesystemLocation) CreateContainer(name string) (stow.Container, error) {
+	if err := validateFilesystemBucketName(name); err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(filepath.Join(l.root, name), 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create bucket %s", name)
+	}
+	return &filesystemContainer{location: l, name: name}, nil
+}
+
+func (l *filesystemLocation) Containers(prefix string, cursor string, count int) ([]stow.Container, string, error) {
+	fis, err := ioutil.ReadDir(l.root)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to list buckets in %s", l.root)
+	}
+	names := make([]string, 0, len(fis))
+	for _, fi := range fis {
+		if fi.IsDir() && !strings.HasPrefix(fi.Name(), ".") {
+			names = append(names, fi.Name())
+		}
+	}
+	page, next := pageNames(names, prefix, cursor, count)
+	containers := make([]stow.Container, 0, len(page))
+	for _, name := range page {
+		containers = append(containers, &filesystemContainer{location: l, name: name})
+	}
+	return containers, next, nil
+}
+
+func (l *filesystemLocation) Container(id string) (stow.Container, error) {
+	if err := validateFilesystemBucketName(id); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(filepath.Join(l.root, id))
+	if os.IsNotExist(err) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get bucket %s", id)
+	}
+	if !info.IsDir() {
+		return nil, stow.ErrNotFound
+	}
+	return &filesystemContainer{location: l, name: id}, nil
+}
+
+// RemoveContainer removes the bucket directory. It fails if the bucket is not
+// empty.
+func (l *filesystemLocation) RemoveContainer(id string) error {
+	if err := validateFilesystemBucketName(id); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(l.root, id)); err != nil {
+		if os.IsNotExist(err) {
+			return stow.ErrNotFound
+		}
+		return errors.Wrapf(err, "failed to remove bucket %s", id)
+	}
+	return os.RemoveAll(filepath.Join(l.root, filesystemMetadataDir, id))
+}
+
+func (l *filesystemLocation) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u.Scheme != filesystemScheme {
+		return nil, errors.Errorf("URL %s is not a file URL", u)
+	}
+	rel, err := filepath.Rel(l.root, filepath.FromSlash(u.Path))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil, errors.Errorf("URL %s is not under %s", u, l.root)
+	}
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+	if len(parts) != 2 {
+		return nil, stow.ErrNotFound
+	}
+	c, err := l.Container(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(parts[1])
+}
+
+func validateFilesystemBucketName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return errors.Errorf("invalid bucket name '%s'", name)
+	}
+	return nil
+}
+
+var _ stow.Container = (*filesystemContainer)(nil)
+
+type filesystemContainer struct {
+	location *filesystemLocation
+	name     string
+}
+
+func (c *filesystemContainer) ID() string {
+	return c.name
+}
+
+func (c *filesystemContainer) Name() string {
+	return c.name
+}
+
+func (c *filesystemContainer) root() string {
+	return filepath.Join(c.location.root, c.name)
+}
+
+// dataPath maps an object name to its file. Names that resolve outside the
+// bucket directory are rejected.
+func (c *filesystemContainer) dataPath(name string) (string, error) {
+	root := c.root()
+	p := filepath.Join(root, filepath.FromSlash(name))
+	if p != root && !strings.HasPrefix(p, root+string(filepath.Separator)) {
+		return "", errors.Errorf("invalid object name '%s'", name)
+	}
+	return p, nil
+}
+
+func (c *filesystemContainer) metadataPath(name string) string {
+	p := filepath.Join(c.location.root, filesystemMetadataDir, c.name, filepath.FromSlash(name))
+	if strings.HasSuffix(name, "/") {
+		return p
+	}
+	return p + filesystemMetadataExt
+}
+
+func (c *filesystemContainer) Item(id string) (stow.Item, error) {
+	p, err := c.dataPath(id)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(p)
+	if os.IsNotExist(err) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get object %s", id)
+	}
+	// Directory markers end with '/'. Anything else must be a regular file.
+	if info.IsDir() != strings.HasSuffix(id, "/") {
+		return nil, stow.ErrNotFound
+	}
+	return &filesystemItem{container: c, name: id, path: p, info: info}, nil
+}
+
+func (c *filesystemContainer) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	root := c.root()
+	var names []string
+	infos := make(map[string]os.FileInfo)
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasPrefix(info.Name(), filesystemTempPrefix) {
+			return nil
+		}
+		name := filepath.ToSlash(rel)
+		if info.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+		infos[name] = info
+		return nil
+	})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to list objects in bucket %s", c.name)
+	}
+	page, next := pageNames(names, prefix, cursor, count)
+	items := make([]stow.Item, 0, len(page))
+	for _, name := range page {
+		items = append(items, &filesystemItem{
+			container: c,
+			name:      name,
+			path:      filepath.Join(root, filepath.FromSlash(name)),
+			info:      infos[name],
+		})
+	}
+	return items, next, nil
+}
+
+// RemoveItem deletes the object and its tags. Removing a directory marker
+// removes the whole directory, since a directory cannot outlive its marker
+// on a filesystem. Like S3, removing a missing object is not an error.
+func (c *filesystemContainer) RemoveItem(id string) error {
+	p, err := c.dataPath(id)
+	if err != nil {
+		return err
+	}
+	if p == c.root() {
+		return errors.New("cannot remove the bucket root")
+	}
+	if strings.HasSuffix(id, "/") {
+		err = os.RemoveAll(p)
+	} else {
+		err = os.Remove(p)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove object %s", id)
+	}
+	if err := os.RemoveAll(c.metadataPath(id)); err != nil {
+		return errors.Wrapf(err, "failed to remove tags for object %s", id)
+	}
+	return nil
+}
+
+// Put writes the data read from r to the object's file. The size is only a
+// hint; callers such as location.Write do not know it up front.
+func (c *filesystemContainer) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
+	p, err := c.dataPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(name, "/") {
+		if err := os.MkdirAll(p, 0755); err != nil {
+			return nil, errors.Wrapf(err, "failed to create directory %s", name)
+		}
+	} else if err := writeFile(p, r); err != nil {
+		return nil, errors.Wrapf(err, "failed to write object %s", name)
+	}
+	if err := c.putMetadata(name, metadata); err != nil {
+		return nil, err
+	}
+	return c.Item(name)
+}
+
+func (c *filesystemContainer) putMetadata(name string, metadata map[string]interface{}) error {
+	mp := c.metadataPath(name)
+	if strings.HasSuffix(name, "/") {
+		// Directory markers carry no tags
+		return nil
+	}
+	if len(metadata) == 0 {
+		if err := os.Remove(mp); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove tags for object %s", name)
+		}
+		return nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode tags for object %s", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(mp), 0755); err != nil {
+		return errors.Wrapf(err, "failed to write tags for object %s", name)
+	}
+	return errors.Wrapf(ioutil.WriteFile(mp, data, 0644), "failed to write tags for object %s", name)
+}
+
+// writeFile writes to a temporary file in the same directory and renames it
+// to p, so that a failed write never leaves a partial object behind.
+func writeFile(p string, r io.Reader) error {
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, filesystemTempPrefix)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	// TempFile creates files readable only by the owner.
+	if err = os.Chmod(f.Name(), 0644); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = os.Rename(f.Name(), p); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+var _ stow.Item = (*filesystemItem)(nil)
+
+type filesystemItem struct {
+	container *filesystemContainer
+	name      string
+	path      string
+	info      os.FileInfo
+}
+
+func (i *filesystemItem) ID() string {
+	return i.name
+}
+
+func (i *filesystemItem) Name() string {
+	return i.name
+}
+
+func (i *filesystemItem) URL() *url.URL {
+	return &url.URL{
+		Scheme: filesystemScheme,
+		Path:   filepath.ToSlash(i.path),
+	}
+}
+
+func (i *filesystemItem) Size() (int64, error) {
+	if i.info.IsDir() {
+		return 0, nil
+	}
+	return i.info.Size(), nil
+}
+
+func (i *filesystemItem) Open() (io.ReadCloser, error) {
+	if i.info.IsDir() {
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	}
+	return os.Open(i.path)
+}
+
+func (i *filesystemItem) ETag() (string, error) {
+	return fmt.Sprintf("%x-%x", i.info.ModTime().UnixNano(), i.info.Size()), nil
+}
+
+func (i *filesystemItem) LastMod() (time.Time, error) {
+	return i.info.ModTime(), nil
+}
+
+func (i *filesystemItem) Metadata() (map[string]interface{}, error) {
+	md := make(map[string]interface{})
+	if i.info.IsDir() {
+		return md, nil
+	}
+	data, err := ioutil.ReadFile(i.container.metadataPath(i.name))
+	if os.IsNotExist(err) {
+		return md, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read tags for object %s", i.name)
+	}
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode tags for object %s", i.name)
+	}
+	return md, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/pkg/errors"
+)
+
+// DefaultListPageSize is the number of objects listed per page if
+// ListOptions.PageSize is not set
+const DefaultListPageSize = 1000
+
+// ListOptions configures ListObjectsPage
+type ListOptions struct {
+	// Prefix restricts the listing to objects whose names, relative to the
+	// directory, start with it
+	Prefix string
+	// Cursor returned with the previous page. Empty for the first page.
+	Cursor string
+	// PageSize is the maximum number of objects returned
+	PageSize int
+	// Tags requests the tags of each object. Some providers need a request
+	// per object to fetch them.
+	Tags bool
+}
+
+// ObjectInfo describes an object
+type ObjectInfo struct {
+	// Name of the object relative to the directory. Directory markers end
+	// with '/'.
+	Name         string
+	Size         int64
+	LastModified time.Time
+	// Tags is only set if ListOptions.Tags was requested
+	Tags map[string]string
+}
+
+// ObjectPage is a page of objects returned by ListObjectsPage
+type ObjectPage struct {
+	Objects []ObjectInfo
+	// Cursor to pass to the next call. Empty if this is the last page.
+	Cursor string
+}
+
+// ListObjectsPage lists a page of the objects under the directory, including
+// those in sub directories, that match opts.Prefix. The prefix is applied by
+// the object store.
+func (d *directory) ListObjectsPage(ctx context.Context, opts ListOptions) (*ObjectPage, error) {
+	defer observeOperation("list_objects_page", time.Now())
+	if d.path == "" {
+		return nil, errors.New("invalid entry")
+	}
+	if opts.PageSize < 0 {
+		return nil, errors.Errorf("invalid page size %d", opts.PageSize)
+	}
+	if opts.PageSize == 0 {
+		opts.PageSize = DefaultListPageSize
+	}
+	dir := cloudName(d.path)
+	return listPage(d.bucket.container, dir, dir+opts.Prefix, opts)
+}
+
+// listPage lists a page of the items that start with prefix. Object names
+// are returned relative to dir.
+func listPage(c stow.Container, dir, prefix string, opts ListOptions) (*ObjectPage, error) {
+	items, cursor, err := c.Items(prefix, opts.Cursor, opts.PageSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list objects with prefix %s", prefix)
+	}
+	page := &ObjectPage{
+		Objects: make([]ObjectInfo, 0, len(items)),
+		Cursor:  cursor,
+	}
+	for _, item := range items {
+		oi := ObjectInfo{
+			Name: strings.TrimPrefix(item.Name(), dir),
+		}
+		if oi.Size, err = item.Size(); err != nil {
+			return nil, errors.Wrapf(err, "failed to get size of %s", item.Name())
+		}
+		if oi.LastModified, err = item.LastMod(); err != nil {
+			return nil, errors.Wrapf(err, "failed to get modification time of %s", item.Name())
+		}
+		if opts.Tags {
+			md, err := item.Metadata()
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get tags of %s", item.Name())
+			}
+			oi.Tags = stringTags(md)
+		}
+		page.Objects = append(page.Objects, oi)
+	}
+	return page, nil
+}
+
+// deleteWithPrefix deletes all items that start with prefix, one page at a
+// time.
+func deleteWithPrefix(ctx context.Context, c stow.Container, prefix string) error {
+	opts := ListOptions{PageSize: DefaultListPageSize}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := listPage(c, "", prefix, opts)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to delete item %s", prefix)
+		}
+		for _, o := range page.Objects {
+			if err := c.RemoveItem(o.Name); err != nil {
+				return errors.Wrapf(err, "Failed to delete item %s", o.Name)
+			}
+		}
+		if page.Cursor == "" {
+			return nil
+		}
+		opts.Cursor = page.Cursor
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// In-memory object store exposed as a Stow location. Intended for tests.
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/pkg/errors"
+)
+
+const (
+	memoryKind         = "kanister-memory"
+	memoryScheme       = "memory"
+	memoryConfigStore  = "store"
+	defaultMemoryStore = "default"
+)
+
+var (
+	memoryStoresMu sync.Mutex
+	memoryStores   = make(map[string]*memoryLocation)
+)
+
+func init() {
+	stow.Register(memoryKind, dialMemory, func(u *url.URL) bool {
+		return u.Scheme == memoryScheme
+	})
+}
+
+// dialMemory returns the named in-memory store, creating it on first use.
+// Stores live for the lifetime of the process so that every provider dialed
+// with the same name sees the same buckets.
+func dialMemory(config stow.Config) (stow.Location, error) {
+	name, _ := config.Config(memoryConfigStore)
+	if name == "" {
+		name = defaultMemoryStore
+	}
+	memoryStoresMu.Lock()
+	defer memoryStoresMu.Unlock()
+	l, ok := memoryStores[name]
+	if !ok {
+		l = &memoryLocation{
+			name:       name,
+			containers: make(map[string]*memoryContainer),
+		}
+		memoryStores[name] = l
+	}
+	return l, nil
+}
+
+var _ stow.Location = (*memoryLocation)(nil)
+
+type memoryLocation struct {
+	name string
+	// mu guards the containers and all the items they hold
+	mu         sync.RWMutex
+	containers map[string]*memoryContainer
+}
+
+func (l *memoryLocation) Close() error {
+	return nil
+}
+
+func (l *memoryLocation) CreateContainer(name string) (stow.Container, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, errors.Errorf("invalid bucket name '%s'", name)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.containers[name]; ok {
+		return nil, errors.Errorf("bucket %s already exists", name)
+	}
+	c := &memoryContainer{
+		location: l,
+		name:     name,
+		items:    make(map[string]*memoryItem),
+	}
+	l.containers[name] = c
+	return c, nil
+}
+
+func (l *memoryLocation) Containers(prefix string, cursor string, count int) ([]stow.Container, string, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	names := make([]string, 0, len(l.containers))
+	for name := range l.containers {
+		names = append(names, name)
+	}
+	page, next := pageNames(names, prefix, cursor, count)
+	containers := make([]stow.Container, 0, len(page))
+	for _, name := range page {
+		containers = append(containers, l.containers[name])
+	}
+	return containers, next, nil
+}
+
+func (l *memoryLocation) Container(id string) (stow.Container, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	c, ok := l.containers[id]
+	if !ok {
+		return nil, stow.ErrNotFound
+	}
+	return c, nil
+}
+
+func (l *memoryLocation) RemoveContainer(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.containers[id]
+	if !ok {
+		return stow.ErrNotFound
+	}
+	if len(c.items) != 0 {
+		return errors.Errorf("bucket %s is not empty", id)
+	}
+	delete(l.containers, id)
+	return nil
+}
+
+func (l *memoryLocation) ItemByURL(u *url.URL) (stow.Item, error) {
+	if u.Scheme != memoryScheme || u.Host != l.name {
+		return nil, errors.Errorf("URL %s does not belong to memory store %s", u, l.name)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if len(parts) != 2 {
+		return nil, stow.ErrNotFound
+	}
+	c, err := l.Container(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(parts[1])
+}
+
+var _ stow.Container = (*memoryContainer)(nil)
+
+type memoryContainer struct {
+	location *memoryLocation
+	name     string
+	items    map[string]*memoryItem
+}
+
+func (c *memoryContainer) ID() string {
+	return c.name
+}
+
+func (c *memoryContainer) Name() string {
+	return c.name
+}
+
+func (c *memoryContainer) Item(id string) (stow.Item, error) {
+	c.location.mu.RLock()
+	defer c.location.mu.RUnlock()
+	i, ok := c.items[id]
+	if !ok {
+		return nil, stow.ErrNotFound
+	}
+	return i, nil
+}
+
+func (c *memoryContainer) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	c.location.mu.RLock()
+	defer c.location.mu.RUnlock()
+	names := make([]string, 0, len(c.items))
+	for name := range c.items {
+		names = append(names, name)
+	}
+	page, next := pageNames(names, prefix, cursor, count)
+	items := make([]stow.Item, 0, len(page))
+	for _, name := range page {
+		items = append(items, c.items[name])
+	}
+	return items, next, nil
+}
+
+// RemoveItem deletes the item. Like S3, removing a missing item is not an
+// error.
+func (c *memoryContainer) RemoveItem(id string) error {
+	c.location.mu.Lock()
+	defer c.location.mu.Unlock()
+	delete(c.items, id)
+	return nil
+}
+
+// Put stores the data read from r. The size is only a hint; callers such as
+// location.Write do not know it up front.
+func (c *memoryContainer) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read data for %s", name)
+	}
+	md := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		md[k] = v
+	}
+	i := &memoryItem{
+		container: c,
+		name:      name,
+		data:      data,
+		etag:      fmt.Sprintf("%x", md5.Sum(data)),
+		metadata:  md,
+		lastMod:   time.Now().UTC(),
+	}
+	c.location.mu.Lock()
+	defer c.location.mu.Unlock()
+	c.items[name] = i
+	return i, nil
+}
+
+var _ stow.Item = (*memoryItem)(nil)
+
+// memoryItem is immutable once stored. Overwriting an object replaces the
+// item, so readers holding the old one are unaffected.
+type memoryItem struct {
+	container *memoryContainer
+	name      string
+	data      []byte
+	etag      string
+	metadata  map[string]interface{}
+	lastMod   time.Time
+}
+
+func (i *memoryItem) ID() string {
+	return i.name
+}
+
+func (i *memoryItem) Name() string {
+	return i.name
+}
+
+func (i *memoryItem) URL() *url.URL {
+	return &url.URL{
+		Scheme: memoryScheme,
+		Host:   i.container.location.name,
+		Path:   "/" + i.container.name + "/" + i.name,
+	}
+}
+
+func (i *memoryItem) Size() (int64, error) {
+	return int64(len(i.data)), nil
+}
+
+func (i *memoryItem) Open() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(i.data)), nil
+}
+
+func (i *memoryItem) ETag() (string, error) {
+	return i.etag, nil
+}
+
+func (i *memoryItem) LastMod() (time.Time, error) {
+	return i.lastMod, nil
+}
+
+func (i *memoryItem) Metadata() (map[string]interface{}, error) {
+	md := make(map[string]interface{}, len(i.metadata))
+	for k, v := range i.metadata {
+		md[k] = v
+	}
+	return md, nil
+}
+
+// pageNames sorts names and returns at most count of them that match prefix
+// and sort after cursor, along with the cursor for the next page. An empty
+// cursor marks the last page.
+func pageNames(names []string, prefix, cursor string, count int) ([]string, string) {
+	sort.Strings(names)
+	page := make([]string, 0, count)
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || (cursor != stow.CursorStart && name <= cursor) {
+			continue
+		}
+		if len(page) == count {
+			return page, page[len(page)-1]
+		}
+		page = append(page, name)
+	}
+	return page, ""
+}
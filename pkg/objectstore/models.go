@@ -20,7 +20,8 @@ type ProviderConfig struct {
 	Type ProviderType
 	// Endpoint used to access the object store. It can be implicit for
 	// stores from certain cloud providers such as AWS. In that case it can
-	// be empty
+	// be empty. For the Filesystem provider it is the root directory and
+	// for the Memory provider it names the in-memory store
 	Endpoint string
 	// If true, disable SSL verification. If false (the default), SSL
 	// verification is enabled.
@@ -74,6 +74,10 @@ type Directory interface {
 	// ListObjects lists all the objects rooted in the current directory
 	ListObjects(context.Context) ([]string, error)
 
+	// ListObjectsPage lists a page of the objects under the current
+	// directory, including sub directories, that match a prefix
+	ListObjectsPage(context.Context, ListOptions) (*ObjectPage, error)
+
 	// Get returns the io interface to read object data
 	Get(context.Context, string) (io.ReadCloser, map[string]string, error)
 
@@ -86,6 +90,10 @@ type Directory interface {
 	// Put persists bytes in the named object
 	PutBytes(context.Context, string, []byte, map[string]string) error
 
+	// Upload persists data from the Reader interface in the named object
+	// using a multipart upload. The length of the data need not be known.
+	Upload(context.Context, string, io.Reader, map[string]string, UploadOptions) error
+
 	// Delete removes the object
 	Delete(context.Context, string) error
 
@@ -108,7 +116,18 @@ func NewProvider(ctx context.Context, config ProviderConfig, secret *Secret) (Pr
 
 // Supported returns true if the object store type is supported
 func Supported(t ProviderType) bool {
-	return t == ProviderTypeS3 || t == ProviderTypeGCS || t == ProviderTypeAzure
+	switch t {
+	case ProviderTypeS3, ProviderTypeGCS, ProviderTypeAzure, ProviderTypeFilesystem, ProviderTypeMemory:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsObjectNotFoundError returns true if the error indicates that the object
+// does not exist
+func IsObjectNotFoundError(err error) bool {
+	return err != nil && errors.Cause(err) == stow.ErrNotFound
 }
 
 func s3Config(ctx context.Context, config ProviderConfig, secret *Secret, region string) (stowKind string, stowConfig stow.Config, err error) {
@@ -197,6 +216,10 @@ func getConfig(ctx context.Context, config ProviderConfig, secret *Secret, regio
 		return gcsConfig(ctx, secret)
 	case ProviderTypeAzure:
 		return azureConfig(ctx, secret)
+	case ProviderTypeFilesystem:
+		return filesystemKind, stow.ConfigMap{filesystemConfigPath: config.Endpoint}, nil
+	case ProviderTypeMemory:
+		return memoryKind, stow.ConfigMap{memoryConfigStore: config.Endpoint}, nil
 	default:
 		return "", nil, errors.Errorf("unknown or unimplemented object store type %s", config.Type)
 	}
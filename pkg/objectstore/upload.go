@@ -0,0 +1,297 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Multipart uploads
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/graymeta/stow"
+	"github.com/jpillora/backoff"
+	"github.com/pkg/errors"
+
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+const (
+	// DefaultUploadPartSize is the size of the first parts of a multipart
+	// upload unless specified otherwise. Up to Concurrency+1 parts are
+	// buffered in memory.
+	DefaultUploadPartSize int64 = 16 * 1024 * 1024
+	// DefaultUploadConcurrency is the number of parts uploaded in parallel
+	// unless specified otherwise.
+	DefaultUploadConcurrency = 4
+	// DefaultUploadRetries is the number of times a part is retried unless
+	// specified otherwise.
+	DefaultUploadRetries = 3
+	// S3 rejects parts, other than the last one, smaller than 5MiB.
+	minUploadPartSize int64 = 5 * 1024 * 1024
+	// S3 rejects parts larger than 5GiB.
+	maxUploadPartSize int64 = 5 * 1024 * 1024 * 1024
+	// S3 allows up to 10,000 parts and Azure up to 50,000 blocks.
+	maxUploadParts = 10000
+	// The part size doubles every uploadPartsPerSize parts so that large
+	// streams fit in maxUploadParts. Even with the minimum part size, that is
+	// more than the 5TiB S3 allows per object.
+	uploadPartsPerSize = 1000
+)
+
+// ProgressFunc is called with the total number of bytes uploaded so far.
+type ProgressFunc func(uploaded int64)
+
+// UploadOptions configure Directory.Upload. Zero values select the defaults.
+type UploadOptions struct {
+	// PartSize is the size of the first parts. It must be at least 5MiB.
+	// It doubles every 1,000 parts, up to 5GiB.
+	PartSize int64
+	// Concurrency is the number of parts uploaded in parallel. GCS uploads
+	// are resumable but sequential and ignore it.
+	Concurrency int
+	// Retries is the number of times a part is retried after a failure
+	// before the upload is aborted.
+	Retries int
+	// Progress, if set, is called as parts complete. It is never called
+	// concurrently.
+	Progress ProgressFunc
+}
+
+func (o UploadOptions) withDefaults() (UploadOptions, error) {
+	switch {
+	case o.PartSize == 0:
+		o.PartSize = DefaultUploadPartSize
+	case o.PartSize < minUploadPartSize:
+		return o, errors.Errorf("upload part size %d is smaller than the minimum %d", o.PartSize, minUploadPartSize)
+	}
+	switch {
+	case o.Concurrency == 0:
+		o.Concurrency = DefaultUploadConcurrency
+	case o.Concurrency < 0:
+		return o, errors.Errorf("invalid upload concurrency %d", o.Concurrency)
+	}
+	switch {
+	case o.Retries == 0:
+		o.Retries = DefaultUploadRetries
+	case o.Retries < 0:
+		return o, errors.Errorf("invalid number of upload retries %d", o.Retries)
+	}
+	return o, nil
+}
+
+// uploader uploads a stream of unknown length to an object.
+type uploader interface {
+	upload(ctx context.Context, name string, r io.Reader, metadata map[string]interface{}, opts UploadOptions) error
+}
+
+// newUploader returns the uploader for the bucket's provider. Providers
+// without a native multipart API stream the data through Stow.
+func newUploader(ctx context.Context, b *bucket) (uploader, error) {
+	if b.provider == nil {
+		return &streamUploader{container: b.container}, nil
+	}
+	switch b.provider.config.Type {
+	case ProviderTypeS3:
+		return newS3Uploader(ctx, b)
+	case ProviderTypeGCS:
+		return newGCSUploader(ctx, b)
+	case ProviderTypeAzure:
+		return newAzureUploader(ctx, b)
+	default:
+		return &streamUploader{container: b.container}, nil
+	}
+}
+
+var _ uploader = (*streamUploader)(nil)
+
+// streamUploader copies the stream into a single Stow Put.
+type streamUploader struct {
+	container stow.Container
+}
+
+func (u *streamUploader) upload(ctx context.Context, name string, r io.Reader, metadata map[string]interface{}, opts UploadOptions) error {
+	pr := &progressReader{r: r, progress: opts.Progress}
+	_, err := u.container.Put(name, pr, 0, metadata)
+	return err
+}
+
+// progressReader reports the number of bytes read so far.
+type progressReader struct {
+	r        io.Reader
+	n        int64
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.n += int64(n)
+		if p.progress != nil {
+			p.progress(p.n)
+		}
+	}
+	return n, err
+}
+
+// partUpload is a provider's multipart upload session. uploadPart is called
+// concurrently with 1-based part numbers.
+type partUpload interface {
+	uploadPart(ctx context.Context, num int, data []byte) error
+	complete(ctx context.Context, parts int) error
+	abort(ctx context.Context) error
+}
+
+// uploadParts reads r in parts of growing size, starting at opts.PartSize, and
+// uploads them through the session returned by begin, retrying each failed part with backoff. Data that
+// fits in a single part is written with put instead, so small objects do not
+// pay for a multipart session. At most opts.Concurrency parts are buffered
+// while in flight.
+func uploadParts(ctx context.Context, r io.Reader, opts UploadOptions, put func([]byte) error, begin func(context.Context) (partUpload, error)) error {
+	data, last, err := readPart(r, uploadPartSize(opts.PartSize, 1))
+	if err != nil {
+		return err
+	}
+	if last {
+		if err := put(data); err != nil {
+			return err
+		}
+		if opts.Progress != nil {
+			opts.Progress(int64(len(data)))
+		}
+		return nil
+	}
+	pu, err := begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to start multipart upload")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		uploaded int64
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	sem := make(chan struct{}, opts.Concurrency)
+	parts := 0
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		parts++
+		wg.Add(1)
+		go func(num int, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := poll.WaitWithBackoffWithRetries(ctx, backoff.Backoff{}, opts.Retries, poll.IsAlwaysRetryable, func(ctx context.Context) (bool, error) {
+				if err := pu.uploadPart(ctx, num, data); err != nil {
+					return false, err
+				}
+				return true, nil
+			})
+			if err != nil {
+				setErr(errors.Wrapf(err, "failed to upload part %d", num))
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			uploaded += int64(len(data))
+			if opts.Progress != nil {
+				opts.Progress(uploaded)
+			}
+		}(parts, data)
+		if last {
+			break
+		}
+		if data, last, err = readPart(r, uploadPartSize(opts.PartSize, parts+1)); err != nil {
+			setErr(err)
+			break
+		}
+		if last && len(data) == 0 {
+			// The previous part ended exactly at the end of the stream
+			break
+		}
+		if parts == maxUploadParts {
+			setErr(errors.Errorf("data to upload exceeds the maximum of %d parts", maxUploadParts))
+			break
+		}
+	}
+	wg.Wait()
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = errors.Wrap(ctx.Err(), "upload cancelled")
+	}
+	if firstErr != nil {
+		// The caller's context may be the reason for the failure
+		if err := pu.abort(context.Background()); err != nil {
+			return errors.Wrapf(firstErr, "failed to abort upload: %v", err)
+		}
+		return firstErr
+	}
+	return errors.Wrap(pu.complete(ctx, parts), "failed to complete multipart upload")
+}
+
+// uploadPartSize returns the size of the num'th part.
+func uploadPartSize(size int64, num int) int64 {
+	for i := uploadPartsPerSize; i < num && size < maxUploadPartSize; i += uploadPartsPerSize {
+		size *= 2
+	}
+	if size > maxUploadPartSize {
+		return maxUploadPartSize
+	}
+	return size
+}
+
+// readPart reads up to size bytes. last is true when r is exhausted; data is
+// empty if there was nothing left to read. The part is only allocated once
+// the first bytes.MinRead bytes have been read, so that small streams don't
+// allocate a full part.
+func readPart(r io.Reader, size int64) (data []byte, last bool, err error) {
+	var buf bytes.Buffer
+	first := int64(bytes.MinRead)
+	if first > size {
+		first = size
+	}
+	_, err = io.CopyN(&buf, r, first)
+	if err == nil && size > first {
+		// Growing by size rather than size-first leaves room for the read
+		// that detects the end of the part, which would otherwise double
+		// the buffer.
+		buf.Grow(int(size))
+		_, err = io.CopyN(&buf, r, size-first)
+	}
+	switch err {
+	case nil:
+		return buf.Bytes(), false, nil
+	case io.EOF:
+		return buf.Bytes(), true, nil
+	default:
+		return nil, false, errors.Wrap(err, "failed to read data to upload")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	az "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/graymeta/stow"
+	stowaz "github.com/graymeta/stow/azure"
+	"github.com/pkg/errors"
+)
+
+var _ uploader = (*azureUploader)(nil)
+
+// azureUploader stages blocks and commits them as a block blob.
+type azureUploader struct {
+	client    az.BlobStorageClient
+	container stow.Container
+}
+
+func newAzureUploader(ctx context.Context, b *bucket) (uploader, error) {
+	_, cfg, err := getConfig(ctx, b.provider.config, b.provider.secret, b.region)
+	if err != nil {
+		return nil, err
+	}
+	account, _ := cfg.Config(stowaz.ConfigAccount)
+	key, _ := cfg.Config(stowaz.ConfigKey)
+	client, err := az.NewBasicClient(account, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Azure storage client")
+	}
+	return &azureUploader{
+		client:    client.GetBlobService(),
+		container: b.container,
+	}, nil
+}
+
+func (u *azureUploader) upload(ctx context.Context, name string, r io.Reader, metadata map[string]interface{}, opts UploadOptions) error {
+	put := func(data []byte) error {
+		_, err := u.container.Put(name, bytes.NewReader(data), int64(len(data)), metadata)
+		return err
+	}
+	begin := func(ctx context.Context) (partUpload, error) {
+		md := make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			if s, ok := v.(string); ok {
+				md[k] = s
+			}
+		}
+		return &azurePartUpload{
+			client:    u.client,
+			container: u.container.ID(),
+			// Match the blob naming used by Stow
+			name:     strings.Replace(name, " ", "+", -1),
+			metadata: md,
+		}, nil
+	}
+	return uploadParts(ctx, r, opts, put, begin)
+}
+
+var _ partUpload = (*azurePartUpload)(nil)
+
+type azurePartUpload struct {
+	client    az.BlobStorageClient
+	container string
+	name      string
+	metadata  map[string]string
+}
+
+func (u *azurePartUpload) blob() *az.Blob {
+	return u.client.GetContainerReference(u.container).GetBlobReference(u.name)
+}
+
+// azureBlockID returns the ID of the num'th block. IDs within a blob must all
+// have the same length.
+func azureBlockID(num int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%010d", num)))
+}
+
+func (u *azurePartUpload) uploadPart(ctx context.Context, num int, data []byte) error {
+	return u.blob().PutBlock(azureBlockID(num), data, nil)
+}
+
+func (u *azurePartUpload) complete(ctx context.Context, parts int) error {
+	blocks := make([]az.Block, 0, parts)
+	for i := 1; i <= parts; i++ {
+		blocks = append(blocks, az.Block{ID: azureBlockID(i), Status: az.BlockStatusUncommitted})
+	}
+	blob := u.blob()
+	blob.Metadata = u.metadata
+	return blob.PutBlockList(blocks, nil)
+}
+
+// abort is a no-op. Azure discards uncommitted blocks after a week.
+func (u *azurePartUpload) abort(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"io"
+
+	stowgcs "github.com/graymeta/stow/google"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	storage "google.golang.org/api/storage/v1"
+)
+
+var _ uploader = (*gcsUploader)(nil)
+
+// gcsUploader uses GCS resumable uploads. Each chunk is retried by the
+// client library, which resumes the upload from the last committed byte.
+type gcsUploader struct {
+	service *storage.Service
+	bucket  string
+}
+
+func newGCSUploader(ctx context.Context, b *bucket) (uploader, error) {
+	_, cfg, err := getConfig(ctx, b.provider.config, b.provider.secret, b.region)
+	if err != nil {
+		return nil, err
+	}
+	var ts oauth2.TokenSource
+	if configJSON, _ := cfg.Config(stowgcs.ConfigJSON); configJSON != "" {
+		jwtConf, err := google.JWTConfigFromJSON([]byte(configJSON), storage.DevstorageReadWriteScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse GCS service key")
+		}
+		ts = jwtConf.TokenSource(ctx)
+	} else {
+		creds, err := google.FindDefaultCredentials(ctx, storage.DevstorageReadWriteScope)
+		if err != nil {
+			return nil, err
+		}
+		ts = creds.TokenSource
+	}
+	service, err := storage.New(oauth2.NewClient(ctx, ts))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GCS client")
+	}
+	return &gcsUploader{
+		service: service,
+		bucket:  b.container.Name(),
+	}, nil
+}
+
+func (u *gcsUploader) upload(ctx context.Context, name string, r io.Reader, metadata map[string]interface{}, opts UploadOptions) error {
+	md := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		if s, ok := v.(string); ok {
+			md[k] = s
+		}
+	}
+	call := u.service.Objects.Insert(u.bucket, &storage.Object{Name: name, Metadata: md}).
+		Media(r, googleapi.ChunkSize(int(opts.PartSize))).
+		Context(ctx)
+	if opts.Progress != nil {
+		call = call.ProgressUpdater(func(current, _ int64) {
+			opts.Progress(current)
+		})
+	}
+	_, err := call.Do()
+	return err
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/graymeta/stow"
+	stows3 "github.com/graymeta/stow/s3"
+	"github.com/pkg/errors"
+)
+
+var _ uploader = (*s3Uploader)(nil)
+
+// s3Uploader uses S3 multipart uploads.
+type s3Uploader struct {
+	client    *s3.S3
+	bucket    string
+	container stow.Container
+}
+
+func newS3Uploader(ctx context.Context, b *bucket) (uploader, error) {
+	_, cfg, err := getConfig(ctx, b.provider.config, b.provider.secret, b.region)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s3ClientFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Uploader{
+		client:    client,
+		bucket:    b.container.Name(),
+		container: b.container,
+	}, nil
+}
+
+// s3ClientFromConfig creates a client configured the same way as Stow's.
+func s3ClientFromConfig(cfg stow.Config) (*s3.S3, error) {
+	accessKeyID, _ := cfg.Config(stows3.ConfigAccessKeyID)
+	secretKey, _ := cfg.Config(stows3.ConfigSecretKey)
+	token, _ := cfg.Config(stows3.ConfigToken)
+
+	httpClient := &http.Client{}
+	if skip, ok := cfg.Config(stows3.ConfigInsecureSkipSSLVerify); ok && skip == "true" {
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	awsConfig := aws.NewConfig().
+		WithHTTPClient(httpClient).
+		WithCredentials(credentials.NewStaticCredentials(accessKeyID, secretKey, token)).
+		WithRegion("us-east-1")
+	if region, ok := cfg.Config(stows3.ConfigRegion); ok {
+		awsConfig.WithRegion(region)
+	}
+	if endpoint, ok := cfg.Config(stows3.ConfigEndpoint); ok {
+		awsConfig.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	s, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create S3 session")
+	}
+	return s3.New(s), nil
+}
+
+func (u *s3Uploader) upload(ctx context.Context, name string, r io.Reader, metadata map[string]interface{}, opts UploadOptions) error {
+	put := func(data []byte) error {
+		_, err := u.container.Put(name, bytes.NewReader(data), int64(len(data)), metadata)
+		return err
+	}
+	begin := func(ctx context.Context) (partUpload, error) {
+		md := make(map[string]*string, len(metadata))
+		for k, v := range metadata {
+			if s, ok := v.(string); ok {
+				md[k] = aws.String(s)
+			}
+		}
+		out, err := u.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:   aws.String(u.bucket),
+			Key:      aws.String(name),
+			Metadata: md,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &s3PartUpload{
+			client:   u.client,
+			bucket:   u.bucket,
+			key:      name,
+			uploadID: aws.StringValue(out.UploadId),
+		}, nil
+	}
+	return uploadParts(ctx, r, opts, put, begin)
+}
+
+var _ partUpload = (*s3PartUpload)(nil)
+
+type s3PartUpload struct {
+	client   *s3.S3
+	bucket   string
+	key      string
+	uploadID string
+	mu       sync.Mutex
+	parts    []*s3.CompletedPart
+}
+
+func (u *s3PartUpload) uploadPart(ctx context.Context, num int, data []byte) error {
+	out, err := u.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(u.bucket),
+		Key:           aws.String(u.key),
+		UploadId:      aws.String(u.uploadID),
+		PartNumber:    aws.Int64(int64(num)),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.parts = append(u.parts, &s3.CompletedPart{
+		ETag:       out.ETag,
+		PartNumber: aws.Int64(int64(num)),
+	})
+	return nil
+}
+
+func (u *s3PartUpload) complete(ctx context.Context, parts int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.parts) != parts {
+		return errors.Errorf("expected %d uploaded parts, found %d", parts, len(u.parts))
+	}
+	sort.Slice(u.parts, func(i, j int) bool {
+		return aws.Int64Value(u.parts[i].PartNumber) < aws.Int64Value(u.parts[j].PartNumber)
+	})
+	_, err := u.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.bucket),
+		Key:             aws.String(u.key),
+		UploadId:        aws.String(u.uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: u.parts},
+	})
+	return err
+}
+
+func (u *s3PartUpload) abort(ctx context.Context) error {
+	_, err := u.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.bucket),
+		Key:      aws.String(u.key),
+		UploadId: aws.String(u.uploadID),
+	})
+	return err
+}
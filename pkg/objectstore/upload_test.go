@@ -0,0 +1,229 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+)
+
+type UploadSuite struct{}
+
+var _ = Suite(&UploadSuite{})
+
+// fakePartUpload records uploaded parts and fails each part the configured
+// number of times before accepting it.
+type fakePartUpload struct {
+	mu        sync.Mutex
+	failures  int
+	attempts  map[int]int
+	parts     map[int][]byte
+	completed int
+	aborted   bool
+}
+
+func newFakePartUpload(failures int) *fakePartUpload {
+	return &fakePartUpload{
+		failures: failures,
+		attempts: make(map[int]int),
+		parts:    make(map[int][]byte),
+	}
+}
+
+func (f *fakePartUpload) uploadPart(ctx context.Context, num int, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts[num]++
+	if f.attempts[num] <= f.failures {
+		return errors.New("transient failure")
+	}
+	f.parts[num] = append([]byte(nil), data...)
+	return nil
+}
+
+func (f *fakePartUpload) complete(ctx context.Context, parts int) error {
+	f.completed = parts
+	return nil
+}
+
+func (f *fakePartUpload) abort(ctx context.Context) error {
+	f.aborted = true
+	return nil
+}
+
+func (f *fakePartUpload) data() []byte {
+	nums := make([]int, 0, len(f.parts))
+	for num := range f.parts {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	var data []byte
+	for _, num := range nums {
+		data = append(data, f.parts[num]...)
+	}
+	return data
+}
+
+func (s *UploadSuite) TestUploadParts(c *C) {
+	ctx := context.Background()
+	const partSize = 5 * 1024 * 1024
+	for _, tc := range []struct {
+		size     int
+		failures int
+		retries  int
+		parts    int
+		put      bool
+		checker  Checker
+	}{
+		{size: 0, put: true, checker: IsNil},
+		{size: partSize - 1, put: true, checker: IsNil},
+		{size: partSize, parts: 1, checker: IsNil},
+		{size: 2 * partSize, parts: 2, checker: IsNil},
+		{size: 3*partSize + 7, parts: 4, checker: IsNil},
+		{size: 2*partSize + 1, failures: 1, retries: 1, parts: 3, checker: IsNil},
+		{size: 2*partSize + 1, failures: 2, retries: 1, checker: NotNil},
+	} {
+		data := make([]byte, tc.size)
+		_, _ = rand.Read(data)
+		fpu := newFakePartUpload(tc.failures)
+		var putData []byte
+		put := func(d []byte) error {
+			putData = d
+			return nil
+		}
+		begin := func(context.Context) (partUpload, error) {
+			return fpu, nil
+		}
+		var progress []int64
+		opts := UploadOptions{
+			PartSize:    partSize,
+			Concurrency: 2,
+			Retries:     tc.retries,
+			Progress:    func(n int64) { progress = append(progress, n) },
+		}
+		err := uploadParts(ctx, bytes.NewReader(data), opts, put, begin)
+		c.Assert(err, tc.checker, Commentf("size %d", tc.size))
+		if err != nil {
+			c.Check(fpu.aborted, Equals, true)
+			continue
+		}
+		c.Assert(progress, Not(HasLen), 0)
+		c.Check(progress[len(progress)-1], Equals, int64(tc.size))
+		c.Check(sort.SliceIsSorted(progress, func(i, j int) bool { return progress[i] < progress[j] }), Equals, true)
+		if tc.put {
+			c.Check(putData, DeepEquals, data)
+			c.Check(fpu.parts, HasLen, 0)
+			continue
+		}
+		c.Check(putData, IsNil)
+		c.Check(fpu.completed, Equals, tc.parts)
+		c.Check(fpu.parts, HasLen, tc.parts)
+		c.Check(fpu.data(), DeepEquals, data)
+	}
+}
+
+func (s *UploadSuite) TestUploadPartsLimit(c *C) {
+	ctx := context.Background()
+	// Parts of 1, 2, 4, ... 512 bytes, 1000 of each size
+	const limit = 1023 * uploadPartsPerSize
+	for _, tc := range []struct {
+		size    int
+		checker Checker
+	}{
+		{size: limit, checker: IsNil},
+		{size: limit + 1, checker: NotNil},
+	} {
+		fpu := newFakePartUpload(0)
+		put := func([]byte) error { return nil }
+		begin := func(context.Context) (partUpload, error) {
+			return fpu, nil
+		}
+		opts := UploadOptions{PartSize: 1, Concurrency: 4, Retries: 1}
+		err := uploadParts(ctx, bytes.NewReader(make([]byte, tc.size)), opts, put, begin)
+		c.Assert(err, tc.checker, Commentf("size %d", tc.size))
+		if err != nil {
+			c.Check(fpu.aborted, Equals, true)
+			continue
+		}
+		c.Check(fpu.completed, Equals, maxUploadParts)
+	}
+}
+
+func (s *UploadSuite) TestUploadPartSize(c *C) {
+	const size = 16 * 1024 * 1024
+	for _, tc := range []struct {
+		num  int
+		size int64
+	}{
+		{num: 1, size: size},
+		{num: uploadPartsPerSize, size: size},
+		{num: uploadPartsPerSize + 1, size: 2 * size},
+		{num: 2*uploadPartsPerSize + 1, size: 4 * size},
+		{num: maxUploadParts, size: maxUploadPartSize},
+	} {
+		c.Check(uploadPartSize(size, tc.num), Equals, tc.size, Commentf("part %d", tc.num))
+	}
+}
+
+func (s *UploadSuite) TestUploadOptionsDefaults(c *C) {
+	opts, err := UploadOptions{}.withDefaults()
+	c.Assert(err, IsNil)
+	c.Check(opts.PartSize, Equals, DefaultUploadPartSize)
+	c.Check(opts.Concurrency, Equals, DefaultUploadConcurrency)
+	c.Check(opts.Retries, Equals, DefaultUploadRetries)
+
+	for _, opts := range []UploadOptions{
+		{PartSize: 1024},
+		{Concurrency: -1},
+		{Retries: -1},
+	} {
+		_, err := opts.withDefaults()
+		c.Check(err, NotNil)
+	}
+}
+
+func (s *UploadSuite) TestReadPart(c *C) {
+	const size = 64 * 1024 * 1024
+	data, last, err := readPart(bytes.NewReader([]byte("small")), size)
+	c.Assert(err, IsNil)
+	c.Assert(last, Equals, true)
+	c.Assert(string(data), Equals, "small")
+	// Small streams don't allocate a full part.
+	c.Assert(cap(data) < size, Equals, true)
+
+	r := bytes.NewReader(bytes.Repeat([]byte("a"), 10))
+	data, last, err = readPart(r, 10)
+	c.Assert(err, IsNil)
+	c.Assert(last, Equals, false)
+	c.Assert(data, HasLen, 10)
+	data, last, err = readPart(r, 10)
+	c.Assert(err, IsNil)
+	c.Assert(last, Equals, true)
+	c.Assert(data, HasLen, 0)
+
+	// Full parts are allocated once.
+	r = bytes.NewReader(make([]byte, 2*size))
+	data, last, err = readPart(r, size)
+	c.Assert(err, IsNil)
+	c.Assert(last, Equals, false)
+	c.Assert(data, HasLen, size)
+	c.Assert(cap(data) < 2*size, Equals, true)
+}
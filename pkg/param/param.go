@@ -82,9 +82,11 @@ type NamespaceParams struct {
 
 // Profile contains where to store artifacts and how to access them.
 type Profile struct {
-	Location      crv1alpha1.Location
-	Credential    Credential
-	SkipSSLVerify bool
+	Location           crv1alpha1.Location
+	Credential         Credential
+	SkipSSLVerify      bool
+	EncryptionKey      []byte
+	RepositoryPassword []byte
 }
 
 // CredentialType
@@ -199,15 +201,45 @@ func fetchProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	cred, err := fetchCredential(ctx, cli, p.Credential)
+	prof := &Profile{
+		Location:      p.Location,
+		SkipSSLVerify: p.SkipSSLVerify,
+	}
+	// Filesystem locations may not need any credentials
+	if p.Location.Type != crv1alpha1.LocationTypeFilesystem || p.Credential.Type != "" {
+		cred, err := fetchCredential(ctx, cli, p.Credential)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		prof.Credential = *cred
+	}
+	if p.EncryptionKey != nil {
+		key, err := fetchEncryptionKey(ctx, cli, p.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		prof.EncryptionKey = key
+	}
+	if p.RepositoryPassword != nil {
+		password, err := fetchEncryptionKey(ctx, cli, p.RepositoryPassword)
+		if err != nil {
+			return nil, err
+		}
+		prof.RepositoryPassword = password
+	}
+	return prof, nil
+}
+
+func fetchEncryptionKey(ctx context.Context, cli kubernetes.Interface, ek *crv1alpha1.EncryptionKey) ([]byte, error) {
+	s, err := cli.CoreV1().Secrets(ek.Secret.Namespace).Get(ek.Secret.Name, metav1.GetOptions{})
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	return &Profile{
-		Location:      p.Location,
-		Credential:    *cred,
-		SkipSSLVerify: p.SkipSSLVerify,
-	}, nil
+	key, ok := s.Data[ek.KeyField]
+	if !ok {
+		return nil, errors.Errorf("Encryption key '%s' not found in secret '%s:%s'", ek.KeyField, s.GetNamespace(), s.GetName())
+	}
+	return key, nil
 }
 
 func fetchCredential(ctx context.Context, cli kubernetes.Interface, c crv1alpha1.Credential) (*Credential, error) {
@@ -430,6 +430,61 @@ func (s *ParamsSuite) TestfetchKVSecretCredential(c *C) {
 	}
 }
 
+func (s *ParamsSuite) TestFetchEncryptionKey(c *C) {
+	ctx := context.Background()
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "keySecret",
+			Namespace: s.namespace,
+		},
+		Data: map[string][]byte{
+			"myKey": []byte("0123456789abcdef0123456789abcdef"),
+		},
+	}
+	cli := fake.NewSimpleClientset(secret)
+	for _, tc := range []struct {
+		ek      *crv1alpha1.EncryptionKey
+		key     []byte
+		checker Checker
+	}{
+		{
+			ek: &crv1alpha1.EncryptionKey{
+				KeyField: "myKey",
+				Secret: crv1alpha1.ObjectReference{
+					Name:      "keySecret",
+					Namespace: s.namespace,
+				},
+			},
+			key:     []byte("0123456789abcdef0123456789abcdef"),
+			checker: IsNil,
+		},
+		{
+			ek: &crv1alpha1.EncryptionKey{
+				KeyField: "missing",
+				Secret: crv1alpha1.ObjectReference{
+					Name:      "keySecret",
+					Namespace: s.namespace,
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			ek: &crv1alpha1.EncryptionKey{
+				KeyField: "myKey",
+				Secret: crv1alpha1.ObjectReference{
+					Name:      "missing",
+					Namespace: s.namespace,
+				},
+			},
+			checker: NotNil,
+		},
+	} {
+		key, err := fetchEncryptionKey(ctx, cli, tc.ek)
+		c.Assert(err, tc.checker)
+		c.Assert(key, DeepEquals, tc.key)
+	}
+}
+
 func (s *ParamsSuite) TestProfile(c *C) {
 	ss := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -16,6 +16,8 @@ package kanister
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/pkg/errors"
@@ -23,6 +25,7 @@ import (
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/field"
 	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/metrics"
 	"github.com/kanisterio/kanister/pkg/param"
 )
 
@@ -31,6 +34,8 @@ type Phase struct {
 	name    string
 	args    map[string]interface{}
 	objects map[string]crv1alpha1.ObjectReference
+	retry   *crv1alpha1.RetryPolicy
+	deps    []string
 	f       Func
 }
 
@@ -39,6 +44,12 @@ func (p *Phase) Name() string {
 	return p.name
 }
 
+// DependsOn returns the names of the phases that must complete before this
+// phase is executed.
+func (p *Phase) DependsOn() []string {
+	return p.deps
+}
+
 // Objects returns the phase object references
 func (p *Phase) Objects() map[string]crv1alpha1.ObjectReference {
 	return p.objects
@@ -48,44 +59,182 @@ func (p *Phase) Objects() map[string]crv1alpha1.ObjectReference {
 // those arguments.
 func (p *Phase) Exec(ctx context.Context, bp crv1alpha1.Blueprint, action string, tp param.TemplateParams) (map[string]interface{}, error) {
 	if p.args == nil {
-		// Get the action from Blueprint
-		a, ok := bp.Actions[action]
-		if !ok {
-			return nil, errors.Errorf("Action {%s} not found in action map", action)
+		args, err := p.RenderArgs(bp, action, tp)
+		if err != nil {
+			return nil, err
+		}
+		p.args = args
+	}
+	// Execute the function
+	start := time.Now()
+	out, err := p.f.Exec(ctx, tp, p.args)
+	metrics.PhaseDuration.WithLabelValues(p.f.Name()).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.FunctionErrors.WithLabelValues(p.f.Name()).Inc()
+	}
+	return out, err
+}
+
+// RenderArgs renders the argument templates in this Phase's Func and checks
+// that the required arguments are present. It does not execute the Func.
+func (p *Phase) RenderArgs(bp crv1alpha1.Blueprint, action string, tp param.TemplateParams) (map[string]interface{}, error) {
+	// Get the action from Blueprint
+	a, ok := bp.Actions[action]
+	if !ok {
+		return nil, errors.Errorf("Action {%s} not found in action map", action)
+	}
+	phases := a.Phases
+	if a.DeferPhase != nil {
+		phases = append(phases[:len(phases):len(phases)], *a.DeferPhase)
+	}
+	// Render the argument templates for the Phase's function
+	var args map[string]interface{}
+	for _, ap := range phases {
+		if ap.Name != p.name {
+			continue
+		}
+		rArgs, err := param.RenderArgs(ap.Args, tp)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkRequiredArgs(p.f.RequiredArgs(), rArgs); err != nil {
+			return nil, errors.Wrapf(err, "Required args missing for function %s", p.f.Name())
 		}
-		// Render the argument templates for the Phase's function
-		for _, ap := range a.Phases {
-			if ap.Name != p.name {
-				continue
+		args = rArgs
+	}
+	return args, nil
+}
+
+// PlaceholderOutput returns the output assumed for this phase when it is not
+// executed. Each output key declared by the phase's Func is mapped to a
+// placeholder naming the phase and the key.
+func (p *Phase) PlaceholderOutput() map[string]interface{} {
+	fo, ok := p.f.(FuncOutputs)
+	if !ok {
+		return nil
+	}
+	out := make(map[string]interface{}, len(fo.Outputs()))
+	for _, k := range fo.Outputs() {
+		out[k] = fmt.Sprintf("<%s.%s>", p.name, k)
+	}
+	return out
+}
+
+// GetPhases renders the returns a list of Phases with pre-rendered arguments.
+func GetPhases(bp crv1alpha1.Blueprint, action, version string, tp param.TemplateParams) ([]*Phase, error) {
+	a, ok := bp.Actions[action]
+	if !ok {
+		return nil, errors.Errorf("Action {%s} not found in action map", action)
+	}
+	funcMu.RLock()
+	defer funcMu.RUnlock()
+	funcVersion, err := resolveFuncVersion(a.Phases, version)
+	if err != nil {
+		return nil, err
+	}
+	phases := make([]*Phase, 0, len(a.Phases))
+	for _, p := range a.Phases {
+		phase, err := newPhase(p, funcVersion, tp)
+		if err != nil {
+			return nil, err
+		}
+		phases = append(phases, phase)
+	}
+	if _, err := PhaseDependencies(phases); err != nil {
+		return nil, err
+	}
+	return phases, nil
+}
+
+// PhaseDependencies returns, for each phase, the indices of the phases it
+// depends on. If none of the phases declare dependencies, each phase depends
+// on the previous one. An error is returned if a dependency is unknown or if
+// the dependencies contain a cycle.
+func PhaseDependencies(phases []*Phase) ([][]int, error) {
+	deps := make([][]int, len(phases))
+	idx := make(map[string]int, len(phases))
+	declared := false
+	for i, p := range phases {
+		idx[p.name] = i
+		declared = declared || len(p.deps) != 0
+	}
+	if !declared {
+		for i := 1; i < len(phases); i++ {
+			deps[i] = []int{i - 1}
+		}
+		return deps, nil
+	}
+	for i, p := range phases {
+		for _, d := range p.deps {
+			j, ok := idx[d]
+			if !ok {
+				return nil, errors.Errorf("Phase {%s} depends on unknown phase {%s}", p.name, d)
 			}
-			args, err := param.RenderArgs(ap.Args, tp)
-			if err != nil {
-				return nil, err
+			if j == i {
+				return nil, errors.Errorf("Phase {%s} depends on itself", p.name)
 			}
-			if err = checkRequiredArgs(p.f.RequiredArgs(), args); err != nil {
-				return nil, errors.Wrapf(err, "Required args missing for function %s", p.f.Name())
+			deps[i] = append(deps[i], j)
+		}
+	}
+	// Kahn's algorithm: if some phases are never ready, they are in a cycle.
+	remaining := make([]int, len(phases))
+	dependents := make([][]int, len(phases))
+	var ready []int
+	for i, ds := range deps {
+		remaining[i] = len(ds)
+		for _, d := range ds {
+			dependents[d] = append(dependents[d], i)
+		}
+		if len(ds) == 0 {
+			ready = append(ready, i)
+		}
+	}
+	visited := 0
+	for len(ready) != 0 {
+		i := ready[0]
+		ready = ready[1:]
+		visited++
+		for _, d := range dependents[i] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				ready = append(ready, d)
 			}
-			p.args = args
 		}
 	}
-	// Execute the function
-	return p.f.Exec(ctx, tp, p.args)
+	if visited != len(phases) {
+		return nil, errors.New("Phase dependencies contain a cycle")
+	}
+	return deps, nil
 }
 
-// GetPhases renders the returns a list of Phases with pre-rendered arguments.
-func GetPhases(bp crv1alpha1.Blueprint, action, version string, tp param.TemplateParams) ([]*Phase, error) {
+// GetDeferPhase returns the deferPhase of an action with its function
+// resolved. It returns nil if the action does not have a deferPhase.
+func GetDeferPhase(bp crv1alpha1.Blueprint, action, version string, tp param.TemplateParams) (*Phase, error) {
 	a, ok := bp.Actions[action]
 	if !ok {
 		return nil, errors.Errorf("Action {%s} not found in action map", action)
 	}
+	if a.DeferPhase == nil {
+		return nil, nil
+	}
+	funcMu.RLock()
+	defer funcMu.RUnlock()
+	funcVersion, err := resolveFuncVersion([]crv1alpha1.BlueprintPhase{*a.DeferPhase}, version)
+	if err != nil {
+		return nil, err
+	}
+	return newPhase(*a.DeferPhase, funcVersion, tp)
+}
+
+// resolveFuncVersion checks that the functions of all phases are registered
+// and returns the version they should be executed with. funcMu must be held.
+func resolveFuncVersion(phases []crv1alpha1.BlueprintPhase, version string) (*semver.Version, error) {
 	defaultVersion, funcVersion, err := getFunctionVersion(version)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to get function version")
 	}
-	funcMu.RLock()
-	defer funcMu.RUnlock()
 	// We first check that all requested phases are registered.
-	for _, p := range a.Phases {
+	for _, p := range phases {
 		if _, ok := funcs[p.Func]; !ok {
 			return nil, errors.Errorf("Requested function {%s} has not been registered", p.Func)
 		}
@@ -100,19 +249,22 @@ func GetPhases(bp crv1alpha1.Blueprint, action, version string, tp param.Templat
 			*funcVersion = *defaultVersion
 		}
 	}
-	phases := make([]*Phase, 0, len(a.Phases))
-	for _, p := range a.Phases {
-		objs, err := param.RenderObjectRefs(p.ObjectRefs, tp)
-		if err != nil {
-			return nil, err
-		}
-		phases = append(phases, &Phase{
-			name:    p.Name,
-			objects: objs,
-			f:       funcs[p.Func][*funcVersion],
-		})
+	return funcVersion, nil
+}
+
+// newPhase creates a Phase for a BlueprintPhase. funcMu must be held.
+func newPhase(p crv1alpha1.BlueprintPhase, funcVersion *semver.Version, tp param.TemplateParams) (*Phase, error) {
+	objs, err := param.RenderObjectRefs(p.ObjectRefs, tp)
+	if err != nil {
+		return nil, err
 	}
-	return phases, nil
+	return &Phase{
+		name:    p.Name,
+		objects: objs,
+		retry:   p.Retry,
+		deps:    p.DependsOn,
+		f:       funcs[p.Func][*funcVersion],
+	}, nil
 }
 
 func checkRequiredArgs(reqArgs []string, args map[string]interface{}) error {
@@ -83,3 +83,89 @@ func (s *PhaseSuite) TestExec(c *C) {
 		c.Assert(output, Equals, tc.expected)
 	}
 }
+
+func (s *PhaseSuite) TestPhaseDependencies(c *C) {
+	for _, tc := range []struct {
+		phases   []*Phase
+		expected [][]int
+		checker  Checker
+	}{
+		{
+			phases:   []*Phase{{name: "a"}, {name: "b"}, {name: "c"}},
+			expected: [][]int{nil, {0}, {1}},
+			checker:  IsNil,
+		},
+		{
+			phases:   []*Phase{{name: "a"}, {name: "b", deps: []string{"a"}}, {name: "c", deps: []string{"a"}}, {name: "d", deps: []string{"b", "c"}}},
+			expected: [][]int{nil, {0}, {0}, {1, 2}},
+			checker:  IsNil,
+		},
+		{
+			phases:   []*Phase{{name: "a"}, {name: "b", deps: []string{"x"}}},
+			expected: nil,
+			checker:  NotNil,
+		},
+		{
+			phases:   []*Phase{{name: "a", deps: []string{"a"}}},
+			expected: nil,
+			checker:  NotNil,
+		},
+		{
+			phases:   []*Phase{{name: "a", deps: []string{"c"}}, {name: "b", deps: []string{"a"}}, {name: "c", deps: []string{"b"}}},
+			expected: nil,
+			checker:  NotNil,
+		},
+	} {
+		deps, err := PhaseDependencies(tc.phases)
+		c.Assert(err, tc.checker)
+		c.Assert(deps, DeepEquals, tc.expected)
+	}
+}
+
+type outputFunc struct {
+	testFunc
+}
+
+func (*outputFunc) Outputs() []string {
+	return []string{"backupTag"}
+}
+
+func (s *PhaseSuite) TestRenderArgs(c *C) {
+	bp := crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "backup",
+						Args: map[string]interface{}{"testKey": "{{ .Options.test }}"},
+					},
+					{
+						Name: "check",
+						Args: map[string]interface{}{"testKey": "{{ .Phases.backup.Output.backupTag }}"},
+					},
+				},
+			},
+		},
+	}
+	backup := &Phase{name: "backup", f: &outputFunc{}}
+	check := &Phase{name: "check", f: &testFunc{}}
+	c.Assert(check.PlaceholderOutput(), IsNil)
+
+	tp := param.TemplateParams{Options: map[string]string{"test": "hello"}}
+	args, err := backup.RenderArgs(bp, "backup", tp)
+	c.Assert(err, IsNil)
+	c.Assert(args, DeepEquals, map[string]interface{}{"testKey": "hello"})
+
+	// The output of the backup phase is not known until it is set.
+	tp.Phases = map[string]*param.Phase{"backup": {}}
+	_, err = check.RenderArgs(bp, "backup", tp)
+	c.Assert(err, NotNil)
+
+	tp.Phases["backup"].Output = backup.PlaceholderOutput()
+	args, err = check.RenderArgs(bp, "backup", tp)
+	c.Assert(err, IsNil)
+	c.Assert(args, DeepEquals, map[string]interface{}{"testKey": "<backup.backupTag>"})
+
+	_, err = check.RenderArgs(bp, "restore", tp)
+	c.Assert(err, ErrorMatches, ".*not found.*")
+}
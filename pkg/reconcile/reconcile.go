@@ -57,3 +57,27 @@ func ActionSet(ctx context.Context, cli crclientv1alpha1.CrV1alpha1Interface, ns
 		return true, nil
 	})
 }
+
+// BackupSchedule attempts to reconcile the modifications made by `f` with the
+// BackupSchedule stored in the API server.
+func BackupSchedule(ctx context.Context, cli crclientv1alpha1.CrV1alpha1Interface, ns, name string, f func(*crv1alpha1.BackupSchedule) error) error {
+	return poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		bs, err := cli.BackupSchedules(ns).Get(name, v1.GetOptions{})
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		if err = f(bs); err != nil {
+			return false, err
+		}
+		_, err = cli.BackupSchedules(bs.GetNamespace()).Update(bs)
+		// If we get a version conflict, we backoff and try again.
+		if apierrors.IsConflict(err) {
+			return false, nil
+		}
+		if err != nil {
+			msg := fmt.Sprintf("Failed to update BackupSchedule %s", name)
+			return false, errors.Wrap(err, msg)
+		}
+		return true, nil
+	})
+}
@@ -38,6 +38,7 @@ func CreateCustomResources(ctx context.Context, config *rest.Config) error {
 		crv1alpha1.ActionSetResource,
 		crv1alpha1.BlueprintResource,
 		crv1alpha1.ProfileResource,
+		crv1alpha1.BackupScheduleResource,
 	}
 	return customresource.CreateCustomResources(*crCTX, resources)
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -46,8 +47,8 @@ func shCommand(command string) []string {
 }
 
 // BackupCommandByID returns restic backup command
-func BackupCommandByID(profile *param.Profile, repository, pathToBackup, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func BackupCommandByID(profile *param.Profile, repository, pathToBackup, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -56,53 +57,220 @@ func BackupCommandByID(profile *param.Profile, repository, pathToBackup, encrypt
 	return shCommand(command), nil
 }
 
+// BackupOptions leaves files out of a restic backup and adds metadata to the
+// snapshot. The zero value backs up everything under the include path.
+type BackupOptions struct {
+	// Exclude are restic patterns for files and directories to skip
+	Exclude []string
+	// ExcludeIfPresent skips directories that contain a file with one of
+	// these names, e.g. CACHEDIR.TAG
+	ExcludeIfPresent []string
+	// Tags are added to the snapshot alongside the generated backup tag
+	Tags []string
+	// Hostname replaces the name of the pod restic runs in as the
+	// snapshot's host
+	Hostname string
+}
+
+// Validate checks that the tags and hostname can be passed to restic
+func (o BackupOptions) Validate() error {
+	for _, t := range o.Tags {
+		if t == "" || strings.Contains(t, ",") {
+			return errors.Errorf("Invalid tag '%s'. Tags must be non-empty and must not contain ','", t)
+		}
+	}
+	return nil
+}
+
+func (o BackupOptions) args() []string {
+	var args []string
+	for _, t := range o.Tags {
+		args = append(args, "--tag", shellQuote(t))
+	}
+	if o.Hostname != "" {
+		args = append(args, "--host", shellQuote(o.Hostname))
+	}
+	for _, p := range o.Exclude {
+		args = append(args, "--exclude", shellQuote(p))
+	}
+	for _, f := range o.ExcludeIfPresent {
+		args = append(args, "--exclude-if-present", shellQuote(f))
+	}
+	return args
+}
+
+// SnapshotFilter selects the snapshots listed by SnapshotsCommandByFilter. A
+// snapshot matches if it has all of Tags and, when set, was taken with
+// Hostname.
+type SnapshotFilter struct {
+	Tags     []string
+	Hostname string
+}
+
+func (f SnapshotFilter) args() []string {
+	var args []string
+	if len(f.Tags) > 0 {
+		args = append(args, "--tag", shellQuote(strings.Join(f.Tags, ",")))
+	}
+	if f.Hostname != "" {
+		args = append(args, "--host", shellQuote(f.Hostname))
+	}
+	return args
+}
+
 // BackupCommandByTag returns restic backup command with tag
-func BackupCommandByTag(profile *param.Profile, repository, backupTag, includePath, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func BackupCommandByTag(profile *param.Profile, repository, backupTag, includePath, passwordFile string, opts BackupOptions) ([]string, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
-	cmd = append(cmd, "backup", "--tag", backupTag, includePath)
+	cmd = append(cmd, "backup", "--tag", backupTag)
+	cmd = append(cmd, opts.args()...)
+	cmd = append(cmd, includePath)
 	command := strings.Join(cmd, " ")
 	return shCommand(command), nil
 }
 
+// OverwritePolicy controls how a restore treats files that already exist in
+// the restore target
+type OverwritePolicy string
+
+const (
+	// OverwriteAlways replaces existing files unconditionally
+	OverwriteAlways OverwritePolicy = "always"
+	// OverwriteIfNewer replaces existing files only if the snapshot copy is newer
+	OverwriteIfNewer OverwritePolicy = "if-newer"
+	// OverwriteNever leaves existing files untouched
+	OverwriteNever OverwritePolicy = "never"
+)
+
+// restoreStagingPattern is the mktemp template of the directory, below the
+// restore target, that snapshots are staged in when existing files may be kept
+const restoreStagingPattern = ".kanister-restore-XXXXXX"
+
+// RestoreOptions narrows a restore to part of a snapshot. The zero value
+// restores the whole snapshot into the restore path.
+type RestoreOptions struct {
+	// Include and Exclude are restic path patterns matched against the
+	// paths stored in the snapshot. restic doesn't allow both to be set.
+	Include []string
+	Exclude []string
+	// TargetSubdir is a relative directory below the restore path that
+	// the snapshot is restored into
+	TargetSubdir string
+	// Overwrite is the policy for files that already exist. It defaults to
+	// OverwriteAlways.
+	Overwrite OverwritePolicy
+}
+
+// Validate checks that the overwrite policy is known, that include and
+// exclude patterns aren't combined and that the target subdirectory stays
+// inside the restore path
+func (o RestoreOptions) Validate() error {
+	switch o.Overwrite {
+	case "", OverwriteAlways, OverwriteIfNewer, OverwriteNever:
+	default:
+		return errors.Errorf("Unsupported overwrite policy '%s'", o.Overwrite)
+	}
+	if len(o.Include) != 0 && len(o.Exclude) != 0 {
+		return errors.New("Include and exclude patterns cannot be used together")
+	}
+	if o.TargetSubdir == "" {
+		return nil
+	}
+	if path.IsAbs(o.TargetSubdir) {
+		return errors.Errorf("Target subdirectory '%s' must be relative", o.TargetSubdir)
+	}
+	if sub := path.Clean(o.TargetSubdir); sub == ".." || strings.HasPrefix(sub, "../") {
+		return errors.Errorf("Target subdirectory '%s' must not leave the restore path", o.TargetSubdir)
+	}
+	return nil
+}
+
+func (o RestoreOptions) args(target string) []string {
+	args := []string{"--target", target}
+	for _, p := range o.Include {
+		args = append(args, "--include", shellQuote(p))
+	}
+	for _, p := range o.Exclude {
+		args = append(args, "--exclude", shellQuote(p))
+	}
+	return args
+}
+
 // RestoreCommandByID returns restic restore command with snapshotID as the identifier
-func RestoreCommandByID(profile *param.Profile, repository, id, restorePath, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func RestoreCommandByID(profile *param.Profile, repository, id, restorePath, passwordFile string, opts RestoreOptions) ([]string, error) {
+	return restoreCommand(profile, repository, restorePath, passwordFile, opts, id)
+}
+
+// RestoreCommandByTag returns restic restore command with tag as the identifier
+func RestoreCommandByTag(profile *param.Profile, repository, tag, restorePath, passwordFile string, opts RestoreOptions) ([]string, error) {
+	return restoreCommand(profile, repository, restorePath, passwordFile, opts, "--tag", tag, "latest")
+}
+
+// restoreCommand returns the restic restore command for the snapshot selected
+// by `snapshot`. restic always replaces existing files, so to keep them the
+// snapshot is restored into a staging directory below the target and copied
+// over with cp.
+func restoreCommand(profile *param.Profile, repository, restorePath, passwordFile string, opts RestoreOptions, snapshot ...string) ([]string, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
-	cmd = append(cmd, "restore", id, "--target", restorePath)
-	command := strings.Join(cmd, " ")
+	target := shellQuote(path.Join(restorePath, opts.TargetSubdir))
+	cmd = append(cmd, "restore")
+	cmd = append(cmd, snapshot...)
+	var cpFlag string
+	switch opts.Overwrite {
+	case OverwriteIfNewer:
+		cpFlag = "-u"
+	case OverwriteNever:
+		cpFlag = "-n"
+	default:
+		cmd = append(cmd, opts.args(target)...)
+		command := strings.Join(cmd, " ")
+		return shCommand(command), nil
+	}
+	cmd = append(cmd, opts.args(`"$stage"`)...)
+	staging := shellQuote(path.Join(restorePath, opts.TargetSubdir, restoreStagingPattern))
+	command := fmt.Sprintf("mkdir -p %s\nstage=$(mktemp -d %s)\ntrap 'rm -rf \"$stage\"' EXIT\n%s\ncp -a %s \"$stage\"/. %s",
+		target, staging, strings.Join(cmd, " "), cpFlag, target)
 	return shCommand(command), nil
 }
 
-// RestoreCommandByTag returns restic restore command with tag as the identifier
-func RestoreCommandByTag(profile *param.Profile, repository, tag, restorePath, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+// SnapshotsCommand returns restic snapshots command
+func SnapshotsCommand(profile *param.Profile, repository, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
-	cmd = append(cmd, "restore", "--tag", tag, "latest", "--target", restorePath)
+	cmd = append(cmd, "snapshots", "--json")
 	command := strings.Join(cmd, " ")
 	return shCommand(command), nil
 }
 
-// SnapshotsCommand returns restic snapshots command
-func SnapshotsCommand(profile *param.Profile, repository, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+// SnapshotsCommandByFilter returns restic snapshots command for the
+// snapshots that match the filter
+func SnapshotsCommandByFilter(profile *param.Profile, repository, passwordFile string, filter SnapshotFilter) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
 	cmd = append(cmd, "snapshots", "--json")
+	cmd = append(cmd, filter.args()...)
 	command := strings.Join(cmd, " ")
 	return shCommand(command), nil
 }
 
 // LatestSnapshotsCommand returns restic snapshots command for last snapshots
-func LatestSnapshotsCommand(profile *param.Profile, repository, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func LatestSnapshotsCommand(profile *param.Profile, repository, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -112,8 +280,8 @@ func LatestSnapshotsCommand(profile *param.Profile, repository, encryptionKey st
 }
 
 // SnapshotsCommandByTag returns restic snapshots command
-func SnapshotsCommandByTag(profile *param.Profile, repository, tag, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func SnapshotsCommandByTag(profile *param.Profile, repository, tag, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -123,8 +291,8 @@ func SnapshotsCommandByTag(profile *param.Profile, repository, tag, encryptionKe
 }
 
 // InitCommand returns restic init command
-func InitCommand(profile *param.Profile, repository, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func InitCommand(profile *param.Profile, repository, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -134,8 +302,8 @@ func InitCommand(profile *param.Profile, repository, encryptionKey string) ([]st
 }
 
 // ForgetCommandByTag returns restic forget command
-func ForgetCommandByTag(profile *param.Profile, repository, tag, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func ForgetCommandByTag(profile *param.Profile, repository, tag, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -145,8 +313,8 @@ func ForgetCommandByTag(profile *param.Profile, repository, tag, encryptionKey s
 }
 
 // ForgetCommandByID returns restic forget command
-func ForgetCommandByID(profile *param.Profile, repository, id, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func ForgetCommandByID(profile *param.Profile, repository, id, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -156,8 +324,8 @@ func ForgetCommandByID(profile *param.Profile, repository, id, encryptionKey str
 }
 
 // PruneCommand returns restic prune command
-func PruneCommand(profile *param.Profile, repository, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func PruneCommand(profile *param.Profile, repository, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +335,8 @@ func PruneCommand(profile *param.Profile, repository, encryptionKey string) ([]s
 }
 
 // StatsCommandByID returns restic stats command
-func StatsCommandByID(profile *param.Profile, repository, id, mode, encryptionKey string) ([]string, error) {
-	cmd, err := resticArgs(profile, repository, encryptionKey)
+func StatsCommandByID(profile *param.Profile, repository, id, mode, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
 	if err != nil {
 		return nil, err
 	}
@@ -177,6 +345,121 @@ func StatsCommandByID(profile *param.Profile, repository, id, mode, encryptionKe
 	return shCommand(command), nil
 }
 
+// StatsCommandByIDs returns restic stats command for the given snapshots,
+// or for all snapshots if ids is empty
+func StatsCommandByIDs(profile *param.Profile, repository string, ids []string, mode, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd = append(cmd, "stats", "--mode", mode)
+	for _, id := range ids {
+		cmd = append(cmd, shellQuote(id))
+	}
+	command := strings.Join(cmd, " ")
+	return shCommand(command), nil
+}
+
+// CheckCommand returns restic check command. If readDataSubset is set, e.g.
+// "1/5", that part of the pack files is also downloaded and verified.
+func CheckCommand(profile *param.Profile, repository, readDataSubset, passwordFile string) ([]string, error) {
+	if err := validateReadDataSubset(readDataSubset); err != nil {
+		return nil, err
+	}
+	cmd, err := resticArgs(profile, repository, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd = append(cmd, "check")
+	if readDataSubset != "" {
+		cmd = append(cmd, "--read-data-subset", shellQuote(readDataSubset))
+	}
+	command := strings.Join(cmd, " ")
+	return shCommand(command), nil
+}
+
+// validateReadDataSubset accepts the "n/t" form, which selects the n-th of t
+// groups of pack files. The "x%" form needs restic 0.12 or later and is
+// rejected since the tools image ships an older restic.
+func validateReadDataSubset(subset string) error {
+	if subset == "" {
+		return nil
+	}
+	if m := regexp.MustCompile(`^(\d+)/(\d+)$`).FindStringSubmatch(subset); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		t, _ := strconv.Atoi(m[2])
+		if n >= 1 && n <= t {
+			return nil
+		}
+	}
+	return errors.Errorf("Invalid data subset '%s'. Use 'n/t' with 1 <= n <= t", subset)
+}
+
+// UnlockCommand returns restic unlock command. Only stale locks are removed
+// unless removeAll is set.
+func UnlockCommand(profile *param.Profile, repository, passwordFile string, removeAll bool) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd = append(cmd, "unlock")
+	if removeAll {
+		cmd = append(cmd, "--remove-all")
+	}
+	command := strings.Join(cmd, " ")
+	return shCommand(command), nil
+}
+
+// ListLocksCommand returns restic command that lists the IDs of the locks in
+// the repository without creating a lock itself
+func ListLocksCommand(profile *param.Profile, repository, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd = append(cmd, "--no-lock", "list", "locks")
+	command := strings.Join(cmd, " ")
+	return shCommand(command), nil
+}
+
+// KeyListCommand returns restic key list command
+func KeyListCommand(profile *param.Profile, repository, passwordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd = append(cmd, "key", "list")
+	command := strings.Join(cmd, " ")
+	return shCommand(command), nil
+}
+
+// KeyAddCommand returns restic key add command which adds the password in
+// newPasswordFile as a key to the repository
+func KeyAddCommand(profile *param.Profile, repository, passwordFile, newPasswordFile string) ([]string, error) {
+	cmd, err := resticArgs(profile, repository, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd = append(cmd, "key", "add", "--new-password-file", shellQuote(newPasswordFile))
+	command := strings.Join(cmd, " ")
+	return shCommand(command), nil
+}
+
+// KeyRemoveCommand returns restic key remove command. restic refuses to
+// remove the key that passwordFile opens.
+func KeyRemoveCommand(profile *param.Profile, repository, passwordFile, id string) ([]string, error) {
+	if !regexp.MustCompile(`^[0-9a-f]+$`).MatchString(id) {
+		return nil, errors.Errorf("Invalid key ID '%s'", id)
+	}
+	cmd, err := resticArgs(profile, repository, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	cmd = append(cmd, "key", "remove", id)
+	command := strings.Join(cmd, " ")
+	return shCommand(command), nil
+}
+
 const (
 	ResticPassword   = "RESTIC_PASSWORD"
 	ResticRepository = "RESTIC_REPOSITORY"
@@ -184,7 +467,20 @@ const (
 	awsS3Endpoint    = "s3.amazonaws.com"
 )
 
-func resticArgs(profile *param.Profile, repository, encryptionKey string) ([]string, error) {
+// resticArgs returns the arguments that run restic against the repository.
+// restic reads the password from passwordFile, a file in the container the
+// command runs in, so that the password is never part of the command.
+func resticArgs(profile *param.Profile, repository, passwordFile string) ([]string, error) {
+	cmd, err := resticRepositoryArgs(profile, repository)
+	if err != nil {
+		return nil, err
+	}
+	return append(cmd, ResticCommand, "--password-file", shellQuote(passwordFile)), nil
+}
+
+// resticRepositoryArgs returns the arguments that point restic at the
+// repository, without the password that opens it
+func resticRepositoryArgs(profile *param.Profile, repository string) ([]string, error) {
 	var cmd []string
 	var err error
 	switch profile.Location.Type {
@@ -194,13 +490,15 @@ func resticArgs(profile *param.Profile, repository, encryptionKey string) ([]str
 		cmd = resticGCSArgs(profile, repository)
 	case crv1alpha1.LocationTypeAzure:
 		cmd = resticAzureArgs(profile, repository)
+	case crv1alpha1.LocationTypeFilesystem:
+		cmd = resticFilesystemArgs(profile, repository)
 	default:
 		return nil, errors.New("Unsupported type '%s' for the location")
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to get arguments")
 	}
-	return append(cmd, fmt.Sprintf("export %s=%s\n", ResticPassword, encryptionKey), ResticCommand), nil
+	return cmd, nil
 }
 
 func resticS3Args(profile *param.Profile, repository string) ([]string, error) {
@@ -265,14 +563,22 @@ func resticAzureArgs(profile *param.Profile, repository string) []string {
 	}
 }
 
+// resticFilesystemArgs points restic at a local repository under the
+// filesystem location's directory, using the same layout as the objectstore.
+func resticFilesystemArgs(profile *param.Profile, repository string) []string {
+	return []string{
+		fmt.Sprintf("export %s=%s\n", ResticRepository, path.Join(profile.Location.Endpoint, repository)),
+	}
+}
+
 // GetOrCreateRepository will check if the repository already exists and initialize one if not
-func GetOrCreateRepository(cli kubernetes.Interface, namespace, pod, container, artifactPrefix, encryptionKey string, profile *param.Profile) error {
-	_, _, err := getLatestSnapshots(profile, artifactPrefix, encryptionKey, cli, namespace, pod, container)
+func GetOrCreateRepository(cli kubernetes.Interface, namespace, pod, container, artifactPrefix, passwordFile string, profile *param.Profile) error {
+	_, _, err := getLatestSnapshots(profile, artifactPrefix, passwordFile, cli, namespace, pod, container)
 	if err == nil {
 		return nil
 	}
 	// Create a repository
-	cmd, err := InitCommand(profile, artifactPrefix, encryptionKey)
+	cmd, err := InitCommand(profile, artifactPrefix, passwordFile)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create init command")
 	}
@@ -283,8 +589,8 @@ func GetOrCreateRepository(cli kubernetes.Interface, namespace, pod, container,
 }
 
 // CheckIfRepoIsReachable checks if repo can be reached by trying to list snapshots
-func CheckIfRepoIsReachable(profile *param.Profile, artifactPrefix string, encryptionKey string, cli kubernetes.Interface, namespace string, pod string, container string) error {
-	_, stderr, err := getLatestSnapshots(profile, artifactPrefix, encryptionKey, cli, namespace, pod, container)
+func CheckIfRepoIsReachable(profile *param.Profile, artifactPrefix string, passwordFile string, cli kubernetes.Interface, namespace string, pod string, container string) error {
+	_, stderr, err := getLatestSnapshots(profile, artifactPrefix, passwordFile, cli, namespace, pod, container)
 	if IsPasswordIncorrect(stderr) { // If password didn't work
 		return errors.New(PasswordIncorrect)
 	}
@@ -297,9 +603,9 @@ func CheckIfRepoIsReachable(profile *param.Profile, artifactPrefix string, encry
 	return nil
 }
 
-func getLatestSnapshots(profile *param.Profile, artifactPrefix string, encryptionKey string, cli kubernetes.Interface, namespace string, pod string, container string) (string, string, error) {
+func getLatestSnapshots(profile *param.Profile, artifactPrefix string, passwordFile string, cli kubernetes.Interface, namespace string, pod string, container string) (string, string, error) {
 	// Use the latest snapshots command to check if the repository exists
-	cmd, err := LatestSnapshotsCommand(profile, artifactPrefix, encryptionKey)
+	cmd, err := LatestSnapshotsCommand(profile, artifactPrefix, passwordFile)
 	if err != nil {
 		return "", "", errors.Wrap(err, "Failed to create snapshot command")
 	}
@@ -428,11 +734,65 @@ func IsPasswordIncorrect(output string) bool {
 	return strings.Contains(output, "wrong password")
 }
 
+// CheckErrorsFound checks if restic check found errors from Check Command log
+func CheckErrorsFound(output string) bool {
+	return strings.Contains(output, "repository contains errors")
+}
+
+// LockIDsFromListLocksLog gets the lock IDs from List Locks Command log
+func LockIDsFromListLocksLog(output string) []string {
+	var ids []string
+	pattern := regexp.MustCompile(`^[0-9a-f]{64}$`)
+	for _, l := range strings.Split(output, "\n") {
+		if l = strings.TrimSpace(l); pattern.MatchString(l) {
+			ids = append(ids, l)
+		}
+	}
+	return ids
+}
+
+// Key describes a key of a restic repository
+type Key struct {
+	ID      string `json:"id"`
+	Current bool   `json:"current"`
+	Created string `json:"created"`
+}
+
+// KeysFromKeyListLog gets the keys from Key List Command log
+func KeysFromKeyListLog(output string) []Key {
+	var keys []Key
+	// Rows look like "*eb78040b  root        host-1      2019-03-28 17:35:15",
+	// where "*" marks the key that opened the repository. User and host
+	// are padded columns that may be empty, so they are not parsed.
+	pattern := regexp.MustCompile(`^([* ])([0-9a-f]{8,})\s.*(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})$`)
+	for _, l := range strings.Split(output, "\n") {
+		m := pattern.FindStringSubmatch(strings.TrimRight(l, "\r"))
+		if m == nil {
+			continue
+		}
+		keys = append(keys, Key{
+			ID:      m[2],
+			Current: m[1] == "*",
+			Created: m[3],
+		})
+	}
+	return keys
+}
+
 // DoesRepoExists checks if repo exists from Snapshot Command log
 func DoesRepoExist(output string) bool {
 	return strings.Contains(output, "Is there a repository at the following location?")
 }
 
+// SnapshotCountFromSnapshotCommand gets the number of snapshots from Snapshot Command log
+func SnapshotCountFromSnapshotCommand(output string) (int, error) {
+	var result []json.RawMessage
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return 0, errors.WithMessage(err, "Failed to unmarshall output from snapshotCommand")
+	}
+	return len(result), nil
+}
+
 // SnapshotIDFromSnapshotLog gets the SnapshotID from Snapshot Command log
 func SnapshotIDsFromSnapshotCommand(output string) ([]string, error) {
 	var snapIds []string
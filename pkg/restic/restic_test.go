@@ -15,6 +15,7 @@
 package restic
 
 import (
+	"regexp"
 	"testing"
 
 	. "gopkg.in/check.v1"
@@ -66,10 +67,10 @@ func (s *ResticDataSuite) TestGetSnapshotID(c *C) {
 
 func (s *ResticDataSuite) TestResticArgs(c *C) {
 	for _, tc := range []struct {
-		profile  *param.Profile
-		repo     string
-		password string
-		expected []string
+		profile      *param.Profile
+		repo         string
+		passwordFile string
+		expected     []string
 	}{
 		{
 			profile: &param.Profile{
@@ -85,14 +86,15 @@ func (s *ResticDataSuite) TestResticArgs(c *C) {
 					},
 				},
 			},
-			repo:     "repo",
-			password: "my-secret",
+			repo:         "repo",
+			passwordFile: "/tmp/restic-password",
 			expected: []string{
 				"export AWS_ACCESS_KEY_ID=id\n",
 				"export AWS_SECRET_ACCESS_KEY=secret\n",
 				"export RESTIC_REPOSITORY=s3:endpoint/repo\n",
-				"export RESTIC_PASSWORD=my-secret\n",
 				"restic",
+				"--password-file",
+				"'/tmp/restic-password'",
 			},
 		},
 		{
@@ -109,14 +111,15 @@ func (s *ResticDataSuite) TestResticArgs(c *C) {
 					},
 				},
 			},
-			repo:     "repo",
-			password: "my-secret",
+			repo:         "repo",
+			passwordFile: "/tmp/restic-password",
 			expected: []string{
 				"export AWS_ACCESS_KEY_ID=id\n",
 				"export AWS_SECRET_ACCESS_KEY=secret\n",
 				"export RESTIC_REPOSITORY=s3:endpoint/repo\n",
-				"export RESTIC_PASSWORD=my-secret\n",
 				"restic",
+				"--password-file",
+				"'/tmp/restic-password'",
 			},
 		},
 		{
@@ -133,14 +136,15 @@ func (s *ResticDataSuite) TestResticArgs(c *C) {
 					},
 				},
 			},
-			repo:     "repo",
-			password: "my-secret",
+			repo:         "repo",
+			passwordFile: "/tmp/restic-password",
 			expected: []string{
 				"export AWS_ACCESS_KEY_ID=id\n",
 				"export AWS_SECRET_ACCESS_KEY=secret\n",
 				"export RESTIC_REPOSITORY=s3:endpoint/repo\n",
-				"export RESTIC_PASSWORD=my-secret\n",
 				"restic",
+				"--password-file",
+				"'/tmp/restic-password'",
 			},
 		},
 		{
@@ -160,23 +164,195 @@ func (s *ResticDataSuite) TestResticArgs(c *C) {
 					},
 				},
 			},
-			repo:     "repo",
-			password: "my-secret",
+			repo:         "repo",
+			passwordFile: "/tmp/restic-password",
 			expected: []string{
 				"export AWS_ACCESS_KEY_ID=id\n",
 				"export AWS_SECRET_ACCESS_KEY=secret\n",
 				"export RESTIC_REPOSITORY=s3:endpoint/repo\n",
-				"export RESTIC_PASSWORD=my-secret\n",
 				"restic",
+				"--password-file",
+				"'/tmp/restic-password'",
+			},
+		},
+		{
+			profile: &param.Profile{
+				Location: v1alpha1.Location{
+					Type:     v1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+			},
+			repo:         "bucket/repo",
+			passwordFile: "/tmp/restic-password",
+			expected: []string{
+				"export RESTIC_REPOSITORY=/mnt/backups/bucket/repo\n",
+				"restic",
+				"--password-file",
+				"'/tmp/restic-password'",
+			},
+		},
+		{
+			profile: &param.Profile{
+				Location: v1alpha1.Location{
+					Type:     v1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+			},
+			repo:         "bucket/repo",
+			passwordFile: "/tmp/it's; rm -rf /",
+			expected: []string{
+				"export RESTIC_REPOSITORY=/mnt/backups/bucket/repo\n",
+				"restic",
+				"--password-file",
+				"'/tmp/it'\\''s; rm -rf /'",
 			},
 		},
 	} {
-		args, err := resticArgs(tc.profile, tc.repo, tc.password)
+		args, err := resticArgs(tc.profile, tc.repo, tc.passwordFile)
 		c.Assert(err, IsNil)
 		c.Assert(args, DeepEquals, tc.expected)
 	}
 }
 
+func (s *ResticDataSuite) TestRestoreCommandOptions(c *C) {
+	profile := &param.Profile{
+		Location: v1alpha1.Location{
+			Type:     v1alpha1.LocationTypeFilesystem,
+			Endpoint: "/mnt/repos",
+		},
+	}
+	for _, tc := range []struct {
+		opts     RestoreOptions
+		expected string
+		checker  Checker
+	}{
+		{
+			opts:     RestoreOptions{},
+			expected: "restic --password-file '/tmp/pw' restore snap1 --target '/data'",
+			checker:  IsNil,
+		},
+		{
+			opts: RestoreOptions{
+				Include:      []string{"/data/db/*.ibd", "/data/it's"},
+				TargetSubdir: "recovered",
+			},
+			expected: `restic --password-file '/tmp/pw' restore snap1 --target '/data/recovered' --include '/data/db/*.ibd' --include '/data/it'\''s'`,
+			checker:  IsNil,
+		},
+		{
+			opts: RestoreOptions{
+				Exclude: []string{"*.tmp"},
+			},
+			expected: `restic --password-file '/tmp/pw' restore snap1 --target '/data' --exclude '*.tmp'`,
+			checker:  IsNil,
+		},
+		{
+			opts: RestoreOptions{
+				Overwrite: OverwriteAlways,
+			},
+			expected: "restic --password-file '/tmp/pw' restore snap1 --target '/data'",
+			checker:  IsNil,
+		},
+		{
+			opts: RestoreOptions{
+				Include:      []string{"/data/db/*.ibd"},
+				TargetSubdir: "recovered",
+				Overwrite:    OverwriteNever,
+			},
+			expected: `restic --password-file '/tmp/pw' restore snap1 --target "$stage" --include '/data/db/*.ibd'` + "\n" +
+				`cp -a -n "$stage"/. '/data/recovered'`,
+			checker: IsNil,
+		},
+		{
+			opts: RestoreOptions{
+				Overwrite: OverwriteIfNewer,
+			},
+			expected: `restic --password-file '/tmp/pw' restore snap1 --target "$stage"` + "\n" +
+				`cp -a -u "$stage"/. '/data'`,
+			checker: IsNil,
+		},
+		{
+			opts: RestoreOptions{
+				Include: []string{"/data/db"},
+				Exclude: []string{"*.tmp"},
+			},
+			checker: NotNil,
+		},
+		{
+			opts:    RestoreOptions{Overwrite: "sometimes"},
+			checker: NotNil,
+		},
+		{
+			opts:    RestoreOptions{TargetSubdir: "/abs"},
+			checker: NotNil,
+		},
+		{
+			opts:    RestoreOptions{TargetSubdir: "a/../../b"},
+			checker: NotNil,
+		},
+	} {
+		cmd, err := RestoreCommandByID(profile, "repo", "snap1", "/data", "/tmp/pw", tc.opts)
+		c.Assert(err, tc.checker)
+		if err != nil {
+			continue
+		}
+		c.Assert(cmd[len(cmd)-1], Matches, "(?s).*"+regexp.QuoteMeta(tc.expected)+"$")
+	}
+}
+
+func (s *ResticDataSuite) TestBackupCommandOptions(c *C) {
+	profile := &param.Profile{
+		Location: v1alpha1.Location{
+			Type:     v1alpha1.LocationTypeFilesystem,
+			Endpoint: "/mnt/repos",
+		},
+	}
+	for _, tc := range []struct {
+		opts     BackupOptions
+		expected string
+		checker  Checker
+	}{
+		{
+			opts:     BackupOptions{},
+			expected: "restic --password-file '/tmp/pw' backup --tag gen123 /data",
+			checker:  IsNil,
+		},
+		{
+			opts: BackupOptions{
+				Exclude:          []string{"/data/cache", "*.tmp"},
+				ExcludeIfPresent: []string{"CACHEDIR.TAG"},
+				Tags:             []string{"app=mysql", "action=backup"},
+				Hostname:         "prod/mysql",
+			},
+			expected: "restic --password-file '/tmp/pw' backup --tag gen123 --tag 'app=mysql' --tag 'action=backup' --host 'prod/mysql' --exclude '/data/cache' --exclude '*.tmp' --exclude-if-present 'CACHEDIR.TAG' /data",
+			checker:  IsNil,
+		},
+		{
+			opts:    BackupOptions{Tags: []string{"a,b"}},
+			checker: NotNil,
+		},
+	} {
+		cmd, err := BackupCommandByTag(profile, "repo", "gen123", "/data", "/tmp/pw", tc.opts)
+		c.Assert(err, tc.checker)
+		if err != nil {
+			continue
+		}
+		c.Assert(cmd[len(cmd)-1], Matches, "(?s).*"+regexp.QuoteMeta(tc.expected)+"$")
+	}
+
+	cmd, err := SnapshotsCommandByFilter(profile, "repo", "/tmp/pw", SnapshotFilter{Tags: []string{"app=mysql", "namespace=prod"}, Hostname: "mysql"})
+	c.Assert(err, IsNil)
+	c.Assert(cmd[len(cmd)-1], Matches, "(?s).*"+regexp.QuoteMeta("restic --password-file '/tmp/pw' snapshots --json --tag 'app=mysql,namespace=prod' --host 'mysql'")+"$")
+
+	cmd, err = StatsCommandByIDs(profile, "repo", []string{"0a1b2c3d", "4e5f6a7b"}, "raw-data", "/tmp/pw")
+	c.Assert(err, IsNil)
+	c.Assert(cmd[len(cmd)-1], Matches, "(?s).*"+regexp.QuoteMeta("restic --password-file '/tmp/pw' stats --mode raw-data '0a1b2c3d' '4e5f6a7b'")+"$")
+
+	cmd, err = StatsCommandByIDs(profile, "repo", nil, "raw-data", "/tmp/pw")
+	c.Assert(err, IsNil)
+	c.Assert(cmd[len(cmd)-1], Matches, "(?s).*"+regexp.QuoteMeta("restic --password-file '/tmp/pw' stats --mode raw-data")+"$")
+}
+
 func (s *ResticDataSuite) TestResticArgsWithAWSRole(c *C) {
 	for _, tc := range []struct {
 		profile *param.Profile
@@ -451,3 +627,127 @@ func (s *ResticDataSuite) TestResticSizeStringParser(c *C) {
 		c.Check(parsedSize, Equals, tc.expectedSizeB)
 	}
 }
+
+func (s *ResticDataSuite) TestCheckCommand(c *C) {
+	profile := &param.Profile{
+		Location: v1alpha1.Location{
+			Type:     v1alpha1.LocationTypeFilesystem,
+			Endpoint: "/mnt/repos",
+		},
+	}
+	for _, tc := range []struct {
+		subset   string
+		expected string
+		checker  Checker
+	}{
+		{subset: "", expected: "restic --password-file '/tmp/pw' check", checker: IsNil},
+		{subset: "1/5", expected: "restic --password-file '/tmp/pw' check --read-data-subset '1/5'", checker: IsNil},
+		{subset: "5/5", expected: "restic --password-file '/tmp/pw' check --read-data-subset '5/5'", checker: IsNil},
+		{subset: "0/5", checker: NotNil},
+		{subset: "6/5", checker: NotNil},
+		// Percentages need a newer restic than the one in the tools image.
+		{subset: "2.5%", checker: NotNil},
+		{subset: "1/5; rm -rf /", checker: NotNil},
+	} {
+		cmd, err := CheckCommand(profile, "repo", tc.subset, "/tmp/pw")
+		c.Assert(err, tc.checker, Commentf("subset %s", tc.subset))
+		if err != nil {
+			continue
+		}
+		c.Assert(cmd[len(cmd)-1], Matches, "(?s).*"+regexp.QuoteMeta(tc.expected)+"$")
+	}
+}
+
+func (s *ResticDataSuite) TestCheckErrorsFound(c *C) {
+	for _, tc := range []struct {
+		log      string
+		expected bool
+	}{
+		{log: "no errors were found", expected: false},
+		{log: "error for tree 4645312b:\n  tree 4645312b: file \"foo\": metadata size (123) and sum of blob sizes (0) do not match\nFatal: repository contains errors", expected: true},
+		{log: "Fatal: unable to open config file: Stat: stat /repo/config: no such file or directory", expected: false},
+	} {
+		c.Check(CheckErrorsFound(tc.log), Equals, tc.expected)
+	}
+}
+
+func (s *ResticDataSuite) TestLockIDsFromListLocksLog(c *C) {
+	id1 := "3f44e2cfe00fbd6e24e3f4f17df1b1c4ad3a36b9b46c9e1e0e0c5e0a6bd41b5a"
+	id2 := "8d5c0e2a8a1e7f5b0f4d3e2c1b0a99887766554433221100ffeeddccbbaa9988"
+	for _, tc := range []struct {
+		log      string
+		expected []string
+	}{
+		{log: "", expected: nil},
+		{log: id1 + "\n", expected: []string{id1}},
+		{log: "repository 1a2b3c4d opened successfully\n" + id1 + "\n" + id2 + "\n", expected: []string{id1, id2}},
+	} {
+		c.Check(LockIDsFromListLocksLog(tc.log), DeepEquals, tc.expected)
+	}
+}
+
+func (s *ResticDataSuite) TestSnapshotCountFromSnapshotCommand(c *C) {
+	for _, tc := range []struct {
+		log      string
+		expected int
+		checker  Checker
+	}{
+		{log: `[]`, expected: 0, checker: IsNil},
+		{log: `null`, expected: 0, checker: IsNil},
+		{log: `[{"short_id":"7c0bfeb9"},{"short_id":"7c0bfeb1"}]`, expected: 2, checker: IsNil},
+		{log: `Fatal: wrong password`, expected: 0, checker: NotNil},
+	} {
+		n, err := SnapshotCountFromSnapshotCommand(tc.log)
+		c.Assert(err, tc.checker)
+		c.Assert(n, Equals, tc.expected)
+	}
+}
+
+func (s *ResticDataSuite) TestKeysFromKeyListLog(c *C) {
+	log := ` ID        User        Host        Created
+----------------------------------------------------
+*eb78040b  root        backup-pod  2019-03-28 17:35:15
+ 4a1c2f3d              other-host  2020-01-02 03:04:05
+ 9f8e7d6c  kanister                2020-02-03 04:05:06
+----------------------------------------------------
+`
+	c.Assert(KeysFromKeyListLog(log), DeepEquals, []Key{
+		{ID: "eb78040b", Current: true, Created: "2019-03-28 17:35:15"},
+		{ID: "4a1c2f3d", Created: "2020-01-02 03:04:05"},
+		{ID: "9f8e7d6c", Created: "2020-02-03 04:05:06"},
+	})
+	c.Assert(KeysFromKeyListLog("Fatal: wrong password or no key found"), HasLen, 0)
+}
+
+func (s *ResticDataSuite) TestKeyRemoveCommand(c *C) {
+	profile := &param.Profile{
+		Location: v1alpha1.Location{
+			Type:     v1alpha1.LocationTypeFilesystem,
+			Endpoint: "/mnt/repos",
+		},
+	}
+	cmd, err := KeyRemoveCommand(profile, "repo", "/tmp/pw", "eb78040b")
+	c.Assert(err, IsNil)
+	c.Assert(cmd[len(cmd)-1], Matches, "(?s).*restic --password-file '/tmp/pw' key remove eb78040b$")
+	_, err = KeyRemoveCommand(profile, "repo", "/tmp/pw", "eb78040b; rm -rf /")
+	c.Assert(err, NotNil)
+}
+
+func (s *ResticDataSuite) TestKeyListCommand(c *C) {
+	profile := &param.Profile{
+		Location: v1alpha1.Location{
+			Type:     v1alpha1.LocationTypeFilesystem,
+			Endpoint: "/mnt/repos",
+		},
+	}
+	cmd, err := KeyListCommand(profile, "repo", "/tmp/restic-password")
+	c.Assert(err, IsNil)
+	c.Assert(cmd[len(cmd)-1], Not(Matches), "(?s).*"+ResticPassword+".*")
+	c.Assert(cmd[len(cmd)-1], Matches, "(?s).*"+regexp.QuoteMeta("restic --password-file '/tmp/restic-password' key list")+"$")
+}
+
+func (s *ResticDataSuite) TestPasswordFromProfile(c *C) {
+	c.Assert(PasswordFromProfile(nil), Equals, GeneratePassword())
+	c.Assert(PasswordFromProfile(&param.Profile{}), Equals, GeneratePassword())
+	c.Assert(PasswordFromProfile(&param.Profile{RepositoryPassword: []byte("from-secret")}), Equals, "from-secret")
+}
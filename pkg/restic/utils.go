@@ -17,6 +17,9 @@ package restic
 import (
 	"crypto/sha256"
 	"fmt"
+	"strings"
+
+	"github.com/kanisterio/kanister/pkg/param"
 )
 
 const (
@@ -29,3 +32,18 @@ func GeneratePassword() string {
 	_, _ = h.Write([]byte(password))
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
+
+// PasswordFromProfile returns the repository password stored in the Secret
+// referenced by the Profile, or the generated password if it has none
+func PasswordFromProfile(profile *param.Profile) string {
+	if profile != nil && len(profile.RepositoryPassword) > 0 {
+		return string(profile.RepositoryPassword)
+	}
+	return GeneratePassword()
+}
+
+// shellQuote wraps s in single quotes so that it reaches restic verbatim
+// through the bash command line, e.g. without glob expansion
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// Backup is a backup that a retention policy is evaluated against.
+type Backup struct {
+	Name string
+	Time time.Time
+}
+
+// Decision records why a backup expired.
+type Decision struct {
+	Backup
+	Reason string
+}
+
+// Expired returns the backups that the policy does not keep, oldest first.
+func Expired(p crv1alpha1.RetentionPolicy, backups []Backup, now time.Time) []Decision {
+	sorted := make([]Backup, len(backups))
+	copy(sorted, backups)
+	// Newest first, so that the keep rules select the newest backups.
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.After(sorted[j].Time)
+	})
+	keep := make([]bool, len(sorted))
+	noRules := p.KeepLast == 0 && p.KeepDaily == 0 && p.KeepWeekly == 0 && p.KeepMonthly == 0
+	for i := 0; i < p.KeepLast && i < len(sorted); i++ {
+		keep[i] = true
+	}
+	keepPeriods(sorted, keep, p.KeepDaily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepPeriods(sorted, keep, p.KeepWeekly, func(t time.Time) string {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-%d", y, w)
+	})
+	keepPeriods(sorted, keep, p.KeepMonthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+	// Backups selected by a keep rule never expire, so that a schedule that
+	// stalls for longer than MaxAge doesn't lose all of its backups. If MaxAge
+	// is set, the other backups only expire once they are older than it.
+	var expired []Decision
+	for i := len(sorted) - 1; i >= 0; i-- {
+		b := sorted[i]
+		switch {
+		case keep[i]:
+		case p.MaxAge != nil:
+			if now.Sub(b.Time) > p.MaxAge.Duration {
+				expired = append(expired, Decision{Backup: b, Reason: fmt.Sprintf("not selected by any keep rule and older than max age %s", p.MaxAge.Duration)})
+			}
+		case !noRules:
+			expired = append(expired, Decision{Backup: b, Reason: "not selected by any keep rule"})
+		}
+	}
+	return expired
+}
+
+// keepPeriods keeps the newest backup of each of the newest n periods. The
+// backups must be sorted newest first.
+func keepPeriods(backups []Backup, keep []bool, n int, period func(time.Time) string) {
+	var last string
+	for i := 0; i < len(backups) && n > 0; i++ {
+		p := period(backups[i].Time.UTC())
+		if p == last {
+			continue
+		}
+		keep[i] = true
+		last = p
+		n--
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type RetentionSuite struct{}
+
+var _ = Suite(&RetentionSuite{})
+
+func (s *RetentionSuite) TestExpired(c *C) {
+	now := time.Date(2019, time.August, 15, 12, 0, 0, 0, time.UTC)
+	backups := []Backup{
+		// Two backups a day for the last few days, plus older monthly ones.
+		{Name: "aug15-a", Time: time.Date(2019, time.August, 15, 10, 0, 0, 0, time.UTC)},
+		{Name: "aug15-b", Time: time.Date(2019, time.August, 15, 2, 0, 0, 0, time.UTC)},
+		{Name: "aug14-a", Time: time.Date(2019, time.August, 14, 10, 0, 0, 0, time.UTC)},
+		{Name: "aug14-b", Time: time.Date(2019, time.August, 14, 2, 0, 0, 0, time.UTC)},
+		{Name: "aug13", Time: time.Date(2019, time.August, 13, 2, 0, 0, 0, time.UTC)},
+		{Name: "aug05", Time: time.Date(2019, time.August, 5, 2, 0, 0, 0, time.UTC)},
+		{Name: "jul20", Time: time.Date(2019, time.July, 20, 2, 0, 0, 0, time.UTC)},
+		{Name: "jun20", Time: time.Date(2019, time.June, 20, 2, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range []struct {
+		policy  crv1alpha1.RetentionPolicy
+		expired []string
+	}{
+		{
+			// No rules keeps everything.
+			policy:  crv1alpha1.RetentionPolicy{},
+			expired: nil,
+		},
+		{
+			policy:  crv1alpha1.RetentionPolicy{KeepLast: 3},
+			expired: []string{"jun20", "jul20", "aug05", "aug13", "aug14-b"},
+		},
+		{
+			policy:  crv1alpha1.RetentionPolicy{KeepDaily: 2},
+			expired: []string{"jun20", "jul20", "aug05", "aug13", "aug14-b", "aug15-b"},
+		},
+		{
+			// Aug 5 is in week 32, Aug 13 to 15 are in week 33.
+			policy:  crv1alpha1.RetentionPolicy{KeepWeekly: 2},
+			expired: []string{"jun20", "jul20", "aug13", "aug14-b", "aug14-a", "aug15-b"},
+		},
+		{
+			policy:  crv1alpha1.RetentionPolicy{KeepLast: 1, KeepMonthly: 3},
+			expired: []string{"aug05", "aug13", "aug14-b", "aug14-a", "aug15-b"},
+		},
+		{
+			policy:  crv1alpha1.RetentionPolicy{MaxAge: &metav1.Duration{Duration: 7 * 24 * time.Hour}},
+			expired: []string{"jun20", "jul20", "aug05"},
+		},
+		{
+			// MaxAge doesn't expire backups selected by a keep rule, nor
+			// backups that are newer than it.
+			policy:  crv1alpha1.RetentionPolicy{KeepMonthly: 3, MaxAge: &metav1.Duration{Duration: 30 * 24 * time.Hour}},
+			expired: nil,
+		},
+		{
+			policy:  crv1alpha1.RetentionPolicy{KeepDaily: 2, MaxAge: &metav1.Duration{Duration: 7 * 24 * time.Hour}},
+			expired: []string{"jun20", "jul20", "aug05"},
+		},
+		{
+			// A stalled schedule keeps its newest backups even though all of
+			// them are older than MaxAge.
+			policy:  crv1alpha1.RetentionPolicy{KeepLast: 2, MaxAge: &metav1.Duration{Duration: time.Hour}},
+			expired: []string{"jun20", "jul20", "aug05", "aug13", "aug14-b", "aug14-a"},
+		},
+	} {
+		var expired []string
+		for _, d := range Expired(tc.policy, backups, now) {
+			c.Check(d.Reason, Not(Equals), "")
+			expired = append(expired, d.Name)
+		}
+		c.Check(expired, DeepEquals, tc.expired, Commentf("%+v", tc.policy))
+	}
+}
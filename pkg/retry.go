@@ -0,0 +1,116 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/jpillora/backoff"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/exec"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+const (
+	defaultRetryMinBackoff = 1 * time.Second
+	defaultRetryMaxBackoff = 1 * time.Minute
+	defaultRetryFactor     = 2
+)
+
+// MaxAttempts returns the number of times this phase may be executed.
+func (p *Phase) MaxAttempts() int {
+	if p.retry == nil || p.retry.MaxAttempts < 1 {
+		return 1
+	}
+	return p.retry.MaxAttempts
+}
+
+// Backoff returns the backoff used to wait between two attempts of this phase.
+func (p *Phase) Backoff() backoff.Backoff {
+	b := backoff.Backoff{
+		Min:    defaultRetryMinBackoff,
+		Max:    defaultRetryMaxBackoff,
+		Factor: defaultRetryFactor,
+	}
+	if p.retry == nil || p.retry.Backoff == nil {
+		return b
+	}
+	rb := p.retry.Backoff
+	if rb.Min.Duration > 0 {
+		b.Min = rb.Min.Duration
+	}
+	if rb.Max.Duration > 0 {
+		b.Max = rb.Max.Duration
+	}
+	if rb.Factor > 0 {
+		b.Factor = rb.Factor
+	}
+	b.Jitter = rb.Jitter
+	return b
+}
+
+// IsRetryable returns true if err belongs to one of the error classes this
+// phase may be retried on.
+func (p *Phase) IsRetryable(err error) bool {
+	if err == nil || p.retry == nil {
+		return false
+	}
+	if len(p.retry.RetryOn) == 0 {
+		return true
+	}
+	for _, class := range p.retry.RetryOn {
+		if isErrorClass(err, class) {
+			return true
+		}
+	}
+	return false
+}
+
+func isErrorClass(err error, class crv1alpha1.RetryErrorClass) bool {
+	switch class {
+	case crv1alpha1.RetryOnAny:
+		return true
+	case crv1alpha1.RetryOnTransient:
+		return isTransientError(err)
+	case crv1alpha1.RetryOnExec:
+		return isExecError(err)
+	}
+	return false
+}
+
+func isTransientError(err error) bool {
+	cause := errors.Cause(err)
+	if cause == context.DeadlineExceeded {
+		return true
+	}
+	if ne, ok := cause.(net.Error); ok && (ne.Timeout() || ne.Temporary()) {
+		return true
+	}
+	return apierrors.IsServerTimeout(cause) ||
+		apierrors.IsTimeout(cause) ||
+		apierrors.IsTooManyRequests(cause) ||
+		apierrors.IsInternalError(cause) ||
+		apierrors.IsServiceUnavailable(cause) ||
+		apierrors.IsUnexpectedServerError(cause)
+}
+
+func isExecError(err error) bool {
+	_, ok := errors.Cause(err).(exec.ExitError)
+	return ok
+}
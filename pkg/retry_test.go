@@ -0,0 +1,137 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/exec"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type RetrySuite struct{}
+
+var _ = Suite(&RetrySuite{})
+
+func (s *RetrySuite) TestMaxAttempts(c *C) {
+	for _, tc := range []struct {
+		retry    *crv1alpha1.RetryPolicy
+		expected int
+	}{
+		{
+			retry:    nil,
+			expected: 1,
+		},
+		{
+			retry:    &crv1alpha1.RetryPolicy{},
+			expected: 1,
+		},
+		{
+			retry:    &crv1alpha1.RetryPolicy{MaxAttempts: 3},
+			expected: 3,
+		},
+	} {
+		p := Phase{retry: tc.retry}
+		c.Check(p.MaxAttempts(), Equals, tc.expected)
+	}
+}
+
+func (s *RetrySuite) TestBackoff(c *C) {
+	p := Phase{}
+	b := p.Backoff()
+	c.Check(b.Min, Equals, defaultRetryMinBackoff)
+	c.Check(b.Max, Equals, defaultRetryMaxBackoff)
+	c.Check(b.Factor, Equals, float64(defaultRetryFactor))
+
+	p = Phase{
+		retry: &crv1alpha1.RetryPolicy{
+			Backoff: &crv1alpha1.RetryBackoff{
+				Min:    metav1.Duration{Duration: 5 * time.Second},
+				Factor: 3,
+				Jitter: true,
+			},
+		},
+	}
+	b = p.Backoff()
+	c.Check(b.Min, Equals, 5*time.Second)
+	c.Check(b.Max, Equals, defaultRetryMaxBackoff)
+	c.Check(b.Factor, Equals, float64(3))
+	c.Check(b.Jitter, Equals, true)
+}
+
+func (s *RetrySuite) TestIsRetryable(c *C) {
+	execErr := errors.Wrap(exec.CodeExitError{Err: errors.New("command terminated"), Code: 1}, "Failed to exec command")
+	transientErr := errors.Wrap(apierrors.NewServerTimeout(schema.GroupResource{Resource: "pods"}, "get", 1), "Failed to get pod")
+	deadlineErr := errors.Wrap(context.DeadlineExceeded, "Failed to wait for pod")
+	otherErr := errors.New("Kanister function failed")
+	for _, tc := range []struct {
+		retryOn   []crv1alpha1.RetryErrorClass
+		err       error
+		retryable bool
+	}{
+		{
+			retryOn:   nil,
+			err:       otherErr,
+			retryable: true,
+		},
+		{
+			retryOn:   []crv1alpha1.RetryErrorClass{crv1alpha1.RetryOnAny},
+			err:       otherErr,
+			retryable: true,
+		},
+		{
+			retryOn:   []crv1alpha1.RetryErrorClass{crv1alpha1.RetryOnTransient},
+			err:       transientErr,
+			retryable: true,
+		},
+		{
+			retryOn:   []crv1alpha1.RetryErrorClass{crv1alpha1.RetryOnTransient},
+			err:       deadlineErr,
+			retryable: true,
+		},
+		{
+			retryOn:   []crv1alpha1.RetryErrorClass{crv1alpha1.RetryOnTransient},
+			err:       execErr,
+			retryable: false,
+		},
+		{
+			retryOn:   []crv1alpha1.RetryErrorClass{crv1alpha1.RetryOnExec},
+			err:       execErr,
+			retryable: true,
+		},
+		{
+			retryOn:   []crv1alpha1.RetryErrorClass{crv1alpha1.RetryOnTransient, crv1alpha1.RetryOnExec},
+			err:       otherErr,
+			retryable: false,
+		},
+		{
+			retryOn:   nil,
+			err:       nil,
+			retryable: false,
+		},
+	} {
+		p := Phase{retry: &crv1alpha1.RetryPolicy{MaxAttempts: 2, RetryOn: tc.retryOn}}
+		c.Check(p.IsRetryable(tc.err), Equals, tc.retryable, Commentf("%v: %v", tc.retryOn, tc.err))
+	}
+	p := Phase{}
+	c.Check(p.IsRetryable(otherErr), Equals, false)
+}
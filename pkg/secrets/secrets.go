@@ -8,6 +8,7 @@ import (
 // ValidateCredentials returns error if secret is failed at validation.
 // Currently supports following:
 // - AWS typed secret with required AWS secret fields.
+// - vSphere typed secret with required vSphere secret fields.
 func ValidateCredentials(secret *v1.Secret) error {
 	if secret == nil {
 		return errors.New("Nil secret")
@@ -15,6 +16,8 @@ func ValidateCredentials(secret *v1.Secret) error {
 	switch string(secret.Type) {
 	case AWSSecretType:
 		return ValidateAWSCredentials(secret)
+	case VSphereSecretType:
+		return ValidateVSphereCredentials(secret)
 	default:
 		return errors.Errorf("Unsupported type '%s' for secret '%s:%s'", string(secret.Type), secret.Namespace, secret.Name)
 	}
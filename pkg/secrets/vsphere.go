@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// VSphereSecretType represents the secret type for vSphere credentials.
+	VSphereSecretType string = "secrets.kanister.io/vsphere"
+
+	// VSphereEndpoint is the key for the vCenter endpoint.
+	VSphereEndpoint string = "vsphere_endpoint"
+	// VSphereUsername is the key for the vCenter username.
+	VSphereUsername string = "vsphere_username"
+	// VSpherePassword is the key for the vCenter password.
+	VSpherePassword string = "vsphere_password"
+	// VSphereDatastore represents the key for the managed object ID of the
+	// datastore new volumes are created in. It is optional.
+	VSphereDatastore string = "vsphere_datastore"
+)
+
+// ValidateVSphereCredentials returns error if the secret is not a vSphere
+// secret with the endpoint, username and password set.
+func ValidateVSphereCredentials(secret *v1.Secret) error {
+	if string(secret.Type) != VSphereSecretType {
+		return errors.New("Secret is not vSphere secret")
+	}
+	for _, k := range []string{VSphereEndpoint, VSphereUsername, VSpherePassword} {
+		if len(secret.Data[k]) == 0 {
+			return errors.Errorf("Secret is missing field %s", k)
+		}
+	}
+	count := 3
+	if _, ok := secret.Data[VSphereDatastore]; ok {
+		count++
+	}
+	if len(secret.Data) > count {
+		return errors.New("Secret has an unknown field")
+	}
+	return nil
+}
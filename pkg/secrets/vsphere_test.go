@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+type VSphereSecretSuite struct{}
+
+var _ = Suite(&VSphereSecretSuite{})
+
+func (s *VSphereSecretSuite) TestValidateVSphereCredentials(c *C) {
+	for _, tc := range []struct {
+		secret     *v1.Secret
+		errChecker Checker
+	}{
+		{
+			secret: &v1.Secret{
+				Type: v1.SecretType(VSphereSecretType),
+				Data: map[string][]byte{
+					VSphereEndpoint: []byte("vcenter.example.com"),
+					VSphereUsername: []byte("user"),
+					VSpherePassword: []byte("pass"),
+				},
+			},
+			errChecker: IsNil,
+		},
+		{
+			secret: &v1.Secret{
+				Type: v1.SecretType(VSphereSecretType),
+				Data: map[string][]byte{
+					VSphereEndpoint:  []byte("vcenter.example.com"),
+					VSphereUsername:  []byte("user"),
+					VSpherePassword:  []byte("pass"),
+					VSphereDatastore: []byte("datastore-1"),
+				},
+			},
+			errChecker: IsNil,
+		},
+		{
+			secret: &v1.Secret{
+				Type: v1.SecretType(VSphereSecretType),
+				Data: map[string][]byte{
+					VSphereEndpoint: []byte("vcenter.example.com"),
+					VSphereUsername: []byte("user"),
+				},
+			},
+			errChecker: NotNil,
+		},
+		{
+			secret: &v1.Secret{
+				Type: v1.SecretType(VSphereSecretType),
+				Data: map[string][]byte{
+					VSphereEndpoint: []byte("vcenter.example.com"),
+					VSphereUsername: []byte("user"),
+					VSpherePassword: []byte("pass"),
+					"unknown":       []byte("value"),
+				},
+			},
+			errChecker: NotNil,
+		},
+		{
+			secret: &v1.Secret{
+				Type: "Opaque",
+			},
+			errChecker: NotNil,
+		},
+	} {
+		err := ValidateVSphereCredentials(tc.secret)
+		c.Check(err, tc.errChecker)
+		c.Check(ValidateCredentials(tc.secret), tc.errChecker)
+	}
+}
@@ -55,6 +55,8 @@ func (*mockGetter) Get(storageType blockstorage.Type, config map[string]string)
 	case blockstorage.TypeEBS:
 		fallthrough
 	case blockstorage.TypeGPD:
+		fallthrough
+	case blockstorage.TypeFCD:
 		return Get(storageType), nil
 	default:
 		return nil, errors.New("Get failed")
@@ -0,0 +1,415 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig"
+	"github.com/mitchellh/mapstructure"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// Blueprint function validates the Blueprint and returns an error if it is invalid.
+// The functions of all phases must be registered and their required args
+// present, all templates must parse, the phase outputs referenced by templates
+// must be declared by the functions of the phases and args with literal values
+// must match the types declared by the functions. Since the version of the
+// functions is only chosen by an ActionSet, the Blueprint is valid if it is
+// valid with any of the versions its functions are registered with.
+func Blueprint(bp *crv1alpha1.Blueprint) error {
+	if bp == nil {
+		return nil
+	}
+	// The error returned is the first one found with a version that all
+	// functions resolve with, since it is more precise than a function not
+	// being registered with some version.
+	var err, resolvedErr error
+	for _, v := range append([]string{kanister.DefaultVersion}, blueprintFuncVersions(bp)...) {
+		vErr := BlueprintVersion(bp, v)
+		if vErr == nil {
+			return nil
+		}
+		if err == nil {
+			err = vErr
+		}
+		if resolvedErr == nil && funcsResolve(bp, v) {
+			resolvedErr = vErr
+		}
+	}
+	if resolvedErr != nil {
+		return resolvedErr
+	}
+	return err
+}
+
+// funcsResolve returns true if the functions of all phases of the Blueprint
+// are registered with the version or the default version.
+func funcsResolve(bp *crv1alpha1.Blueprint, version string) bool {
+	for _, a := range bp.Actions {
+		if a == nil {
+			continue
+		}
+		for _, p := range blueprintPhases(a) {
+			if _, err := kanister.GetFuncSchema(p.Func, version); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// blueprintPhases returns the phases of the action followed by its
+// deferPhase, if any.
+func blueprintPhases(a *crv1alpha1.BlueprintAction) []crv1alpha1.BlueprintPhase {
+	phases := a.Phases
+	if a.DeferPhase != nil {
+		phases = append(phases[:len(phases):len(phases)], *a.DeferPhase)
+	}
+	return phases
+}
+
+// BlueprintVersion validates the Blueprint with the functions of the phases
+// resolved with the version, as they would be for an ActionSet with that
+// preferred version.
+func BlueprintVersion(bp *crv1alpha1.Blueprint, version string) error {
+	if bp == nil {
+		return nil
+	}
+	names := make([]string, 0, len(bp.Actions))
+	for name := range bp.Actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if a := bp.Actions[name]; a != nil {
+			if err := blueprintAction(name, a, version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// blueprintFuncVersions returns the versions, other than the default, that the
+// functions of the Blueprint are registered with.
+func blueprintFuncVersions(bp *crv1alpha1.Blueprint) []string {
+	seen := map[string]bool{kanister.DefaultVersion: true}
+	var versions []string
+	for _, a := range bp.Actions {
+		if a == nil {
+			continue
+		}
+		for _, p := range blueprintPhases(a) {
+			for _, v := range kanister.FuncVersions(p.Func) {
+				if !seen[v] {
+					seen[v] = true
+					versions = append(versions, v)
+				}
+			}
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func blueprintAction(name string, a *crv1alpha1.BlueprintAction, version string) error {
+	phases := blueprintPhases(a)
+	paths := make([]string, len(phases))
+	schemas := make(map[string]*kanister.FuncSchema, len(phases))
+	for i, p := range phases {
+		paths[i] = fmt.Sprintf("actions.%s.phases[%d]", name, i)
+		if i == len(a.Phases) {
+			paths[i] = fmt.Sprintf("actions.%s.deferPhase", name)
+		}
+		if p.Name == "" {
+			return errorf("%s.name: Phase name is empty", paths[i])
+		}
+		if _, ok := schemas[p.Name]; ok {
+			return errorf("%s.name: Phase name %s is not unique", paths[i], p.Name)
+		}
+		fs, err := kanister.GetFuncSchema(p.Func, version)
+		if err != nil {
+			return errorf("%s.func: %v", paths[i], err)
+		}
+		schemas[p.Name] = fs
+	}
+	for i, p := range phases {
+		path := paths[i]
+		fs := schemas[p.Name]
+		for _, arg := range fs.RequiredArgs {
+			if _, ok := p.Args[arg]; !ok {
+				return errorf("%s.args.%s: Required arg is missing for function %s", path, arg, fs.Name)
+			}
+		}
+		for j, d := range p.DependsOn {
+			switch {
+			case d == p.Name:
+				return errorf("%s.dependsOn[%d]: Phase %s depends on itself", path, j, d)
+			case !phaseExists(a.Phases, d):
+				return errorf("%s.dependsOn[%d]: Phase %s does not exist", path, j, d)
+			}
+		}
+		for _, arg := range sortedKeys(p.Args) {
+			argPath := fmt.Sprintf("%s.args.%s", path, arg)
+			if err := argType(fs.ArgTypes[arg], p.Args[arg]); err != nil {
+				return errorf("%s: %v", argPath, err)
+			}
+			if err := outputRefs(argPath, p.Args[arg], schemas); err != nil {
+				return err
+			}
+		}
+		if err := objectRefs(path, p.ObjectRefs, schemas); err != nil {
+			return err
+		}
+	}
+	for _, art := range sortedArtifactKeys(a.OutputArtifacts) {
+		kv := a.OutputArtifacts[art].KeyValue
+		keys := make([]string, 0, len(kv))
+		for k := range kv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			artPath := fmt.Sprintf("actions.%s.outputArtifacts.%s.keyValue.%s", name, art, k)
+			if err := outputRefs(artPath, kv[k], schemas); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// objectRefs checks the templates in the fields of the object references of a
+// phase.
+func objectRefs(path string, refs map[string]crv1alpha1.ObjectReference, schemas map[string]*kanister.FuncSchema) error {
+	keys := make([]string, 0, len(refs))
+	for k := range refs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ref := refs[k]
+		for _, f := range []struct {
+			name  string
+			value string
+		}{
+			{"apiVersion", ref.APIVersion},
+			{"group", ref.Group},
+			{"resource", ref.Resource},
+			{"kind", ref.Kind},
+			{"name", ref.Name},
+			{"namespace", ref.Namespace},
+		} {
+			if err := outputRefs(fmt.Sprintf("%s.objects.%s.%s", path, k, f.name), f.value, schemas); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// argType checks that a literal arg value can be decoded into the declared
+// type, as the functions do when they are executed. Values that contain
+// templates are not checked since their type is only known once they are
+// rendered.
+func argType(t kanister.ArgType, v interface{}) error {
+	if t == "" || hasTemplate(v) {
+		return nil
+	}
+	var out interface{}
+	switch t {
+	case kanister.ArgTypeString:
+		out = new(string)
+	case kanister.ArgTypeInt:
+		out = new(int)
+	case kanister.ArgTypeBool:
+		out = new(bool)
+	case kanister.ArgTypeStringSlice:
+		out = new([]string)
+	case kanister.ArgTypeMap:
+		out = new(map[string]interface{})
+	default:
+		return nil
+	}
+	if err := mapstructure.WeakDecode(v, out); err != nil {
+		return fmt.Errorf("Value is not of type %s: %v", t, err)
+	}
+	return nil
+}
+
+func hasTemplate(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return strings.Contains(v, "{{")
+	case []interface{}:
+		for _, e := range v {
+			if hasTemplate(e) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, e := range v {
+			if hasTemplate(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// outputRefs checks that the phase outputs referenced by the templates in v
+// exist. schemas holds the schema of the function of each phase of the
+// action, or nil if it is not known.
+func outputRefs(path string, v interface{}, schemas map[string]*kanister.FuncSchema) error {
+	switch v := v.(type) {
+	case string:
+		t, err := template.New("config").Funcs(sprig.TxtFuncMap()).Parse(v)
+		if err != nil {
+			return errorf("%s: Failed to parse template: %v", path, err)
+		}
+		var refs []outputRef
+		walkTemplate(t.Root, true, &refs)
+		for _, ref := range refs {
+			fs, ok := schemas[ref.phase]
+			if !ok {
+				return errorf("%s: Phase %s referenced by template does not exist", path, ref.phase)
+			}
+			if fs == nil || fs.Outputs == nil {
+				continue
+			}
+			if !contains(fs.Outputs, ref.key) {
+				return errorf("%s: Output %s of phase %s is not an output of function %s", path, ref.key, ref.phase, fs.Name)
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			if err := outputRefs(fmt.Sprintf("%s[%d]", path, i), e, schemas); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			if err := outputRefs(fmt.Sprintf("%s.%s", path, k), v[k], schemas); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// outputRef is a reference to `.Phases.<phase>.Output.<key>` in a template.
+type outputRef struct {
+	phase string
+	key   string
+}
+
+// walkTemplate collects the phase output references in the parse tree of a
+// template. root is false within `range` and `with` blocks, where fields are
+// not relative to the TemplateParams.
+func walkTemplate(n parse.Node, root bool, refs *[]outputRef) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkTemplate(c, root, refs)
+		}
+	case *parse.ActionNode:
+		walkTemplate(n.Pipe, root, refs)
+	case *parse.TemplateNode:
+		walkTemplate(n.Pipe, root, refs)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			walkTemplate(c, root, refs)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			walkTemplate(a, root, refs)
+		}
+	case *parse.IfNode:
+		walkTemplate(n.Pipe, root, refs)
+		walkTemplate(n.List, root, refs)
+		walkTemplate(n.ElseList, root, refs)
+	case *parse.RangeNode:
+		walkTemplate(n.Pipe, root, refs)
+		walkTemplate(n.List, false, refs)
+		walkTemplate(n.ElseList, root, refs)
+	case *parse.WithNode:
+		walkTemplate(n.Pipe, root, refs)
+		walkTemplate(n.List, false, refs)
+		walkTemplate(n.ElseList, root, refs)
+	case *parse.FieldNode:
+		if root {
+			addOutputRef(n.Ident, refs)
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 0 && n.Ident[0] == "$" {
+			addOutputRef(n.Ident[1:], refs)
+		}
+	}
+}
+
+func addOutputRef(ident []string, refs *[]outputRef) {
+	if len(ident) >= 4 && ident[0] == "Phases" && ident[2] == "Output" {
+		*refs = append(*refs, outputRef{phase: ident[1], key: ident[3]})
+	}
+}
+
+func phaseExists(phases []crv1alpha1.BlueprintPhase, name string) bool {
+	for _, p := range phases {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedArtifactKeys(m map[string]crv1alpha1.Artifact) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/robfig/cron/v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
@@ -83,10 +84,11 @@ func actionSetStatus(as *crv1alpha1.ActionSetStatus) error {
 		return err
 	}
 	saw := map[crv1alpha1.State]bool{
-		crv1alpha1.StatePending:  false,
-		crv1alpha1.StateRunning:  false,
-		crv1alpha1.StateFailed:   false,
-		crv1alpha1.StateComplete: false,
+		crv1alpha1.StatePending:   false,
+		crv1alpha1.StateRunning:   false,
+		crv1alpha1.StateFailed:    false,
+		crv1alpha1.StateComplete:  false,
+		crv1alpha1.StateCancelled: false,
 	}
 	for _, a := range as.Actions {
 		for _, p := range a.Phases {
@@ -111,6 +113,12 @@ func actionSetStatus(as *crv1alpha1.ActionSetStatus) error {
 
 func actionSetStatusActions(as []crv1alpha1.ActionStatus) error {
 	for _, a := range as {
+		if phasesHaveDependencies(a.Phases) {
+			if err := phaseDependenciesStatus(a.Phases); err != nil {
+				return err
+			}
+			continue
+		}
 		var sawNotComplete bool
 		var lastNonComplete crv1alpha1.State
 		for _, p := range a.Phases {
@@ -126,16 +134,84 @@ func actionSetStatusActions(as []crv1alpha1.ActionStatus) error {
 	return nil
 }
 
-// Blueprint function validates the Blueprint and returns an error if it is invalid.
-func Blueprint(bp *crv1alpha1.Blueprint) error {
-	// TODO: Add blueprint validation.
+func phasesHaveDependencies(phases []crv1alpha1.Phase) bool {
+	for _, p := range phases {
+		if len(p.DependsOn) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// phaseDependenciesStatus checks that phases which are not pending only
+// started after all the phases they depend on were complete.
+func phaseDependenciesStatus(phases []crv1alpha1.Phase) error {
+	states := make(map[string]crv1alpha1.State, len(phases))
+	for _, p := range phases {
+		states[p.Name] = p.State
+	}
+	for _, p := range phases {
+		if p.State == crv1alpha1.StatePending {
+			continue
+		}
+		for _, d := range p.DependsOn {
+			if states[d] != crv1alpha1.StateComplete {
+				return errorf("Phase %s cannot be %s before the phase %s it depends on is complete", p.Name, p.State, d)
+			}
+		}
+	}
 	return nil
 }
 
+// BackupSchedule function validates the BackupSchedule and returns an error if
+// it is invalid.
+func BackupSchedule(bs *crv1alpha1.BackupSchedule) error {
+	if bs.Spec == nil {
+		return errorf("Spec must be non-nil")
+	}
+	if _, err := cron.ParseStandard(bs.Spec.Schedule); err != nil {
+		return errorf("Invalid schedule '%s': %v", bs.Spec.Schedule, err)
+	}
+	switch bs.Spec.ConcurrencyPolicy {
+	case "", crv1alpha1.ConcurrencyPolicyAllow, crv1alpha1.ConcurrencyPolicyForbid, crv1alpha1.ConcurrencyPolicyReplace:
+	default:
+		return errorf("Unknown concurrency policy '%s'", bs.Spec.ConcurrencyPolicy)
+	}
+	if l := bs.Spec.SuccessfulHistoryLimit; l != nil && *l < 0 {
+		return errorf("Successful history limit must not be negative")
+	}
+	if l := bs.Spec.FailedHistoryLimit; l != nil && *l < 0 {
+		return errorf("Failed history limit must not be negative")
+	}
+	if r := bs.Spec.Retention; r != nil {
+		if r.KeepLast < 0 || r.KeepDaily < 0 || r.KeepWeekly < 0 || r.KeepMonthly < 0 {
+			return errorf("Retention keep rules must not be negative")
+		}
+		if r.MaxAge != nil && r.MaxAge.Duration <= 0 {
+			return errorf("Retention max age must be positive")
+		}
+	}
+	return actionSetSpec(bs.Spec.ActionSetTemplate.Spec)
+}
+
 func ProfileSchema(p *crv1alpha1.Profile) error {
 	if !supported(p.Location.Type) {
 		return errorf("unknown or unsupported location type '%s'", p.Location.Type)
 	}
+	if err := validateEncryptionKey(p.EncryptionKey); err != nil {
+		return err
+	}
+	if err := validateEncryptionKey(p.RepositoryPassword); err != nil {
+		return err
+	}
+	if p.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		if p.Location.Endpoint == "" {
+			return errorf("Directory path for filesystem location not specified")
+		}
+		if p.Credential.Type == "" {
+			return nil
+		}
+	}
 	if err := validateCredentialType(&p.Credential); err != nil {
 		return err
 	}
@@ -147,6 +223,19 @@ func ProfileSchema(p *crv1alpha1.Profile) error {
 	return nil
 }
 
+func validateEncryptionKey(ek *crv1alpha1.EncryptionKey) error {
+	if ek == nil {
+		return nil
+	}
+	if ek.Secret.Name == "" {
+		return errorf("Secret for encryption key not specified")
+	}
+	if ek.KeyField == "" {
+		return errorf("Encryption key field empty")
+	}
+	return nil
+}
+
 func validateCredentialType(creds *crv1alpha1.Credential) error {
 	switch creds.Type {
 	case crv1alpha1.CredentialTypeKeyPair:
@@ -171,7 +260,12 @@ func validateCredentialType(creds *crv1alpha1.Credential) error {
 }
 
 func supported(t crv1alpha1.LocationType) bool {
-	return t == crv1alpha1.LocationTypeS3Compliant || t == crv1alpha1.LocationTypeGCS || t == crv1alpha1.LocationTypeAzure
+	switch t {
+	case crv1alpha1.LocationTypeS3Compliant, crv1alpha1.LocationTypeGCS, crv1alpha1.LocationTypeAzure, crv1alpha1.LocationTypeFilesystem:
+		return true
+	default:
+		return false
+	}
 }
 
 func ProfileBucket(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
@@ -195,10 +289,13 @@ func ProfileBucket(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.In
 		pType = objectstore.ProviderTypeGCS
 	case crv1alpha1.LocationTypeAzure:
 		pType = objectstore.ProviderTypeAzure
+	case crv1alpha1.LocationTypeFilesystem:
+		// The directory is only mounted in the pods that use the Profile.
+		return nil
 	default:
 		return errorf("unknown or unsupported location type '%s'", p.Location.Type)
 	}
-	pc := objectstore.ProviderConfig{Type: pType}
+	pc := objectstore.ProviderConfig{Type: pType, Endpoint: p.Location.Endpoint}
 	secret, err := osSecretFromProfile(ctx, pType, p, cli)
 	if err != nil {
 		return err
@@ -225,6 +322,9 @@ func ReadAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Inter
 		pType = objectstore.ProviderTypeGCS
 	case crv1alpha1.LocationTypeAzure:
 		pType = objectstore.ProviderTypeAzure
+	case crv1alpha1.LocationTypeFilesystem:
+		// The directory is only mounted in the pods that use the Profile.
+		return nil
 	default:
 		return errorf("unknown or unsupported location type '%s'", p.Location.Type)
 	}
@@ -262,6 +362,9 @@ func WriteAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Inte
 		pType = objectstore.ProviderTypeGCS
 	case crv1alpha1.LocationTypeAzure:
 		pType = objectstore.ProviderTypeAzure
+	case crv1alpha1.LocationTypeFilesystem:
+		// The directory is only mounted in the pods that use the Profile.
+		return nil
 	default:
 		return errorf("unknown or unsupported location type '%s'", p.Location.Type)
 	}
@@ -15,11 +15,13 @@
 package validate
 
 import (
+	"context"
 	"testing"
 
 	. "gopkg.in/check.v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/param"
 )
@@ -470,15 +472,350 @@ func (s *ValidateSuite) TestActionSetStatus(c *C) {
 			},
 			checker: NotNil,
 		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateCancelled,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateComplete,
+							},
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateCancelled,
+							},
+							crv1alpha1.Phase{
+								State: crv1alpha1.StatePending,
+							},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								Name:  "a",
+								State: crv1alpha1.StateRunning,
+							},
+							crv1alpha1.Phase{
+								Name:  "b",
+								State: crv1alpha1.StateComplete,
+							},
+							crv1alpha1.Phase{
+								Name:      "c",
+								State:     crv1alpha1.StateRunning,
+								DependsOn: []string{"b"},
+							},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								Name:  "a",
+								State: crv1alpha1.StateRunning,
+							},
+							crv1alpha1.Phase{
+								Name:      "b",
+								State:     crv1alpha1.StateComplete,
+								DependsOn: []string{"a"},
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
 	} {
 		err := actionSetStatus(tc.as)
 		c.Check(err, tc.checker)
 	}
 }
 
+type schemaFunc struct{}
+
+func (*schemaFunc) Name() string {
+	return "ValidateSchemaFunc"
+}
+
+func (*schemaFunc) RequiredArgs() []string {
+	return nil
+}
+
+func (*schemaFunc) Exec(context.Context, param.TemplateParams, map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (*schemaFunc) ArgTypes() map[string]kanister.ArgType {
+	return map[string]kanister.ArgType{
+		"name":     kanister.ArgTypeString,
+		"replicas": kanister.ArgTypeInt,
+		"command":  kanister.ArgTypeStringSlice,
+	}
+}
+
+func (*schemaFunc) Outputs() []string {
+	return []string{"backupID"}
+}
+
+type versionedFunc struct {
+	schemaFunc
+}
+
+func (*versionedFunc) Name() string {
+	return "ValidateVersionedFunc"
+}
+
+func (*versionedFunc) RequiredArgs() []string {
+	return []string{"namespace"}
+}
+
+func init() {
+	_ = kanister.Register(&schemaFunc{})
+	_ = kanister.RegisterVersion(&versionedFunc{}, "v1.0.0")
+}
+
 func (s *ValidateSuite) TestBlueprint(c *C) {
 	err := Blueprint(nil)
 	c.Assert(err, IsNil)
+
+	bp := func(args map[string]interface{}, arts map[string]crv1alpha1.Artifact) *crv1alpha1.Blueprint {
+		return &crv1alpha1.Blueprint{
+			Actions: map[string]*crv1alpha1.BlueprintAction{
+				"backup": {
+					OutputArtifacts: arts,
+					Phases: []crv1alpha1.BlueprintPhase{
+						{Name: "first", Func: "ValidateSchemaFunc"},
+						{Name: "second", Func: "ValidateSchemaFunc", Args: args},
+					},
+				},
+			},
+		}
+	}
+	for _, tc := range []struct {
+		args    map[string]interface{}
+		arts    map[string]crv1alpha1.Artifact
+		checker Checker
+	}{
+		{
+			args:    map[string]interface{}{"name": "{{ .Phases.first.Output.backupID }}"},
+			checker: IsNil,
+		},
+		{
+			args:    map[string]interface{}{"name": "{{ $.Phases.first.Output.backupId }}"},
+			checker: NotNil,
+		},
+		{
+			args:    map[string]interface{}{"command": []interface{}{"echo", "{{ .Phases.first.Output.backupTag }}"}},
+			checker: NotNil,
+		},
+		{
+			// Fields within `with` are not relative to the template params.
+			args:    map[string]interface{}{"name": "{{ with .Phases.first.Output }}{{ .Phases.x.Output.y }}{{ end }}"},
+			checker: IsNil,
+		},
+		{
+			args:    map[string]interface{}{"name": "{{ .Phases.third.Output.backupID }}"},
+			checker: NotNil,
+		},
+		{
+			args:    map[string]interface{}{"name": "{{ .Phases.first.Output.backupID "},
+			checker: NotNil,
+		},
+		{
+			args:    map[string]interface{}{"replicas": 2},
+			checker: IsNil,
+		},
+		{
+			args:    map[string]interface{}{"replicas": "2"},
+			checker: IsNil,
+		},
+		{
+			args:    map[string]interface{}{"replicas": "two"},
+			checker: NotNil,
+		},
+		{
+			args:    map[string]interface{}{"replicas": "{{ .Options.replicas }}"},
+			checker: IsNil,
+		},
+		{
+			args:    map[string]interface{}{"command": map[string]interface{}{"echo": "hello"}},
+			checker: NotNil,
+		},
+		{
+			arts: map[string]crv1alpha1.Artifact{
+				"snapshot": {KeyValue: map[string]string{"id": "{{ .Phases.second.Output.backupID }}"}},
+			},
+			checker: IsNil,
+		},
+		{
+			arts: map[string]crv1alpha1.Artifact{
+				"snapshot": {KeyValue: map[string]string{"id": "{{ .Phases.second.Output.snapshotID }}"}},
+			},
+			checker: NotNil,
+		},
+	} {
+		err := Blueprint(bp(tc.args, tc.arts))
+		c.Check(err, tc.checker, Commentf("%v %v", tc.args, tc.arts))
+		if err != nil {
+			c.Check(IsError(err), Equals, true)
+		}
+	}
+
+	for _, tc := range []struct {
+		phase crv1alpha1.BlueprintPhase
+		err   string
+	}{
+		{
+			phase: crv1alpha1.BlueprintPhase{Name: "third", Func: "ValidateSchemaFunk"},
+			err:   "actions.backup.phases\\[2\\].func: .*not been registered.*",
+		},
+		{
+			phase: crv1alpha1.BlueprintPhase{Name: "second", Func: "ValidateSchemaFunc"},
+			err:   "actions.backup.phases\\[2\\].name: .*not unique.*",
+		},
+		{
+			phase: crv1alpha1.BlueprintPhase{Name: "third", Func: "ValidateVersionedFunc"},
+			err:   "actions.backup.phases\\[2\\].args.namespace: Required arg is missing.*",
+		},
+		{
+			phase: crv1alpha1.BlueprintPhase{Name: "third", Func: "ValidateVersionedFunc", Args: map[string]interface{}{"namespace": "ns"}},
+		},
+		{
+			phase: crv1alpha1.BlueprintPhase{Name: "third", Func: "ValidateSchemaFunc", DependsOn: []string{"fourth"}},
+			err:   "actions.backup.phases\\[2\\].dependsOn\\[0\\]: Phase fourth does not exist.*",
+		},
+		{
+			phase: crv1alpha1.BlueprintPhase{
+				Name: "third",
+				Func: "ValidateSchemaFunc",
+				ObjectRefs: map[string]crv1alpha1.ObjectReference{
+					"cm": {Name: "{{ .Object.Name "},
+				},
+			},
+			err: "actions.backup.phases\\[2\\].objects.cm.name: Failed to parse template.*",
+		},
+	} {
+		b := bp(nil, nil)
+		b.Actions["backup"].Phases = append(b.Actions["backup"].Phases, tc.phase)
+		err := Blueprint(b)
+		if tc.err == "" {
+			c.Check(err, IsNil)
+			c.Check(BlueprintVersion(b, kanister.DefaultVersion), NotNil)
+			continue
+		}
+		c.Check(err, ErrorMatches, tc.err)
+	}
+}
+
+func (s *ValidateSuite) TestBackupSchedule(c *C) {
+	negative := int32(-1)
+	spec := func() *crv1alpha1.BackupScheduleSpec {
+		return &crv1alpha1.BackupScheduleSpec{
+			Schedule: "0 * * * *",
+			ActionSetTemplate: crv1alpha1.ActionSetTemplate{
+				Spec: &crv1alpha1.ActionSetSpec{
+					Actions: []crv1alpha1.ActionSpec{
+						{
+							Object: crv1alpha1.ObjectReference{
+								Kind: param.DeploymentKind,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	for _, tc := range []struct {
+		spec    func(*crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec
+		checker Checker
+	}{
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				return s
+			},
+			checker: IsNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.Schedule = "@every 1h"
+				s.ConcurrencyPolicy = crv1alpha1.ConcurrencyPolicyForbid
+				return s
+			},
+			checker: IsNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				return nil
+			},
+			checker: NotNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.Schedule = "every hour"
+				return s
+			},
+			checker: NotNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.ConcurrencyPolicy = "Sometimes"
+				return s
+			},
+			checker: NotNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.FailedHistoryLimit = &negative
+				return s
+			},
+			checker: NotNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.ActionSetTemplate.Spec = nil
+				return s
+			},
+			checker: NotNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.Retention = &crv1alpha1.RetentionPolicy{KeepLast: 3, KeepDaily: 7}
+				return s
+			},
+			checker: IsNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.Retention = &crv1alpha1.RetentionPolicy{KeepWeekly: -1}
+				return s
+			},
+			checker: NotNil,
+		},
+		{
+			spec: func(s *crv1alpha1.BackupScheduleSpec) *crv1alpha1.BackupScheduleSpec {
+				s.Retention = &crv1alpha1.RetentionPolicy{MaxAge: &metav1.Duration{}}
+				return s
+			},
+			checker: NotNil,
+		},
+	} {
+		err := BackupSchedule(&crv1alpha1.BackupSchedule{Spec: tc.spec(spec())})
+		c.Check(err, tc.checker)
+	}
 }
 
 func (s *ValidateSuite) TestProfileSchema(c *C) {
@@ -588,6 +925,104 @@ func (s *ValidateSuite) TestProfileSchema(c *C) {
 			},
 			checker: NotNil,
 		},
+		// Filesystem location without credentials
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+			},
+			checker: IsNil,
+		},
+		// Filesystem location without a directory
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type: crv1alpha1.LocationTypeFilesystem,
+				},
+			},
+			checker: NotNil,
+		},
+		// Encryption key in a secret
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+				EncryptionKey: &crv1alpha1.EncryptionKey{
+					KeyField: "key",
+					Secret: crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		// Encryption key without a key field
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+				EncryptionKey: &crv1alpha1.EncryptionKey{
+					Secret: crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		// Encryption key without a secret
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+				EncryptionKey: &crv1alpha1.EncryptionKey{
+					KeyField: "key",
+				},
+			},
+			checker: NotNil,
+		},
+		// Valid repository password
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+				RepositoryPassword: &crv1alpha1.EncryptionKey{
+					KeyField: "password",
+					Secret: crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		// Repository password without a key field
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeFilesystem,
+					Endpoint: "/mnt/backups",
+				},
+				RepositoryPassword: &crv1alpha1.EncryptionKey{
+					Secret: crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: NotNil,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -595,3 +1030,17 @@ func (s *ValidateSuite) TestProfileSchema(c *C) {
 		c.Check(err, tc.checker)
 	}
 }
+
+func (s *ValidateSuite) TestFilesystemProfileAccess(c *C) {
+	// The directory is not mounted where the Profile is validated.
+	p := &crv1alpha1.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Endpoint: "/nonexistent/backups",
+		},
+	}
+	ctx := context.Background()
+	c.Check(ProfileBucket(ctx, p, nil), IsNil)
+	c.Check(ReadAccess(ctx, p, nil), IsNil)
+	c.Check(WriteAccess(ctx, p, nil), IsNil)
+}
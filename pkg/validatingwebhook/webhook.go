@@ -0,0 +1,95 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validatingwebhook implements the admission webhook that rejects
+// invalid Blueprints when they are created or updated.
+package validatingwebhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+var _ http.Handler = (*blueprintHandler)(nil)
+
+type blueprintHandler struct{}
+
+// NewBlueprintHandler returns a handler that serves AdmissionReview requests
+// for Blueprints and only allows the Blueprints that pass validate.Blueprint.
+func NewBlueprintHandler() http.Handler {
+	return &blueprintHandler{}
+}
+
+func (*blueprintHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &v1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to decode AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "AdmissionReview does not contain a request", http.StatusBadRequest)
+		return
+	}
+	review.Response = reviewBlueprint(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+	js, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
+func reviewBlueprint(req *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	bp := &crv1alpha1.Blueprint{}
+	if err := json.Unmarshal(req.Object.Raw, bp); err != nil {
+		return deny(errors.Wrap(err, "Failed to decode Blueprint"))
+	}
+	if err := validate.Blueprint(bp); err != nil {
+		log.Print("Rejected invalid Blueprint", field.M{"Namespace": req.Namespace, "Name": req.Name, "Error": err.Error()})
+		return deny(err)
+	}
+	return &v1beta1.AdmissionResponse{Allowed: true}
+}
+
+func deny(err error) *v1beta1.AdmissionResponse {
+	return &v1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
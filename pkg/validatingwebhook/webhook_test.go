@@ -0,0 +1,97 @@
+// Copyright 2019 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatingwebhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "gopkg.in/check.v1"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	_ "github.com/kanisterio/kanister/pkg/function"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type WebhookSuite struct{}
+
+var _ = Suite(&WebhookSuite{})
+
+func (s *WebhookSuite) TestBlueprintHandler(c *C) {
+	for _, tc := range []struct {
+		fn      string
+		allowed bool
+		message string
+	}{
+		{
+			fn:      "ScaleWorkload",
+			allowed: true,
+		},
+		{
+			fn:      "ScaleWorkloads",
+			allowed: false,
+			message: "actions.backup.phases\\[0\\].func: .*ScaleWorkloads.*",
+		},
+	} {
+		bp := &crv1alpha1.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{Name: "bp"},
+			Actions: map[string]*crv1alpha1.BlueprintAction{
+				"backup": {
+					Phases: []crv1alpha1.BlueprintPhase{
+						{
+							Name: "scale",
+							Func: tc.fn,
+							Args: map[string]interface{}{"replicas": 0},
+						},
+					},
+				},
+			},
+		}
+		raw, err := json.Marshal(bp)
+		c.Assert(err, IsNil)
+		body, err := json.Marshal(&v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				UID:    types.UID("uid"),
+				Object: runtime.RawExtension{Raw: raw},
+			},
+		})
+		c.Assert(err, IsNil)
+
+		rec := httptest.NewRecorder()
+		NewBlueprintHandler().ServeHTTP(rec, httptest.NewRequest("POST", "/", bytes.NewReader(body)))
+		c.Assert(rec.Code, Equals, http.StatusOK)
+		review := &v1beta1.AdmissionReview{}
+		c.Assert(json.Unmarshal(rec.Body.Bytes(), review), IsNil)
+		c.Assert(review.Response, NotNil)
+		c.Assert(review.Response.UID, Equals, types.UID("uid"))
+		c.Assert(review.Response.Allowed, Equals, tc.allowed)
+		if !tc.allowed {
+			c.Assert(review.Response.Result.Message, Matches, tc.message)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewBlueprintHandler().ServeHTTP(rec, httptest.NewRequest("POST", "/", bytes.NewReader([]byte("{}"))))
+	c.Assert(rec.Code, Equals, http.StatusBadRequest)
+}